@@ -2,6 +2,8 @@
 package boxcutter
 
 import (
+	"reflect"
+
 	"k8s.io/apimachinery/pkg/api/meta"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/client-go/discovery"
@@ -99,6 +101,13 @@ type WithCollisionProtection = types.WithCollisionProtection
 // Can also be described as dry-run, as no modification will occur.
 type WithPaused = types.WithPaused
 
+// WithShared marks objects as shared between multiple owners.
+// Objects are only deleted when the last owner releases them.
+var WithShared = types.WithShared
+
+// WithHandoverTo hands objects over to the given new owner instead of deleting them.
+var WithHandoverTo = types.WithHandoverTo
+
 // WithAggregatePhaseReconcileErrors causes phase reconciliation to aggregate all object
 // errors as a single error instead of returning on the first error.
 var WithAggregatePhaseReconcileErrors = types.WithAggregatePhaseReconcileErrors
@@ -116,6 +125,25 @@ var ProbeFunc = types.ProbeFunc
 // WithProbe registers the given probe to evaluate state of objects.
 var WithProbe = types.WithProbe
 
+// ProbeState records the status of a probe over multiple reconciliations.
+type ProbeState = types.ProbeState
+
+// ProbeStateStore records probe states of objects across reconciliations.
+type ProbeStateStore = types.ProbeStateStore
+
+// WithProbeStateStore records probe status transitions in the given store.
+var WithProbeStateStore = types.WithProbeStateStore
+
+// NewInMemoryProbeStateStore returns a probe state store keeping states in memory.
+var NewInMemoryProbeStateStore = machinery.NewInMemoryProbeStateStore
+
+// NewAnnotationProbeStateStore returns a probe state store
+// persisting states in an annotation on each object.
+var NewAnnotationProbeStateStore = machinery.NewAnnotationProbeStateStore
+
+// WithTeardownProbe registers a probe that has to succeed before a deleted object is reported gone.
+var WithTeardownProbe = types.WithTeardownProbe
+
 // WithObjectReconcileOptions applies the given options only to the given object.
 var WithObjectReconcileOptions = types.WithObjectReconcileOptions
 
@@ -164,7 +192,23 @@ type RevisionEngineOptions struct {
 	// it should not be cached, as caching it would negate the benefits of cache
 	// filtering.
 	UnfilteredReader client.Reader
-	PhaseValidator   *validation.PhaseValidator
+	// PhaseValidator runs preflight checks before a phase is reconciled.
+	// Defaults to validation.NewNamespacedPhaseValidator when nil, including typed nil pointers,
+	// use validation.NewClusterPhaseValidator for cluster-scoped deployments.
+	PhaseValidator validation.PhaseCheck
+	// RevisionValidator runs checks before any phase of a revision is reconciled.
+	// Defaults to validation.NewRevisionValidator when nil, including typed nil pointers.
+	RevisionValidator validation.RevisionCheck
+	// AdditionalPhaseValidators run after PhaseValidator,
+	// their validation errors are merged.
+	AdditionalPhaseValidators []validation.PhaseCheck
+	// AdditionalRevisionValidators run after RevisionValidator,
+	// their validation errors are merged.
+	AdditionalRevisionValidators []validation.RevisionCheck
+	// Policies are checked for every object during revision validation
+	// and phase preflight. Can't be combined with PhaseValidator or RevisionValidator,
+	// pass policies to injected validators via validation.WithPolicies instead.
+	Policies []validation.Policy
 }
 
 // NewPhaseEngine  returns a new PhaseEngine instance.
@@ -173,10 +217,6 @@ func NewPhaseEngine(opts RevisionEngineOptions) (*machinery.PhaseEngine, error)
 		return nil, err
 	}
 
-	if opts.PhaseValidator == nil {
-		opts.PhaseValidator = validation.NewNamespacedPhaseValidator(opts.RestMapper, opts.Writer)
-	}
-
 	comp := machinery.NewComparator(
 		opts.DiscoveryClient, opts.Scheme, opts.FieldOwner)
 
@@ -186,7 +226,7 @@ func NewPhaseEngine(opts RevisionEngineOptions) (*machinery.PhaseEngine, error)
 		opts.ManagedBy, opts.UnfilteredReader,
 	)
 
-	return machinery.NewPhaseEngine(oe, opts.PhaseValidator), nil
+	return machinery.NewPhaseEngine(oe, phaseValidator(opts)), nil
 }
 
 // NewRevisionEngine returns a new RevisionEngine instance.
@@ -195,9 +235,6 @@ func NewRevisionEngine(opts RevisionEngineOptions) (*RevisionEngine, error) {
 		return nil, err
 	}
 
-	pval := validation.NewNamespacedPhaseValidator(opts.RestMapper, opts.Writer)
-	rval := validation.NewRevisionValidator()
-
 	comp := machinery.NewComparator(
 		opts.DiscoveryClient, opts.Scheme, opts.FieldOwner)
 
@@ -206,9 +243,50 @@ func NewRevisionEngine(opts RevisionEngineOptions) (*RevisionEngine, error) {
 		comp, opts.FieldOwner, opts.SystemPrefix,
 		opts.ManagedBy, opts.UnfilteredReader,
 	)
-	pe := machinery.NewPhaseEngine(oe, pval)
+	pe := machinery.NewPhaseEngine(oe, phaseValidator(opts))
 
-	return machinery.NewRevisionEngine(pe, rval, opts.Writer), nil
+	return machinery.NewRevisionEngine(pe, revisionValidator(opts), opts.Writer), nil
+}
+
+func phaseValidator(opts RevisionEngineOptions) validation.PhaseCheck {
+	pval := opts.PhaseValidator
+	if isNil(pval) {
+		pval = validation.NewNamespacedPhaseValidator(
+			opts.RestMapper, opts.Writer, validation.WithPolicies(opts.Policies))
+	}
+
+	if len(opts.AdditionalPhaseValidators) == 0 {
+		return pval
+	}
+
+	return validation.ChainPhaseChecks(
+		append([]validation.PhaseCheck{pval}, opts.AdditionalPhaseValidators...)...)
+}
+
+func revisionValidator(opts RevisionEngineOptions) validation.RevisionCheck {
+	rval := opts.RevisionValidator
+	if isNil(rval) {
+		rval = validation.NewRevisionValidator(validation.WithPolicies(opts.Policies))
+	}
+
+	if len(opts.AdditionalRevisionValidators) == 0 {
+		return rval
+	}
+
+	return validation.ChainRevisionChecks(
+		append([]validation.RevisionCheck{rval}, opts.AdditionalRevisionValidators...)...)
+}
+
+// isNil returns true if v is nil or an interface holding a nil pointer,
+// e.g. a nil *validation.PhaseValidator assigned to PhaseValidator.
+func isNil(v any) bool {
+	if v == nil {
+		return true
+	}
+
+	rv := reflect.ValueOf(v)
+
+	return rv.Kind() == reflect.Pointer && rv.IsNil()
 }
 
 // RevisionEngineOptionsError is returned for errors with the RevisionEngineOptions.
@@ -249,5 +327,11 @@ func validateRevisionEngineOpts(opts RevisionEngineOptions) error {
 		return RevisionEngineOptionsError{msg: "reader must be provided"}
 	}
 
+	if len(opts.Policies) > 0 && (!isNil(opts.PhaseValidator) || !isNil(opts.RevisionValidator)) {
+		return RevisionEngineOptionsError{
+			msg: "policies can't be combined with phaseValidator or revisionValidator, use validation.WithPolicies",
+		}
+	}
+
 	return nil
 }
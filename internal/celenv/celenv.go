@@ -0,0 +1,33 @@
+// Package celenv contains the common expression language environment
+// shared by CEL probes and validation policies.
+package celenv
+
+import (
+	"github.com/google/cel-go/cel"
+	"github.com/google/cel-go/ext"
+	"k8s.io/apiserver/pkg/cel/library"
+)
+
+// New returns a new CEL environment with the libraries available
+// in kube-apiserver validation rules, extended by the given options,
+// e.g. cel.Variable declarations.
+func New(opts ...cel.EnvOption) (*cel.Env, error) {
+	return cel.NewEnv(append([]cel.EnvOption{
+		cel.HomogeneousAggregateLiterals(),
+		cel.EagerlyValidateDeclarations(true),
+		cel.DefaultUTCTimeZone(true),
+
+		ext.Strings(ext.StringsVersion(0)),
+		library.URLs(),
+		library.Regex(),
+		library.Lists(),
+	}, opts...)...)
+}
+
+// Program returns a program for the given ast, limited to costLimit per evaluation.
+func Program(env *cel.Env, ast *cel.Ast, costLimit uint64) (cel.Program, error) {
+	return env.Program(ast,
+		cel.CostTracking(&library.CostEstimator{}),
+		cel.CostLimit(costLimit),
+	)
+}
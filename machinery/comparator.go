@@ -225,6 +225,11 @@ func (d *Comparator) Compare(
 
 	ss.CopyInto(&parser.Schema)
 
+	fieldOwner := d.fieldOwner
+	if len(options.FieldOwner) > 0 {
+		fieldOwner = options.FieldOwner
+	}
+
 	// Extrapolate a field set from desired.
 	desiredObject = desiredObject.DeepCopyObject().(Object)
 	if options.Owner != nil {
@@ -253,7 +258,7 @@ func (d *Comparator) Compare(
 	res.DesiredFieldSet = res.DesiredFieldSet.Difference(localStripSet)
 
 	// Get "our" managed fields on actual.
-	mf, ok := findManagedFields(d.fieldOwner, actualObject)
+	mf, ok := findManagedFields(fieldOwner, actualObject)
 	if !ok {
 		// not a single managed field from "us" -> diverged for sure
 		for _, mf := range actualObject.GetManagedFields() {
@@ -288,7 +293,7 @@ func (d *Comparator) Compare(
 
 	// Index diff into something more useful for the caller.
 	for _, mf := range actualObject.GetManagedFields() {
-		if mf.Manager == d.fieldOwner {
+		if mf.Manager == fieldOwner {
 			continue
 		}
 
@@ -1,11 +1,39 @@
 package machinery
 
 import (
+	"errors"
 	"fmt"
 
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	machinerytypes "k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
+var (
+	// ErrSharedWithoutOwner is returned when shared objects are reconciled without owner.
+	ErrSharedWithoutOwner = errors.New("shared objects require an owner")
+	// ErrSharedOwnerStrategyUnsupported is returned when the OwnerStrategy
+	// does not support multiple non-controller owners.
+	ErrSharedOwnerStrategyUnsupported = errors.New("owner strategy does not support shared objects")
+	// ErrHandoverWithoutOwner is returned when objects are handed over without owner.
+	ErrHandoverWithoutOwner = errors.New("handover requires an owner")
+)
+
+// HandoverError is returned when an object marked for handover
+// was adopted by someone else than the new owner.
+type HandoverError struct {
+	// Controller is the reference to the current controller of the object.
+	Controller metav1.OwnerReference
+	// HandoverTo is the UID of the intended new owner.
+	HandoverTo machinerytypes.UID
+}
+
+// Error implements the error interface.
+func (e HandoverError) Error() string {
+	return fmt.Sprintf("object handed over to %s, but controlled by %s %s (%s)",
+		e.HandoverTo, e.Controller.Kind, e.Controller.Name, e.Controller.UID)
+}
+
 // CreateCollisionError is returned when boxcutter tries to create an object,
 // but it already exists. \
 // This happens when another actor has created the object and caches are slow,
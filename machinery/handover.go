@@ -0,0 +1,93 @@
+package machinery
+
+import (
+	"context"
+	"fmt"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"pkg.package-operator.run/boxcutter/machinery/types"
+)
+
+// Handover moves objects between unrelated owners:
+// The releasing owner annotates its objects with the UID of the new owner during teardown.
+// The receiving owner adopts objects carrying its UID, regardless of CollisionProtection.
+// After adoption the releasing owner removes its owner reference and the annotation.
+
+// releaseHandover marks actualObject to be handed over to the owner with UID options.HandoverTo.
+// Returns true when the object has been adopted by the new owner and is released.
+// Returns a HandoverError when the object is controlled by anyone else.
+func (e *ObjectEngine) releaseHandover(
+	ctx context.Context,
+	actualObject Object,
+	options types.ObjectTeardownOptions,
+) (released bool, err error) {
+	if options.Owner == nil {
+		return false, ErrHandoverWithoutOwner
+	}
+
+	ctrlSit, ctrl := e.detectOwner(options.Owner, options.OwnerStrategy, actualObject, nil)
+
+	switch {
+	case ctrlSit == ctrlSituationNoController:
+		// Waiting for the new owner to adopt the object.
+		return false, nil
+
+	case ctrlSit != ctrlSituationIsController && ctrl.UID != options.HandoverTo:
+		return false, HandoverError{Controller: *ctrl, HandoverTo: options.HandoverTo}
+
+	case ctrlSit != ctrlSituationIsController:
+		// New owner took over.
+		// Remove us from owners list and cleanup the handover annotation.
+		patch := actualObject.DeepCopyObject().(Object)
+		options.OwnerStrategy.RemoveOwner(options.Owner, patch)
+
+		a := patch.GetAnnotations()
+		delete(a, e.handoverAnnotation())
+		patch.SetAnnotations(a)
+
+		if err := e.writer.Patch(ctx, patch, client.MergeFrom(actualObject)); err != nil {
+			return false, fmt.Errorf("removing owner after handover: %w", err)
+		}
+
+		return true, nil
+	}
+
+	if actualObject.GetAnnotations()[e.handoverAnnotation()] == string(options.HandoverTo) {
+		// Waiting for the new owner to adopt the object.
+		return false, nil
+	}
+
+	patch := actualObject.DeepCopyObject().(Object)
+
+	a := patch.GetAnnotations()
+	if a == nil {
+		a = map[string]string{}
+	}
+
+	a[e.handoverAnnotation()] = string(options.HandoverTo)
+	patch.SetAnnotations(a)
+
+	if err := e.writer.Patch(ctx, patch, client.MergeFromWithOptions(
+		actualObject, client.MergeFromWithOptimisticLock{},
+	)); err != nil {
+		return false, fmt.Errorf("marking object for handover: %w", err)
+	}
+
+	return false, nil
+}
+
+// isHandedOverTo returns true if obj has been marked for handover to the owner given in options.
+func (e *ObjectEngine) isHandedOverTo(obj Object, options types.ObjectReconcileOptions) bool {
+	if options.Owner == nil || len(options.Owner.GetUID()) == 0 {
+		return false
+	}
+
+	uid, ok := obj.GetAnnotations()[e.handoverAnnotation()]
+
+	return ok && uid == string(options.Owner.GetUID())
+}
+
+func (e *ObjectEngine) handoverAnnotation() string {
+	return e.systemPrefix + "/handover-to"
+}
@@ -0,0 +1,171 @@
+package machinery
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"pkg.package-operator.run/boxcutter/machinery/types"
+)
+
+func TestObjectEngine_Handover(t *testing.T) {
+	t.Parallel()
+
+	const handoverAnnotation = testSystemPrefix + "/handover-to"
+
+	withHandoverTo := func(uid string) func(obj *unstructured.Unstructured, _ *ownerMode) {
+		return func(obj *unstructured.Unstructured, _ *ownerMode) {
+			obj.SetAnnotations(map[string]string{handoverAnnotation: uid})
+		}
+	}
+	withTestController := withOwnerRef("v1", "ConfigMap", "owner", "12345-678", true)
+	withOtherController := withOwnerRef("v1", "ConfigMap", "other", "other-uid", true)
+
+	teardownOpts := []types.ObjectTeardownOption{
+		types.WithOwner(testOwner, testOwnerStrategy), types.WithHandoverTo("new-uid"),
+	}
+
+	t.Run("release marks object", func(t *testing.T) {
+		t.Parallel()
+
+		oe, writer, _ := newTestObjectEngine(buildObj("testi", "test", withTestController)(nil))
+
+		writer.
+			On("Patch", mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+			Return(nil)
+
+		gone, err := oe.Teardown(t.Context(), 1, buildObj("testi", "test")(nil), teardownOpts...)
+		require.NoError(t, err)
+		assert.False(t, gone)
+
+		patched := writer.Calls[0].Arguments.Get(1).(*unstructured.Unstructured)
+		assert.Equal(t, "new-uid", patched.GetAnnotations()[handoverAnnotation])
+		writer.AssertNotCalled(t, "Delete", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("release waits for adoption", func(t *testing.T) {
+		t.Parallel()
+
+		oe, writer, _ := newTestObjectEngine(buildObj("testi", "test",
+			withTestController, withHandoverTo("new-uid"))(nil))
+
+		gone, err := oe.Teardown(t.Context(), 1, buildObj("testi", "test")(nil), teardownOpts...)
+		require.NoError(t, err)
+		assert.False(t, gone)
+
+		writer.AssertNotCalled(t, "Patch", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+		writer.AssertNotCalled(t, "Delete", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("release after adoption", func(t *testing.T) {
+		t.Parallel()
+
+		oe, writer, _ := newTestObjectEngine(buildObj("testi", "test",
+			withOwnerRef("v1", "ConfigMap", "owner", "12345-678", false),
+			withOwnerRef("v1", "ConfigMap", "new", "new-uid", true),
+			withHandoverTo("new-uid"))(nil))
+
+		writer.
+			On("Patch", mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+			Return(nil)
+
+		gone, err := oe.Teardown(t.Context(), 1, buildObj("testi", "test")(nil), teardownOpts...)
+		require.NoError(t, err)
+		assert.True(t, gone)
+
+		patched := writer.Calls[0].Arguments.Get(1).(*unstructured.Unstructured)
+		assert.NotContains(t, patched.GetAnnotations(), handoverAnnotation)
+		require.Len(t, patched.GetOwnerReferences(), 1)
+		assert.Equal(t, "new", patched.GetOwnerReferences()[0].Name)
+	})
+
+	t.Run("release waits without controller", func(t *testing.T) {
+		t.Parallel()
+
+		oe, writer, _ := newTestObjectEngine(buildObj("testi", "test",
+			withOwnerRef("v1", "ConfigMap", "owner", "12345-678", false),
+			withHandoverTo("new-uid"))(nil))
+
+		gone, err := oe.Teardown(t.Context(), 1, buildObj("testi", "test")(nil), teardownOpts...)
+		require.NoError(t, err)
+		assert.False(t, gone)
+
+		writer.AssertNotCalled(t, "Patch", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+		writer.AssertNotCalled(t, "Delete", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("release adopted by someone else", func(t *testing.T) {
+		t.Parallel()
+
+		oe, writer, _ := newTestObjectEngine(buildObj("testi", "test",
+			withOwnerRef("v1", "ConfigMap", "owner", "12345-678", false),
+			withOtherController,
+			withHandoverTo("new-uid"))(nil))
+
+		gone, err := oe.Teardown(t.Context(), 1, buildObj("testi", "test")(nil), teardownOpts...)
+		require.EqualError(t, err,
+			"object handed over to new-uid, but controlled by ConfigMap other (other-uid)")
+		assert.False(t, gone)
+
+		var herr HandoverError
+		require.ErrorAs(t, err, &herr)
+		assert.Equal(t, "other", herr.Controller.Name)
+		writer.AssertNotCalled(t, "Patch", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("release without owner", func(t *testing.T) {
+		t.Parallel()
+
+		oe, _, _ := newTestObjectEngine(buildObj("testi", "test", withTestController)(nil))
+
+		_, err := oe.Teardown(t.Context(), 1, buildObj("testi", "test")(nil), types.WithHandoverTo("new-uid"))
+		require.ErrorIs(t, err, ErrHandoverWithoutOwner)
+	})
+
+	reconcileOpts := []types.ObjectReconcileOption{
+		types.WithOwner(testOwner, testOwnerStrategy),
+	}
+
+	t.Run("adopt", func(t *testing.T) {
+		t.Parallel()
+
+		oe, writer, ddm := newTestObjectEngine(buildObj("testi", "test",
+			withOtherController, withHandoverTo("12345-678"))(nil))
+
+		ddm.
+			On("Compare", mock.Anything, mock.Anything, mock.Anything).
+			Return(CompareResult{}, nil)
+		writer.
+			On("Apply", mock.Anything, mock.Anything, mock.Anything).
+			Return(nil)
+
+		res, err := oe.Reconcile(t.Context(), 1, buildObj("testi", "test")(nil), reconcileOpts...)
+		require.NoError(t, err)
+		require.Equal(t, ActionHandover, res.Action())
+
+		handover := res.(ObjectResultHandover)
+		assert.Equal(t, "other", handover.PreviousOwner().Name)
+		writer.AssertCalled(t, "Apply", mock.Anything, mock.Anything,
+			[]client.ApplyOption{client.FieldOwner(testFieldOwner), client.ForceOwnership})
+	})
+
+	t.Run("handed over to someone else", func(t *testing.T) {
+		t.Parallel()
+
+		oe, writer, ddm := newTestObjectEngine(buildObj("testi", "test",
+			withOtherController, withHandoverTo("new-uid"))(nil))
+
+		ddm.
+			On("Compare", mock.Anything, mock.Anything, mock.Anything).
+			Return(CompareResult{}, nil)
+
+		res, err := oe.Reconcile(t.Context(), 1, buildObj("testi", "test")(nil), reconcileOpts...)
+		require.NoError(t, err)
+		assert.Equal(t, ActionCollision, res.Action())
+		writer.AssertNotCalled(t, "Apply", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"strconv"
+	"time"
 
 	"github.com/go-logr/logr"
 	"k8s.io/apimachinery/pkg/api/errors"
@@ -11,9 +12,12 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	machinerytypes "k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/client-go/util/csaupgrade"
+	"k8s.io/utils/clock"
+	"k8s.io/utils/lru"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 
@@ -38,6 +42,16 @@ type ObjectEngine struct {
 	// but it explicitly MUST NOT be used to read objects which will eventually
 	// be available in the cache.
 	unfilteredReader client.Reader // may be nil
+
+	// probeStateStore is used when no store is given via options.
+	probeStateStore types.ProbeStateStore // may be nil
+	clock           clock.PassiveClock
+
+	// teardownLastSeen remembers the last state of objects deleted during teardown by UID,
+	// to evaluate teardown probes after the object is gone.
+	teardownLastSeen *lru.Cache // may be nil
+	// teardownDeleting maps GVK and name of objects deleted during teardown to their UID.
+	teardownDeleting *lru.Cache // may be nil, if teardownLastSeen is nil
 }
 
 // NewObjectEngine returns a new Engine instance.
@@ -67,6 +81,12 @@ func NewObjectEngine(
 		fieldOwner:   fieldOwner,
 		systemPrefix: systemPrefix,
 		managedBy:    managedBy,
+
+		probeStateStore: NewInMemoryProbeStateStore(DefaultInMemoryProbeStateStoreSize),
+		clock:           clock.RealClock{},
+
+		teardownLastSeen: lru.New(teardownLastSeenCacheSize),
+		teardownDeleting: lru.New(teardownLastSeenCacheSize),
 	}
 }
 
@@ -96,6 +116,8 @@ const (
 	managedByLabel             string = "app.kubernetes.io/managed-by"
 	managedByLabelDefaultValue string = "boxcutter"
 	boxcutterManagedLabel      string = "boxcutter-managed"
+
+	teardownLastSeenCacheSize = 1024
 )
 
 // Teardown ensures the given object is safely removed from the cluster.
@@ -139,19 +161,28 @@ func (e *ObjectEngine) Teardown(
 	if meta.IsNoMatchError(err) {
 		// API no longer registered.
 		// Consider the object deleted.
-		return true, nil
+		return e.teardownProbesSucceeded(desiredObject, options)
 	}
 
 	if errors.IsNotFound(err) {
 		// Object is gone, yay!
-		return true, nil
+		return e.teardownProbesSucceeded(desiredObject, options)
 	}
 
 	if err != nil {
 		return false, fmt.Errorf("getting object before deletion: %w", err)
 	}
 
-	if options.Owner != nil {
+	if options.Shared {
+		// Only delete when no other owner is left.
+		released, err := e.releaseShared(ctx, desiredObject, actualObject, options)
+		if err != nil || released {
+			return released, err
+		}
+	} else if len(options.HandoverTo) > 0 {
+		// Never delete objects that are handed over to another owner.
+		return e.releaseHandover(ctx, actualObject, options)
+	} else if options.Owner != nil {
 		// Check ownership instead of revision to determine if we should delete.
 		// If we're not the controller, only remove our owner ref and leave the object in place.
 		// A possible reason for this could be an orphaning deletion.
@@ -176,6 +207,11 @@ func (e *ObjectEngine) Teardown(
 		}
 	}
 
+	// Remember the state of objects we delete for teardown probes.
+	if err := e.rememberTeardownLastSeen(actualObject, options); err != nil {
+		return false, err
+	}
+
 	// Actually delete the object.
 	writer := e.writer
 	if options.TeardownWriter != nil {
@@ -187,7 +223,7 @@ func (e *ObjectEngine) Teardown(
 		ResourceVersion: new(actualObject.GetResourceVersion()),
 	})
 	if errors.IsNotFound(err) {
-		return true, nil
+		return e.teardownProbesSucceeded(desiredObject, options)
 	}
 	// TODO: Catch Precondition errors?
 	if err != nil {
@@ -197,6 +233,91 @@ func (e *ObjectEngine) Teardown(
 	return false, nil
 }
 
+type teardownDeletingKey struct {
+	gvk schema.GroupVersionKind
+	key client.ObjectKey
+}
+
+func (e *ObjectEngine) teardownDeletingKey(obj Object) (teardownDeletingKey, error) {
+	if err := ensureGVKIsSet(obj, e.scheme); err != nil {
+		return teardownDeletingKey{}, err
+	}
+
+	return teardownDeletingKey{
+		gvk: obj.GetObjectKind().GroupVersionKind(),
+		key: client.ObjectKeyFromObject(obj),
+	}, nil
+}
+
+// rememberTeardownLastSeen stores the given object state by UID,
+// when teardown probes need to be evaluated after the object is gone.
+// Must only be called for objects the engine is about to delete.
+func (e *ObjectEngine) rememberTeardownLastSeen(
+	actualObject Object, options types.ObjectTeardownOptions,
+) error {
+	if len(options.TeardownProbes) == 0 || e.teardownLastSeen == nil {
+		return nil
+	}
+
+	key, err := e.teardownDeletingKey(actualObject)
+	if err != nil {
+		return err
+	}
+
+	// Drop the state of a previous object with the same name.
+	if uid, ok := e.teardownDeleting.Get(key); ok && uid != actualObject.GetUID() {
+		e.teardownLastSeen.Remove(uid)
+	}
+
+	e.teardownDeleting.Add(key, actualObject.GetUID())
+	e.teardownLastSeen.Add(actualObject.GetUID(), actualObject.DeepCopyObject())
+
+	return nil
+}
+
+// teardownProbesSucceeded evaluates teardown probes against the last seen state
+// of the deleted object, falling back to the desired object when never observed.
+func (e *ObjectEngine) teardownProbesSucceeded(
+	desiredObject Object, options types.ObjectTeardownOptions,
+) (bool, error) {
+	if len(options.TeardownProbes) == 0 {
+		return true, nil
+	}
+
+	key, err := e.teardownDeletingKey(desiredObject)
+	if err != nil {
+		return false, err
+	}
+
+	var (
+		obj client.Object = desiredObject
+		uid any
+	)
+
+	if e.teardownLastSeen != nil {
+		if u, ok := e.teardownDeleting.Get(key); ok {
+			uid = u
+			if lastSeen, ok := e.teardownLastSeen.Get(uid); ok {
+				obj = lastSeen.(Object)
+			}
+		}
+	}
+
+	for _, probe := range options.TeardownProbes {
+		if r := probe.Probe(obj); r.Status != types.ProbeStatusTrue {
+			return false, nil
+		}
+	}
+
+	// Deletion completed.
+	if uid != nil {
+		e.teardownDeleting.Remove(key)
+		e.teardownLastSeen.Remove(uid)
+	}
+
+	return true, nil
+}
+
 // Reconcile runs actions to bring actual state closer to desired.
 func (e *ObjectEngine) Reconcile(
 	ctx context.Context,
@@ -209,6 +330,25 @@ func (e *ObjectEngine) Reconcile(
 		opt.ApplyToObjectReconcileOptions(&options)
 	}
 
+	res, err := e.reconcile(ctx, revision, desiredObject, options)
+	if err != nil {
+		return nil, err
+	}
+
+	states, err := e.recordProbeStates(ctx, res, options)
+	if err != nil {
+		return nil, fmt.Errorf("recording probe states: %w", err)
+	}
+
+	return withProbeStates(res, states), nil
+}
+
+func (e *ObjectEngine) reconcile(
+	ctx context.Context,
+	revision int64,
+	desiredObject Object,
+	options types.ObjectReconcileOptions,
+) (ObjectResult, error) {
 	labels := desiredObject.GetLabels()
 	if labels == nil {
 		labels = map[string]string{}
@@ -230,6 +370,11 @@ func (e *ObjectEngine) Reconcile(
 
 	// Copy because some client actions will modify the object.
 	desiredObject = desiredObject.DeepCopyObject().(Object)
+
+	if options.Shared {
+		return e.reconcileShared(ctx, desiredObject, options)
+	}
+
 	e.setObjectRevision(desiredObject, revision)
 
 	if options.Owner != nil {
@@ -302,6 +447,73 @@ func (e *ObjectEngine) Reconcile(
 	)
 }
 
+// recordProbeStates updates the probe states of the reconciled object with
+// the latest probe results. While paused, states are not stored.
+// States of colliding objects and objects not controlled by us are never recorded.
+func (e *ObjectEngine) recordProbeStates(
+	ctx context.Context, res ObjectResult,
+	options types.ObjectReconcileOptions,
+) (types.ProbeStateContainer, error) {
+	store := options.ProbeStateStore
+	if store == nil {
+		store = e.probeStateStore
+	}
+
+	if store == nil || len(options.Probes) == 0 {
+		return nil, nil
+	}
+
+	if _, ok := res.(ObjectResultCollision); ok {
+		// Object belongs to someone else.
+		return nil, nil
+	}
+
+	obj := res.Object()
+	if !e.isControlled(obj, options) {
+		return nil, nil
+	}
+
+	var now time.Time
+	if e.clock != nil {
+		now = e.clock.Now()
+	} else {
+		now = time.Now()
+	}
+
+	previous, err := store.Load(ctx, obj)
+	if err != nil {
+		return nil, err
+	}
+
+	states := types.UpdateProbeStates(previous, res.ProbeResults(), now)
+	if options.Paused {
+		return states, nil
+	}
+
+	if err := store.Store(ctx, obj, states); err != nil {
+		return nil, err
+	}
+
+	return states, nil
+}
+
+// isControlled returns true if obj is controlled by options.Owner,
+// owned by options.Owner for shared objects,
+// or is managed by boxcutter when no owner is set.
+func (e *ObjectEngine) isControlled(obj Object, options types.ObjectReconcileOptions) bool {
+	if options.Owner == nil {
+		return e.isBoxcutterManaged(obj)
+	}
+
+	if options.Shared {
+		s, ok := options.OwnerStrategy.(sharedOwnerStrategy)
+
+		return ok && s.IsOwner(options.Owner, obj)
+	}
+
+	return options.OwnerStrategy.IsController(options.Owner, obj)
+}
+
 func (e *ObjectEngine) checkSituation(
 	desiredObject Object,
 	actualObject Object,
@@ -363,9 +575,21 @@ func (e *ObjectEngine) objectUpdateHandling(
 		return nil, fmt.Errorf("getting revision of object: %w", err)
 	}
 
+	collision, _ := types.CheckCollision(actualObject, revision, e.systemPrefix, options)
+
 	switch ctrlSit {
 	case ctrlSituationUnknownController:
-		if options.CollisionProtection != types.CollisionProtectionNone {
+		if e.isHandedOverTo(actualObject, options) {
+			// The current controller is releasing this object to us.
+			res, err := e.objectAdoptionHandling(ctx, compareRes, revision, desiredObject, actualObject, options)
+			if err != nil {
+				return nil, err
+			}
+
+			return newObjectResultHandover(res.Object(), compareRes, actualOwner, options), nil
+		}
+
+		if collision {
 			return newObjectResultConflict(
 				actualObject, compareRes,
 				actualOwner, options,
@@ -378,8 +602,7 @@ func (e *ObjectEngine) objectUpdateHandling(
 		// If the object has no controller, but there are system annotations or labels present,
 		// the object might have been just orphaned, if we re-adopt it now, it would get deleted
 		// by the kubernetes garbage collector.
-		if options.CollisionProtection == types.CollisionProtectionPrevent ||
-			e.isBoxcutterManaged(actualObject) {
+		if collision {
 			return newObjectResultConflict(
 				actualObject, compareRes,
 				actualOwner, options,
@@ -584,20 +807,25 @@ func (e *ObjectEngine) apply(
 	o = append(o, client.FieldOwner(e.fieldOwner))
 	o = append(o, opts...)
 
-	var ac runtime.ApplyConfiguration
+	ac, err := toApplyConfiguration(desiredObject)
+	if err != nil {
+		return err
+	}
 
-	switch v := desiredObject.(type) {
+	return e.writer.Apply(ctx, ac, o...)
+}
+
+func toApplyConfiguration(obj Object) (runtime.ApplyConfiguration, error) {
+	switch v := obj.(type) {
 	case runtime.ApplyConfiguration:
-		ac = v
+		return v, nil
 
 	case *unstructured.Unstructured:
-		ac = client.ApplyConfigurationFromUnstructured(v)
+		return client.ApplyConfigurationFromUnstructured(v), nil
 
 	default:
-		return NewUnsupportedApplyConfigurationError(desiredObject)
+		return nil, NewUnsupportedApplyConfigurationError(obj)
 	}
-
-	return e.writer.Apply(ctx, ac, o...)
 }
 
 type ctrlSituation string
@@ -668,13 +896,21 @@ func (e *ObjectEngine) getObjectRevision(obj client.Object) (int64, error) {
 func (e *ObjectEngine) migrateFieldManagersToSSA(
 	ctx context.Context, object Object,
 	options types.ObjectReconcileOptions,
+) error {
+	return e.migrateFieldManagerToSSA(ctx, object, options, e.fieldOwner)
+}
+
+func (e *ObjectEngine) migrateFieldManagerToSSA(
+	ctx context.Context, object Object,
+	options types.ObjectReconcileOptions,
+	fieldOwner string,
 ) error {
 	if options.Paused {
 		return nil
 	}
 
 	patch, err := csaupgrade.UpgradeManagedFieldsPatch(
-		object, sets.New(e.fieldOwner), e.fieldOwner)
+		object, sets.New(fieldOwner), fieldOwner)
 
 	switch {
 	case err != nil:
@@ -1587,6 +1587,145 @@ func TestObjectEngine_Teardown(t *testing.T) {
 	}
 }
 
+func TestObjectEngine_Teardown_Probes(t *testing.T) {
+	t.Parallel()
+
+	cache := &cacheMock{}
+	writer := testutil.NewClient()
+
+	oe := NewObjectEngine(
+		scheme.Scheme,
+		cache, writer,
+		&comparatorMock{},
+		testFieldOwner,
+		testSystemPrefix,
+		"",
+		nil,
+	)
+
+	desired := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "oe-test",
+			Namespace: "test",
+		},
+	}
+
+	cache.
+		On("Get", mock.Anything, client.ObjectKeyFromObject(desired), mock.Anything, mock.Anything).
+		Run(func(args mock.Arguments) {
+			obj := args.Get(2).(*corev1.ConfigMap)
+			obj.UID = "1"
+			obj.Annotations = map[string]string{testSystemPrefix + "/revision": "1"}
+			obj.Data = map[string]string{"lb": "pending-release"}
+		}).
+		Return(nil).
+		Once()
+	cache.
+		On("Get", mock.Anything, client.ObjectKeyFromObject(desired), mock.Anything, mock.Anything).
+		Return(apierrors.NewNotFound(schema.GroupResource{}, ""))
+	writer.
+		On("Delete", mock.Anything, mock.Anything, mock.Anything).
+		Return(nil)
+
+	released := false
+
+	var probed []client.Object
+
+	probe := types.WithTeardownProbe(types.ProbeFunc(
+		func(obj client.Object) types.ProbeResult {
+			probed = append(probed, obj)
+			if released {
+				return types.ProbeResult{Status: types.ProbeStatusTrue}
+			}
+
+			return types.ProbeResult{Status: types.ProbeStatusFalse}
+		}))
+
+	// Delete issued.
+	gone, err := oe.Teardown(t.Context(), 1, desired, probe)
+	require.NoError(t, err)
+	assert.False(t, gone)
+	assert.Empty(t, probed)
+
+	// Object gone, but probe failing.
+	gone, err = oe.Teardown(t.Context(), 1, desired, probe)
+	require.NoError(t, err)
+	assert.False(t, gone)
+
+	// Probe succeeds.
+	released = true
+	gone, err = oe.Teardown(t.Context(), 1, desired, probe)
+	require.NoError(t, err)
+	assert.True(t, gone)
+
+	require.Len(t, probed, 2)
+	for _, obj := range probed {
+		// Evaluated against last seen state.
+		assert.Equal(t, map[string]string{"lb": "pending-release"}, obj.(*corev1.ConfigMap).Data)
+	}
+
+	// Last seen state is dropped once deletion completed.
+	assert.Zero(t, oe.teardownLastSeen.Len())
+	assert.Zero(t, oe.teardownDeleting.Len())
+}
+
+func TestObjectEngine_Teardown_ProbesNotController(t *testing.T) {
+	t.Parallel()
+
+	cache := &cacheMock{}
+	writer := testutil.NewClient()
+
+	oe := NewObjectEngine(
+		scheme.Scheme,
+		cache, writer,
+		&comparatorMock{},
+		testFieldOwner,
+		testSystemPrefix,
+		"",
+		nil,
+	)
+
+	desired := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "oe-test",
+			Namespace: "test",
+		},
+	}
+
+	cache.
+		On("Get", mock.Anything, client.ObjectKeyFromObject(desired), mock.Anything, mock.Anything).
+		Run(func(args mock.Arguments) {
+			obj := args.Get(2).(*corev1.ConfigMap)
+			obj.UID = "1"
+			obj.OwnerReferences = []metav1.OwnerReference{{
+				APIVersion: "v1",
+				Kind:       "ConfigMap",
+				Name:       "other",
+				UID:        "other",
+				Controller: new(true),
+			}}
+		}).
+		Return(nil)
+	writer.
+		On("Patch", mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+		Return(nil)
+
+	probe := types.WithTeardownProbe(types.ProbeFunc(
+		func(client.Object) types.ProbeResult {
+			return types.ProbeResult{Status: types.ProbeStatusTrue}
+		}))
+
+	gone, err := oe.Teardown(t.Context(), 1, desired, probe,
+		types.WithOwner(testOwner, testOwnerStrategy))
+	require.NoError(t, err)
+	assert.True(t, gone)
+
+	// Objects of other controllers are left in place and not remembered.
+	writer.AssertNotCalled(t, "Delete", mock.Anything, mock.Anything, mock.Anything)
+	assert.Zero(t, oe.teardownLastSeen.Len())
+	assert.Zero(t, oe.teardownDeleting.Len())
+}
+
 func TestObjectEngine_Teardown_SanityChecks(t *testing.T) {
 	t.Parallel()
 
@@ -1808,6 +1947,36 @@ func TestObjectEngine_MigrateFieldManagersToSSA_NoPatch(t *testing.T) {
 	writer.AssertNotCalled(t, "Patch")
 }
 
+// newTestObjectEngine returns an ObjectEngine with mocked writer and comparator,
+// reading actual from its cache or NotFound, if actual is nil.
+func newTestObjectEngine(actual *unstructured.Unstructured) (
+	*ObjectEngine, *testutil.CtrlClient, *comparatorMock,
+) {
+	cache := &cacheMock{}
+	writer := testutil.NewClient()
+	ddm := &comparatorMock{}
+
+	if actual == nil {
+		cache.
+			On("Get", mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+			Return(apierrors.NewNotFound(schema.GroupResource{}, ""))
+	} else {
+		cache.
+			On("Get", mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+			Run(func(args mock.Arguments) {
+				actual.DeepCopyInto(args.Get(2).(*unstructured.Unstructured))
+			}).
+			Return(nil)
+	}
+
+	oe := NewObjectEngine(
+		scheme.Scheme, cache, writer, ddm,
+		testFieldOwner, testSystemPrefix, "", nil,
+	)
+
+	return oe, writer, ddm
+}
+
 type cacheMock struct {
 	testutil.CtrlClient
 }
@@ -74,14 +74,18 @@ type PhaseTeardownResult interface {
 	// Waiting returns a list of objects that have yet to be
 	// cleaned up on the kube-apiserver.
 	Waiting() []types.ObjectRef
+	// HandedOver returns a list of objects that have been
+	// adopted by their new owner and released.
+	HandedOver() []types.ObjectRef
 
 	String() string
 }
 
 type phaseTeardownResult struct {
-	name    string
-	gone    []types.ObjectRef
-	waiting []types.ObjectRef
+	name       string
+	gone       []types.ObjectRef
+	waiting    []types.ObjectRef
+	handedOver []types.ObjectRef
 }
 
 func (r *phaseTeardownResult) String() string {
@@ -104,6 +108,14 @@ func (r *phaseTeardownResult) String() string {
 		}
 	}
 
+	if len(r.handedOver) > 0 {
+		fmt.Fprintln(&out, "Handed Over Objects:")
+
+		for _, handedOver := range r.handedOver {
+			fmt.Fprintf(&out, "- %s\n", handedOver.String())
+		}
+	}
+
 	return out.String()
 }
 
@@ -130,6 +142,12 @@ func (r *phaseTeardownResult) Waiting() []types.ObjectRef {
 	return r.waiting
 }
 
+// HandedOver returns a list of objects that have been
+// adopted by their new owner and released.
+func (r *phaseTeardownResult) HandedOver() []types.ObjectRef {
+	return r.handedOver
+}
+
 // Teardown ensures the given phase is safely removed from the cluster.
 func (e *PhaseEngine) Teardown(
 	ctx context.Context,
@@ -150,8 +168,10 @@ func (e *PhaseEngine) Teardown(
 	errs := make([]error, 0, len(objects))
 
 	for _, obj := range objects {
+		objOpts := options.ForObject(obj)
+
 		gone, err := e.objectEngine.Teardown(
-			ctx, revision, obj, options.ForObject(obj)...)
+			ctx, revision, obj, objOpts...)
 		if err != nil {
 			err = fmt.Errorf("teardown %s: %w", types.ToObjectRef(obj), err)
 			if options.AggregateErrors {
@@ -166,9 +186,12 @@ func (e *PhaseEngine) Teardown(
 			}
 		}
 
-		if gone {
+		switch {
+		case gone && isHandover(objOpts):
+			res.handedOver = append(res.handedOver, types.ToObjectRef(obj))
+		case gone:
 			res.gone = append(res.gone, types.ToObjectRef(obj))
-		} else {
+		default:
 			res.waiting = append(res.waiting, types.ToObjectRef(obj))
 		}
 	}
@@ -176,6 +199,16 @@ func (e *PhaseEngine) Teardown(
 	return res, errors.Join(errs...)
 }
 
+// isHandover returns true if the given options hand the object over to another owner.
+func isHandover(opts []types.ObjectTeardownOption) bool {
+	var options types.ObjectTeardownOptions
+	for _, opt := range opts {
+		opt.ApplyToObjectTeardownOptions(&options)
+	}
+
+	return len(options.HandoverTo) > 0
+}
+
 // Reconcile runs actions to bring actual state closer to desired.
 func (e *PhaseEngine) Reconcile(
 	ctx context.Context,
@@ -194,6 +227,13 @@ func (e *PhaseEngine) Reconcile(
 		name: phase.GetName(),
 	}
 
+	// Collect warnings of preflight validation and reconciliation,
+	// e.g. reads served by a direct read fallback of the cache.
+	warnings := &types.WarningRecorder{}
+	ctx = types.ContextWithWarningRecorder(ctx, warnings)
+
+	defer func() { pres.warnings = warnings.Warnings() }()
+
 	// Preflight
 	err := e.phaseValidator.Validate(ctx, phase, opts...)
 	if err != nil {
@@ -241,6 +281,9 @@ type PhaseResult interface {
 	// GetValidationError returns the preflight validation
 	// error, if one was encountered.
 	GetValidationError() *validation.PhaseValidationError
+	// GetWarnings returns non-fatal findings of preflight validation and reconciliation,
+	// e.g. the use of deprecated API versions.
+	GetWarnings() []types.Warning
 	// GetObjects returns results for individual objects.
 	GetObjects() []ObjectResult
 	// InTransition returns true if the Phase has not yet fully rolled out,
@@ -259,6 +302,7 @@ type PhaseResult interface {
 type phaseResult struct {
 	name            string
 	validationError *validation.PhaseValidationError
+	warnings        []types.Warning
 	objects         []ObjectResult
 }
 
@@ -273,6 +317,12 @@ func (r *phaseResult) GetValidationError() *validation.PhaseValidationError {
 	return r.validationError
 }
 
+// GetWarnings returns non-fatal findings of preflight validation and reconciliation,
+// e.g. the use of deprecated API versions.
+func (r *phaseResult) GetWarnings() []types.Warning {
+	return r.warnings
+}
+
 // GetObjects returns results for individual objects.
 func (r *phaseResult) GetObjects() []ObjectResult {
 	return r.objects
@@ -349,6 +399,14 @@ func (r *phaseResult) String() string {
 		}
 	}
 
+	if len(r.warnings) > 0 {
+		fmt.Fprintln(&out, "Warnings:")
+
+		for _, w := range r.warnings {
+			fmt.Fprintf(&out, "- %s\n", w.String())
+		}
+	}
+
 	fmt.Fprintln(&out, "Objects:")
 
 	for _, ores := range r.objects {
@@ -49,19 +49,31 @@ func TestPhaseEngine_Reconcile(t *testing.T) {
 
 	var revision int64 = 1
 
+	warning := validation.Warning{ObjectRef: types.ToObjectRef(obj), Message: "deprecated"}
+
 	pv.
 		On("Validate", mock.Anything, mock.Anything, mock.Anything).
+		Run(func(args mock.Arguments) {
+			validation.RecordWarning(args.Get(0).(context.Context), warning)
+		}).
 		Return(nil)
+	// Warnings of reconciliation, e.g. cache fallback reads.
+	readWarning := validation.Warning{ObjectRef: types.ToObjectRef(obj), Message: "direct read"}
+
 	oe.On("Reconcile", mock.Anything, revision, obj, mock.Anything).
+		Run(func(args mock.Arguments) {
+			validation.RecordWarning(args.Get(0).(context.Context), readWarning)
+		}).
 		Return(newObjectResultCreated(obj, types.ObjectReconcileOptions{}), nil)
 
-	_, err := pe.Reconcile(t.Context(), revision, types.NewPhase(
+	res, err := pe.Reconcile(t.Context(), revision, types.NewPhase(
 		"test",
 		[]client.Object{
 			obj,
 		},
 	), types.WithOwner(owner, nil))
 	require.NoError(t, err)
+	assert.Equal(t, []validation.Warning{warning, readWarning}, res.GetWarnings())
 }
 
 func TestPhaseEngine_Reconcile_PreflightViolation(t *testing.T) {
@@ -153,6 +165,50 @@ func TestPhaseEngine_Teardown(t *testing.T) {
 	assert.Len(t, deleted.Gone(), 2)
 }
 
+func TestPhaseEngine_Teardown_Handover(t *testing.T) {
+	t.Parallel()
+
+	oe := &objectEngineMock{}
+	pv := &phaseValidatorMock{}
+	pe := NewPhaseEngine(oe, pv)
+
+	obj1 := &unstructured.Unstructured{
+		Object: map[string]any{
+			"apiVersion": "v1",
+			"kind":       "Secret",
+			"metadata": map[string]any{
+				"name":      "secret1",
+				"namespace": "test",
+			},
+		},
+	}
+	obj2 := &unstructured.Unstructured{
+		Object: map[string]any{
+			"apiVersion": "v1",
+			"kind":       "Secret",
+			"metadata": map[string]any{
+				"name":      "secret2",
+				"namespace": "test",
+			},
+		},
+	}
+
+	var revision int64 = 1
+
+	oe.On("Teardown", mock.Anything, revision, obj1, mock.Anything).
+		Return(true, nil)
+	oe.On("Teardown", mock.Anything, revision, obj2, mock.Anything).
+		Return(true, nil)
+
+	res, err := pe.Teardown(t.Context(), revision, types.NewPhase(
+		"test", []client.Object{obj1, obj2},
+	), types.WithObjectTeardownOptions(obj1, types.WithHandoverTo("new-owner")))
+	require.NoError(t, err)
+	assert.True(t, res.IsComplete())
+	assert.Equal(t, []types.ObjectRef{types.ToObjectRef(obj1)}, res.HandedOver())
+	assert.Equal(t, []types.ObjectRef{types.ToObjectRef(obj2)}, res.Gone())
+}
+
 func TestPhaseEngine_AggregateErrors(t *testing.T) {
 	t.Parallel()
 
@@ -539,6 +595,10 @@ func TestPhaseResult_String(t *testing.T) {
 			PhaseName:  "banana",
 			PhaseError: errTest,
 		},
+		warnings: []validation.Warning{{
+			ObjectRef: types.ToObjectRef(obj),
+			Message:   "deprecated",
+		}},
 		objects: []ObjectResult{
 			newObjectResultCreated(obj, types.ObjectReconcileOptions{}),
 		},
@@ -549,6 +609,8 @@ Complete: false
 In Transition: false
 Validation Errors:
 - AAAAAAh
+Warnings:
+- /v1, Kind=Secret test/testi: deprecated
 Objects:
 - Object Secret.v1 test/testi
   Action: "Created"
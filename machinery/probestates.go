@@ -0,0 +1,162 @@
+package machinery
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"maps"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	machinerytypes "k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/lru"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"pkg.package-operator.run/boxcutter/machinery/types"
+)
+
+// DefaultInMemoryProbeStateStoreSize is the number of objects
+// the default in-memory probe state store keeps states for.
+const DefaultInMemoryProbeStateStoreSize = 4096
+
+var (
+	_ types.ProbeStateStore = (*InMemoryProbeStateStore)(nil)
+	_ types.ProbeStateStore = (*AnnotationProbeStateStore)(nil)
+)
+
+// InMemoryProbeStateStore keeps probe states in memory.
+// States are lost on restart, so transition times start
+// at the first observation after a process restart.
+// InMemoryProbeStateStore is safe for concurrent use.
+type InMemoryProbeStateStore struct {
+	cache *lru.Cache
+}
+
+// NewInMemoryProbeStateStore returns a new InMemoryProbeStateStore
+// holding states for at most maxEntries objects.
+func NewInMemoryProbeStateStore(maxEntries int) *InMemoryProbeStateStore {
+	return &InMemoryProbeStateStore{cache: lru.New(maxEntries)}
+}
+
+type inMemoryProbeStateKey struct {
+	ref types.ObjectRef
+	uid machinerytypes.UID
+}
+
+func newInMemoryProbeStateKey(obj client.Object) inMemoryProbeStateKey {
+	return inMemoryProbeStateKey{
+		ref: types.ToObjectRef(obj),
+		uid: obj.GetUID(),
+	}
+}
+
+// Load returns the previously recorded probe states of the given object.
+func (s *InMemoryProbeStateStore) Load(
+	_ context.Context, obj client.Object,
+) (types.ProbeStateContainer, error) {
+	v, ok := s.cache.Get(newInMemoryProbeStateKey(obj))
+	if !ok {
+		return nil, nil
+	}
+
+	return maps.Clone(v.(types.ProbeStateContainer)), nil
+}
+
+// Store records the probe states of the given object.
+func (s *InMemoryProbeStateStore) Store(
+	_ context.Context, obj client.Object, states types.ProbeStateContainer,
+) error {
+	s.cache.Add(newInMemoryProbeStateKey(obj), maps.Clone(states))
+
+	return nil
+}
+
+// AnnotationProbeStateStore persists probe states as JSON
+// in an annotation on the object itself, so they survive restarts.
+// States are read from the object as last seen on the cluster and
+// only written when they changed.
+//
+// Every change is written with a separate merge patch of the live object,
+// which bumps its resourceVersion and emits a watch event,
+// so owners watching the object reconcile again after each probe transition.
+// Prefer the InMemoryProbeStateStore for objects with frequently flapping probes.
+// Shared objects need an annotationKey per owner, as owners don't share probe states.
+type AnnotationProbeStateStore struct {
+	writer        client.Writer
+	annotationKey string
+	fieldOwner    string
+}
+
+// NewAnnotationProbeStateStore returns a new AnnotationProbeStateStore.
+// fieldOwner MUST be different from the field owner of the ObjectEngine,
+// otherwise the annotation is dropped with the next apply.
+func NewAnnotationProbeStateStore(
+	writer client.Writer,
+	annotationKey string,
+	fieldOwner string,
+) *AnnotationProbeStateStore {
+	return &AnnotationProbeStateStore{
+		writer:        writer,
+		annotationKey: annotationKey,
+		fieldOwner:    fieldOwner,
+	}
+}
+
+// Load returns the previously recorded probe states of the given object.
+// Invalid annotation values are discarded and overwritten with the next Store.
+func (s *AnnotationProbeStateStore) Load(
+	_ context.Context, obj client.Object,
+) (types.ProbeStateContainer, error) {
+	states, err := s.decode(obj)
+	if err != nil {
+		return nil, nil //nolint:nilerr
+	}
+
+	return states, nil
+}
+
+// Store records the probe states of the given object.
+func (s *AnnotationProbeStateStore) Store(
+	ctx context.Context, obj client.Object, states types.ProbeStateContainer,
+) error {
+	current, err := s.decode(obj)
+	if err == nil && equality.Semantic.DeepEqual(current, states) {
+		return nil
+	}
+
+	b, err := json.Marshal(states)
+	if err != nil {
+		return fmt.Errorf("marshal probe states: %w", err)
+	}
+
+	patch, err := json.Marshal(map[string]any{
+		"metadata": map[string]any{
+			"annotations": map[string]string{
+				s.annotationKey: string(b),
+			},
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("marshal probe states patch: %w", err)
+	}
+
+	if err := s.writer.Patch(ctx, obj, client.RawPatch(
+		machinerytypes.MergePatchType, patch), client.FieldOwner(s.fieldOwner)); err != nil {
+		return fmt.Errorf("patching probe states annotation: %w", err)
+	}
+
+	return nil
+}
+
+func (s *AnnotationProbeStateStore) decode(obj client.Object) (types.ProbeStateContainer, error) {
+	v, ok := obj.GetAnnotations()[s.annotationKey]
+	if !ok || len(v) == 0 {
+		return nil, nil
+	}
+
+	var states types.ProbeStateContainer
+	if err := json.Unmarshal([]byte(v), &states); err != nil {
+		return nil, fmt.Errorf("unmarshal probe states annotation %q: %w", s.annotationKey, err)
+	}
+
+	return states, nil
+}
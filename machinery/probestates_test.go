@@ -0,0 +1,294 @@
+package machinery
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/kubernetes/scheme"
+	clocktesting "k8s.io/utils/clock/testing"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"pkg.package-operator.run/boxcutter/internal/testutil"
+	"pkg.package-operator.run/boxcutter/machinery/types"
+)
+
+var testProbeStates = types.ProbeStateContainer{
+	types.ProgressProbeType: {
+		Status:             types.ProbeStatusFalse,
+		LastTransitionTime: metav1.NewTime(time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)),
+	},
+}
+
+func TestInMemoryProbeStateStore(t *testing.T) {
+	t.Parallel()
+
+	ctx := t.Context()
+	store := NewInMemoryProbeStateStore(10)
+
+	cm := &corev1.ConfigMap{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: "ConfigMap"},
+		ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "test", UID: "1"},
+	}
+
+	states, err := store.Load(ctx, cm)
+	require.NoError(t, err)
+	assert.Nil(t, states)
+
+	require.NoError(t, store.Store(ctx, cm, testProbeStates))
+
+	states, err = store.Load(ctx, cm)
+	require.NoError(t, err)
+	assert.Equal(t, testProbeStates, states)
+
+	// Recreated object must not inherit states.
+	recreated := cm.DeepCopy()
+	recreated.UID = "2"
+
+	states, err = store.Load(ctx, recreated)
+	require.NoError(t, err)
+	assert.Nil(t, states)
+}
+
+func TestAnnotationProbeStateStore(t *testing.T) {
+	t.Parallel()
+
+	const annotationKey = "test/probe-states"
+
+	b, err := json.Marshal(testProbeStates)
+	require.NoError(t, err)
+
+	t.Run("load", func(t *testing.T) {
+		t.Parallel()
+
+		store := NewAnnotationProbeStateStore(testutil.NewClient(), annotationKey, "probes")
+		cm := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{
+			Annotations: map[string]string{annotationKey: string(b)},
+		}}
+
+		states, err := store.Load(t.Context(), cm)
+		require.NoError(t, err)
+		assert.True(t, equality.Semantic.DeepEqual(testProbeStates, states))
+
+		cm.Annotations[annotationKey] = "{"
+		states, err = store.Load(t.Context(), cm)
+		require.NoError(t, err)
+		assert.Nil(t, states)
+	})
+
+	t.Run("store unchanged", func(t *testing.T) {
+		t.Parallel()
+
+		writer := testutil.NewClient()
+		store := NewAnnotationProbeStateStore(writer, annotationKey, "probes")
+		cm := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{
+			Annotations: map[string]string{annotationKey: string(b)},
+		}}
+
+		require.NoError(t, store.Store(t.Context(), cm, testProbeStates))
+		writer.AssertNotCalled(t, "Patch", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("store changed", func(t *testing.T) {
+		t.Parallel()
+
+		writer := testutil.NewClient()
+		store := NewAnnotationProbeStateStore(writer, annotationKey, "probes")
+		cm := &corev1.ConfigMap{}
+
+		writer.
+			On("Patch", mock.Anything, cm, mock.Anything, mock.Anything).
+			Return(nil)
+
+		require.NoError(t, store.Store(t.Context(), cm, testProbeStates))
+
+		patch := writer.Calls[0].Arguments.Get(2).(client.Patch)
+		data, err := patch.Data(cm)
+		require.NoError(t, err)
+		assert.JSONEq(t,
+			`{"metadata":{"annotations":{"test/probe-states":`+string(mustMarshal(t, string(b)))+`}}}`,
+			string(data))
+		assert.Equal(t, []client.PatchOption{client.FieldOwner("probes")},
+			writer.Calls[0].Arguments.Get(3))
+	})
+}
+
+func TestObjectEngine_ProbeStates(t *testing.T) {
+	t.Parallel()
+
+	cache := &cacheMock{}
+	writer := testutil.NewClient()
+	clock := clocktesting.NewFakePassiveClock(time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC))
+
+	oe := NewObjectEngine(
+		scheme.Scheme,
+		cache, writer,
+		&comparatorMock{},
+		testFieldOwner,
+		testSystemPrefix,
+		"",
+		nil,
+	)
+	oe.clock = clock
+
+	configMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "oe-test",
+			Namespace: "test",
+		},
+	}
+
+	cache.
+		On("Get", mock.Anything, client.ObjectKeyFromObject(configMap), mock.Anything, mock.Anything).
+		Return(apierrors.NewNotFound(schema.GroupResource{}, ""))
+	writer.
+		On("Create", mock.Anything, mock.Anything, mock.Anything).
+		Return(nil)
+
+	status := types.ProbeStatusFalse
+	probe := types.WithProbe(types.ProgressProbeType, types.ProbeFunc(
+		func(client.Object) types.ProbeResult {
+			return types.ProbeResult{Status: status}
+		}))
+
+	reconcile := func() types.ProbeState {
+		t.Helper()
+
+		res, err := oe.Reconcile(t.Context(), 1, configMap, probe)
+		require.NoError(t, err)
+
+		s, ok := res.ProbeStates().Type(types.ProgressProbeType)
+		require.True(t, ok)
+
+		return s
+	}
+
+	t0 := clock.Now()
+	s := reconcile()
+	assert.Equal(t, types.ProbeStatusFalse, s.Status)
+	assert.Equal(t, t0, s.LastTransitionTime.Time)
+
+	clock.SetTime(t0.Add(time.Minute))
+
+	s = reconcile()
+	assert.Equal(t, types.ProbeStatusFalse, s.Status)
+	assert.Equal(t, t0, s.LastTransitionTime.Time)
+	assert.Equal(t, time.Minute, s.Since(clock.Now()))
+
+	status = types.ProbeStatusTrue
+	s = reconcile()
+	assert.Equal(t, types.ProbeStatusTrue, s.Status)
+	assert.Equal(t, t0.Add(time.Minute), s.LastTransitionTime.Time)
+}
+
+func mustMarshal(t *testing.T, v any) []byte {
+	t.Helper()
+
+	b, err := json.Marshal(v)
+	require.NoError(t, err)
+
+	return b
+}
+
+func TestObjectEngine_ProbeStates_Collision(t *testing.T) {
+	t.Parallel()
+
+	cache := &cacheMock{}
+	writer := testutil.NewClient()
+	ddm := &comparatorMock{}
+
+	oe := NewObjectEngine(
+		scheme.Scheme,
+		cache, writer, ddm,
+		testFieldOwner,
+		testSystemPrefix,
+		"",
+		nil,
+	)
+
+	actual := buildObj("testi", "test",
+		withOwnerRef("v1", "ConfigMap", "other", "other-uid", true))(nil)
+
+	cache.
+		On("Get", mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+		Run(func(args mock.Arguments) {
+			actual.DeepCopyInto(args.Get(2).(*unstructured.Unstructured))
+		}).
+		Return(nil)
+	ddm.
+		On("Compare", mock.Anything, mock.Anything, mock.Anything).
+		Return(CompareResult{}, nil)
+
+	probe := types.WithProbe(types.ProgressProbeType, types.ProbeFunc(
+		func(client.Object) types.ProbeResult {
+			return types.ProbeResult{Status: types.ProbeStatusTrue}
+		}))
+	store := NewAnnotationProbeStateStore(writer, testSystemPrefix+"/probe-states", testFieldOwner+"/probes")
+
+	res, err := oe.Reconcile(t.Context(), 1, buildObj("testi", "test")(nil),
+		types.WithOwner(testOwner, testOwnerStrategy), probe, types.WithProbeStateStore(store))
+	require.NoError(t, err)
+	assert.Equal(t, ActionCollision, res.Action())
+	assert.Nil(t, res.ProbeStates())
+
+	// The object of the other owner is never patched.
+	writer.AssertNotCalled(t, "Patch", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestObjectEngine_ProbeStates_Shared(t *testing.T) {
+	t.Parallel()
+
+	cache := &cacheMock{}
+	writer := testutil.NewClient()
+	ddm := &comparatorMock{}
+
+	oe := NewObjectEngine(
+		scheme.Scheme,
+		cache, writer, ddm,
+		testFieldOwner,
+		testSystemPrefix,
+		"",
+		nil,
+	)
+
+	// Shared object owned, but not controlled by the owner.
+	actual := buildObj("testi", "test",
+		withOwnerRef("v1", "ConfigMap", testOwner.Name, string(testOwner.UID), false))(nil)
+	actual.SetAnnotations(map[string]string{testSystemPrefix + "/shared": "True"})
+
+	cache.
+		On("Get", mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+		Run(func(args mock.Arguments) {
+			actual.DeepCopyInto(args.Get(2).(*unstructured.Unstructured))
+		}).
+		Return(nil)
+	ddm.
+		On("Compare", mock.Anything, mock.Anything, mock.Anything).
+		Return(CompareResult{}, nil)
+	writer.
+		On("Apply", mock.Anything, mock.Anything, mock.Anything).
+		Return(nil)
+
+	probe := types.WithProbe(types.ProgressProbeType, types.ProbeFunc(
+		func(client.Object) types.ProbeResult {
+			return types.ProbeResult{Status: types.ProbeStatusTrue}
+		}))
+
+	res, err := oe.Reconcile(t.Context(), 1, buildObj("testi", "test")(nil),
+		types.WithOwner(testOwner, testOwnerStrategy), types.WithShared(), probe)
+	require.NoError(t, err)
+	assert.Equal(t, ActionUpdated, res.Action())
+
+	s, ok := res.ProbeStates().Type(types.ProgressProbeType)
+	require.True(t, ok)
+	assert.Equal(t, types.ProbeStatusTrue, s.Status)
+}
@@ -19,6 +19,9 @@ type ObjectResult interface {
 	Object() Object
 	// Probes returns the results from the given object Probes.
 	ProbeResults() types.ProbeResultContainer
+	// ProbeStates returns the probe states including transition times.
+	// Empty when no probes have been given.
+	ProbeStates() types.ProbeStateContainer
 	// String returns a human readable description of the Result.
 	String() string
 	// IsComplete returns true when:
@@ -47,12 +50,14 @@ var (
 	_ ObjectResult = (*ObjectResultProgressed)(nil)
 	_ ObjectResult = (*ObjectResultRecovered)(nil)
 	_ ObjectResult = (*ObjectResultCollision)(nil)
+	_ ObjectResult = (*ObjectResultHandover)(nil)
 )
 
 // ObjectResultCreated is returned when the Object was just created.
 type ObjectResultCreated struct {
 	obj          Object
 	probeResults types.ProbeResultContainer
+	probeStates  types.ProbeStateContainer
 	options      types.ObjectReconcileOptions
 }
 
@@ -99,6 +104,11 @@ func (r ObjectResultCreated) ProbeResults() types.ProbeResultContainer {
 	return r.probeResults
 }
 
+// ProbeStates returns the probe states including transition times.
+func (r ObjectResultCreated) ProbeStates() types.ProbeStateContainer {
+	return r.probeStates
+}
+
 // String returns a human readable description of the Result.
 func (r ObjectResultCreated) String() string {
 	return reportStart(r)
@@ -164,10 +174,44 @@ func newObjectResultRecovered(
 	}
 }
 
+// ObjectResultHandover is returned when the object has been adopted
+// after its previous owner handed it over.
+type ObjectResultHandover struct {
+	normalResult
+
+	previousOwner *metav1.OwnerReference
+}
+
+// PreviousOwner returns the owner that handed the object over.
+func (r ObjectResultHandover) PreviousOwner() *metav1.OwnerReference {
+	return r.previousOwner
+}
+
+// String returns a human readable description of the Result.
+func (r ObjectResultHandover) String() string {
+	msg := r.normalResult.String()
+	msg += fmt.Sprintf("Previous Owner: %s\n", r.previousOwner.String())
+
+	return msg
+}
+
+func newObjectResultHandover(
+	obj Object,
+	diverged CompareResult,
+	previousOwner *metav1.OwnerReference,
+	options types.ObjectReconcileOptions,
+) ObjectResult {
+	return ObjectResultHandover{
+		normalResult:  newNormalObjectResult(ActionHandover, obj, diverged, options),
+		previousOwner: previousOwner,
+	}
+}
+
 type normalResult struct {
 	action        Action
 	obj           Object
 	probeResults  types.ProbeResultContainer
+	probeStates   types.ProbeStateContainer
 	compareResult CompareResult
 	options       types.ObjectReconcileOptions
 }
@@ -213,6 +257,11 @@ func (r normalResult) ProbeResults() types.ProbeResultContainer {
 	return r.probeResults
 }
 
+// ProbeStates returns the probe states including transition times.
+func (r normalResult) ProbeStates() types.ProbeStateContainer {
+	return r.probeStates
+}
+
 // IsPaused returns true when the WithPaused option has been set.
 func (r normalResult) IsPaused() bool {
 	return r.options.Paused
@@ -276,6 +325,38 @@ func newObjectResultConflict(
 	}
 }
 
+// withProbeStates returns a copy of the given result with probe states attached.
+func withProbeStates(res ObjectResult, states types.ProbeStateContainer) ObjectResult {
+	switch r := res.(type) {
+	case ObjectResultCreated:
+		r.probeStates = states
+
+		return r
+	case ObjectResultUpdated:
+		r.probeStates = states
+
+		return r
+	case ObjectResultProgressed:
+		r.probeStates = states
+
+		return r
+	case ObjectResultIdle:
+		r.probeStates = states
+
+		return r
+	case ObjectResultRecovered:
+		r.probeStates = states
+
+		return r
+	case ObjectResultHandover:
+		r.probeStates = states
+
+		return r
+	}
+
+	return res
+}
+
 // Action describes the taken reconciliation action.
 type Action string
 
@@ -293,6 +374,8 @@ const (
 	ActionIdle Action = "Idle"
 	// ActionCollision indicates aking actions was refused due to a collision with an existing object.
 	ActionCollision Action = "Collision"
+	// ActionHandover indicates that the object has been adopted after being handed over by its previous owner.
+	ActionHandover Action = "Handover"
 )
 
 func reportStart(or ObjectResult) string {
@@ -631,6 +631,10 @@ func (r *testPhaseResult) GetValidationError() *validation.PhaseValidationError
 	return r.validationErr
 }
 
+func (r *testPhaseResult) GetWarnings() []validation.Warning {
+	return nil
+}
+
 func (r *testPhaseResult) GetObjects() []ObjectResult {
 	return r.objects
 }
@@ -0,0 +1,321 @@
+package machinery
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/structured-merge-diff/v6/fieldpath"
+
+	"pkg.package-operator.run/boxcutter/machinery/types"
+)
+
+// sharedOwnerStrategy is needed to share objects between multiple owners.
+type sharedOwnerStrategy interface {
+	SetOwnerReference(owner, obj metav1.Object) error
+	IsOwner(owner, obj metav1.Object) bool
+	RemoveOwner(owner, obj metav1.Object)
+	GetOwnerReferences(obj metav1.Object) []metav1.OwnerReference
+}
+
+// comparatorFieldOwner overrides the field owner to compare managed fields of.
+type comparatorFieldOwner string
+
+// ApplyToComparatorOptions implements types.ComparatorOption.
+func (o comparatorFieldOwner) ApplyToComparatorOptions(opts *types.ComparatorOptions) {
+	opts.FieldOwner = string(o)
+}
+
+// Shared objects are reconciled without controller and revision.
+// Every owner adds a non-controller owner reference
+// and applies its fields under its own field manager,
+// so conflicting values between owners are rejected by the kube-apiserver.
+func (e *ObjectEngine) reconcileShared(
+	ctx context.Context,
+	desiredObject Object,
+	options types.ObjectReconcileOptions,
+) (ObjectResult, error) {
+	ownerStrategy, err := e.sharedOwnerStrategy(options.Owner, options.OwnerStrategy)
+	if err != nil {
+		return nil, err
+	}
+
+	e.setSharedAnnotation(desiredObject)
+
+	// Lookup actual object state on cluster.
+	actualObject := desiredObject.DeepCopyObject().(Object)
+
+	err = e.cache.Get(
+		ctx, client.ObjectKeyFromObject(desiredObject), actualObject,
+	)
+
+	switch {
+	case apierrors.IsNotFound(err):
+		createObject := desiredObject.DeepCopyObject().(Object)
+		if err := ownerStrategy.SetOwnerReference(options.Owner, createObject); err != nil {
+			return nil, fmt.Errorf("set owner reference: %w", err)
+		}
+
+		fieldOwner := e.sharedFieldOwner(options.Owner)
+
+		err := e.create(ctx, createObject, options, client.FieldOwner(fieldOwner))
+		if apierrors.IsAlreadyExists(err) {
+			if e.unfilteredReader == nil {
+				return nil, NewCreateCollisionError(createObject, err.Error())
+			}
+
+			// Object has been created by another owner in the meantime
+			// or is excluded from the cache.
+			actualObject := desiredObject.DeepCopyObject().(Object)
+			if err := e.unfilteredReader.Get(
+				ctx, client.ObjectKeyFromObject(desiredObject), actualObject,
+			); err != nil {
+				return nil, fmt.Errorf("getting object after create collision: %w", err)
+			}
+
+			return e.sharedUpdateHandling(ctx, ownerStrategy, desiredObject, actualObject, options)
+		}
+
+		if err != nil {
+			return nil, fmt.Errorf("creating resource: %w", err)
+		}
+
+		if err := e.migrateFieldManagerToSSA(ctx, createObject, options, fieldOwner); err != nil {
+			return nil, fmt.Errorf("migrating to SSA after create: %w", err)
+		}
+
+		return newObjectResultCreated(createObject, options), nil
+
+	case err != nil:
+		return nil, fmt.Errorf("getting object: %w", err)
+	}
+
+	return e.sharedUpdateHandling(ctx, ownerStrategy, desiredObject, actualObject, options)
+}
+
+func (e *ObjectEngine) sharedUpdateHandling(
+	ctx context.Context,
+	ownerStrategy sharedOwnerStrategy,
+	desiredObject Object,
+	actualObject Object,
+	options types.ObjectReconcileOptions,
+) (ObjectResult, error) {
+	// Objects with a controller or not created as shared object collide.
+	if collision, ctrl := types.CheckCollision(actualObject, 0, e.systemPrefix, options); collision {
+		return newObjectResultConflict(actualObject, CompareResult{}, ctrl, options), nil
+	}
+
+	isOwner := ownerStrategy.IsOwner(options.Owner, actualObject)
+
+	// Owner reference is applied with our fields,
+	// so it is not removed by the next apply.
+	if err := ownerStrategy.SetOwnerReference(options.Owner, desiredObject); err != nil {
+		return nil, fmt.Errorf("set owner reference: %w", err)
+	}
+
+	fieldOwner := e.sharedFieldOwner(options.Owner)
+
+	compareRes, err := e.comparator.Compare(
+		desiredObject, actualObject, comparatorFieldOwner(fieldOwner))
+	if err != nil {
+		return nil, fmt.Errorf("diverge check: %w", err)
+	}
+
+	// Without own managed fields there is no comparison and fields have to be applied.
+	modified := compareRes.Comparison == nil ||
+		!compareRes.Comparison.Modified.Empty() ||
+		!compareRes.Comparison.Removed.Empty()
+	if isOwner && !modified && !compareRes.IsConflict() {
+		return newObjectResultIdle(actualObject, compareRes, options), nil
+	}
+
+	if !isOwner && !options.Paused {
+		patch := actualObject.DeepCopyObject().(Object)
+		if err := ownerStrategy.SetOwnerReference(options.Owner, patch); err != nil {
+			return nil, fmt.Errorf("set owner reference: %w", err)
+		}
+
+		if err := e.writer.Patch(ctx, patch, client.MergeFromWithOptions(
+			actualObject, client.MergeFromWithOptimisticLock{},
+		)); err != nil {
+			return nil, fmt.Errorf("adding owner reference: %w", err)
+		}
+	}
+
+	// Apply without force to detect conflicting values of other owners.
+	// Validated with a dry run while paused.
+	applyOpts := []client.ApplyOption{client.FieldOwner(fieldOwner)}
+	if options.Paused {
+		applyOpts = append(applyOpts, client.DryRunAll)
+	}
+
+	// Other owners constantly change the object, so no optimistic locking.
+	desiredObject.SetResourceVersion("")
+
+	ac, err := toApplyConfiguration(desiredObject)
+	if err != nil {
+		return nil, err
+	}
+
+	err = e.writer.Apply(ctx, ac, applyOpts...)
+	if apierrors.IsConflict(err) {
+		compareRes.ConflictingMangers = sharedConflicts(compareRes, err)
+
+		return newObjectResultConflict(actualObject, compareRes, nil, options), nil
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("patching (shared): %w", err)
+	}
+
+	if options.Paused {
+		return newObjectResultUpdated(actualObject, compareRes, options), nil
+	}
+
+	return newObjectResultUpdated(desiredObject, compareRes, options), nil
+}
+
+// releaseShared removes the owner from a shared object and releases its fields.
+// Returns false when the owner was the last owner and the object has to be deleted.
+func (e *ObjectEngine) releaseShared(
+	ctx context.Context,
+	desiredObject Object,
+	actualObject Object,
+	options types.ObjectTeardownOptions,
+) (bool, error) {
+	ownerStrategy, err := e.sharedOwnerStrategy(options.Owner, options.OwnerStrategy)
+	if err != nil {
+		return false, err
+	}
+
+	patch := actualObject.DeepCopyObject().(Object)
+	ownerStrategy.RemoveOwner(options.Owner, patch)
+
+	if len(ownerStrategy.GetOwnerReferences(patch)) == 0 {
+		return false, nil
+	}
+
+	if ownerStrategy.IsOwner(options.Owner, actualObject) {
+		if err := e.writer.Patch(ctx, patch, client.MergeFromWithOptions(
+			actualObject, client.MergeFromWithOptimisticLock{},
+		)); err != nil {
+			return false, fmt.Errorf("removing owner reference: %w", err)
+		}
+	}
+
+	fieldOwner := e.sharedFieldOwner(options.Owner)
+	if _, ok := findManagedFields(fieldOwner, actualObject); !ok {
+		return true, nil
+	}
+
+	// Applying an empty object releases all fields of the field owner.
+	// Fields not managed by any other owner are removed.
+	if err := ensureGVKIsSet(desiredObject, e.scheme); err != nil {
+		return false, err
+	}
+
+	empty := &unstructured.Unstructured{}
+	empty.SetGroupVersionKind(desiredObject.GetObjectKind().GroupVersionKind())
+	empty.SetName(desiredObject.GetName())
+	empty.SetNamespace(desiredObject.GetNamespace())
+
+	if err := e.writer.Apply(
+		ctx, client.ApplyConfigurationFromUnstructured(empty), client.FieldOwner(fieldOwner),
+	); err != nil {
+		return false, fmt.Errorf("releasing fields: %w", err)
+	}
+
+	return true, nil
+}
+
+func (e *ObjectEngine) sharedOwnerStrategy(
+	owner client.Object, ownerStrategy types.OwnerStrategy,
+) (sharedOwnerStrategy, error) {
+	if owner == nil {
+		return nil, ErrSharedWithoutOwner
+	}
+
+	s, ok := ownerStrategy.(sharedOwnerStrategy)
+	if !ok {
+		return nil, fmt.Errorf("%w: %T", ErrSharedOwnerStrategyUnsupported, ownerStrategy)
+	}
+
+	return s, nil
+}
+
+// sharedFieldOwner returns the field manager of the given owner.
+func (e *ObjectEngine) sharedFieldOwner(owner client.Object) string {
+	return e.fieldOwner + "/" + string(owner.GetUID())
+}
+
+func (e *ObjectEngine) sharedAnnotation() string {
+	return e.systemPrefix + "/shared"
+}
+
+// isShared returns true if the object has been created as shared object.
+func (e *ObjectEngine) isShared(obj client.Object) bool {
+	_, ok := obj.GetAnnotations()[e.sharedAnnotation()]
+
+	return ok
+}
+
+func (e *ObjectEngine) setSharedAnnotation(obj client.Object) {
+	a := obj.GetAnnotations()
+	if a == nil {
+		a = map[string]string{}
+	}
+
+	a[e.sharedAnnotation()] = "true"
+	obj.SetAnnotations(a)
+}
+
+// sharedConflicts returns the managers of conflicting fields reported by the kube-apiserver.
+func sharedConflicts(compareRes CompareResult, err error) []CompareResultManagedFields {
+	var apiStatus apierrors.APIStatus
+	if !errors.As(err, &apiStatus) || apiStatus.Status().Details == nil {
+		return compareRes.ConflictingMangers
+	}
+
+	var conflicts []CompareResultManagedFields
+
+	for _, cause := range apiStatus.Status().Details.Causes {
+		if cause.Type != metav1.CauseTypeFieldManagerConflict {
+			continue
+		}
+
+		// Message format: conflict with "manager" [using apps/v1].
+		_, manager, ok := strings.Cut(cause.Message, `"`)
+		if !ok {
+			continue
+		}
+
+		manager, _, _ = strings.Cut(manager, `"`)
+
+		if slices.ContainsFunc(conflicts, func(c CompareResultManagedFields) bool {
+			return c.Manager == manager
+		}) {
+			continue
+		}
+
+		fields := &fieldpath.Set{}
+
+		for _, c := range compareRes.ConflictingMangers {
+			if c.Manager == manager {
+				fields = c.Fields
+			}
+		}
+
+		conflicts = append(conflicts, CompareResultManagedFields{
+			Manager: manager,
+			Fields:  fields,
+		})
+	}
+
+	return conflicts
+}
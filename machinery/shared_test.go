@@ -0,0 +1,261 @@
+package machinery
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/structured-merge-diff/v6/fieldpath"
+	"sigs.k8s.io/structured-merge-diff/v6/typed"
+
+	"pkg.package-operator.run/boxcutter/machinery/types"
+)
+
+func TestObjectEngine_Shared(t *testing.T) {
+	t.Parallel()
+
+	const sharedFieldOwner = testFieldOwner + "/12345-678"
+
+	withShared := func(obj *unstructured.Unstructured, _ *ownerMode) {
+		obj.SetAnnotations(map[string]string{testSystemPrefix + "/shared": "true"})
+	}
+	withOtherOwner := withOwnerRef("v1", "ConfigMap", "other", "other-uid", false)
+	withTestOwner := withOwnerRef("v1", "ConfigMap", "owner", "12345-678", false)
+	withManagedFields := func(obj *unstructured.Unstructured, _ *ownerMode) {
+		obj.SetManagedFields([]metav1.ManagedFieldsEntry{{
+			Manager:   sharedFieldOwner,
+			Operation: metav1.ManagedFieldsOperationApply,
+		}})
+	}
+
+	sharedOpts := []types.ObjectReconcileOption{
+		types.WithOwner(testOwner, testOwnerStrategy), types.WithShared(),
+	}
+
+	t.Run("create", func(t *testing.T) {
+		t.Parallel()
+
+		oe, writer, _ := newTestObjectEngine(nil)
+
+		writer.
+			On("Create", mock.Anything, mock.Anything, mock.Anything).
+			Return(nil)
+
+		res, err := oe.Reconcile(t.Context(), 1, buildObj("testi", "test")(nil), sharedOpts...)
+		require.NoError(t, err)
+		assert.Equal(t, ActionCreated, res.Action())
+
+		created := writer.Calls[0].Arguments.Get(1).(*unstructured.Unstructured)
+		assert.Equal(t, map[string]string{testSystemPrefix + "/shared": "true"}, created.GetAnnotations())
+		require.Len(t, created.GetOwnerReferences(), 1)
+		assert.Nil(t, created.GetOwnerReferences()[0].Controller)
+		assert.Equal(t, []client.CreateOption{client.FieldOwner(sharedFieldOwner)},
+			writer.Calls[0].Arguments.Get(2))
+	})
+
+	t.Run("join", func(t *testing.T) {
+		t.Parallel()
+
+		oe, writer, ddm := newTestObjectEngine(buildObj("testi", "test", withShared, withOtherOwner)(nil))
+
+		ddm.
+			On("Compare", mock.Anything, mock.Anything, mock.Anything).
+			Return(CompareResult{}, nil)
+		writer.
+			On("Patch", mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+			Return(nil)
+		writer.
+			On("Apply", mock.Anything, mock.Anything, mock.Anything).
+			Return(nil)
+
+		res, err := oe.Reconcile(t.Context(), 1, buildObj("testi", "test")(nil), sharedOpts...)
+		require.NoError(t, err)
+		assert.Equal(t, ActionUpdated, res.Action())
+
+		patched := writer.Calls[0].Arguments.Get(1).(*unstructured.Unstructured)
+		assert.Len(t, patched.GetOwnerReferences(), 2)
+		writer.AssertCalled(t, "Apply", mock.Anything, mock.Anything,
+			[]client.ApplyOption{client.FieldOwner(sharedFieldOwner)})
+		ddm.AssertCalled(t, "Compare", mock.Anything, mock.Anything,
+			[]types.ComparatorOption{comparatorFieldOwner(sharedFieldOwner)})
+	})
+
+	t.Run("update keeps owner reference", func(t *testing.T) {
+		t.Parallel()
+
+		// Object as created by this owner.
+		oe, writer, _ := newTestObjectEngine(nil)
+		writer.
+			On("Create", mock.Anything, mock.Anything, mock.Anything).
+			Return(nil)
+
+		_, err := oe.Reconcile(t.Context(), 1, buildObj("testi", "test")(nil), sharedOpts...)
+		require.NoError(t, err)
+
+		created := writer.Calls[0].Arguments.Get(1).(*unstructured.Unstructured)
+		withManagedFields(created, nil)
+
+		oe, writer, ddm := newTestObjectEngine(created)
+
+		ddm.
+			On("Compare", mock.Anything, mock.Anything, mock.Anything).
+			Return(CompareResult{}, nil)
+		writer.
+			On("Apply", mock.Anything, mock.Anything, mock.Anything).
+			Return(nil)
+
+		for range 2 {
+			res, err := oe.Reconcile(t.Context(), 1, buildObj("testi", "test")(nil), sharedOpts...)
+			require.NoError(t, err)
+			assert.Equal(t, ActionUpdated, res.Action())
+		}
+
+		writer.AssertNotCalled(t, "Patch", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+		require.Len(t, writer.Calls, 2)
+
+		for i, call := range writer.Calls {
+			b, err := json.Marshal(call.Arguments.Get(1))
+			require.NoError(t, err)
+
+			applied := &unstructured.Unstructured{}
+			require.NoError(t, applied.UnmarshalJSON(b))
+			assert.Equal(t, created.GetOwnerReferences(), applied.GetOwnerReferences(), "apply %d", i)
+
+			compared := ddm.Calls[i].Arguments.Get(0).(*unstructured.Unstructured)
+			assert.Equal(t, created.GetOwnerReferences(), compared.GetOwnerReferences(), "compare %d", i)
+		}
+	})
+
+	t.Run("idle", func(t *testing.T) {
+		t.Parallel()
+
+		oe, _, ddm := newTestObjectEngine(buildObj("testi", "test", withShared, withTestOwner)(nil))
+
+		ddm.
+			On("Compare", mock.Anything, mock.Anything, mock.Anything).
+			Return(CompareResult{Comparison: &typed.Comparison{
+				Added:    &fieldpath.Set{},
+				Removed:  &fieldpath.Set{},
+				Modified: &fieldpath.Set{},
+			}}, nil)
+
+		res, err := oe.Reconcile(t.Context(), 1, buildObj("testi", "test")(nil), sharedOpts...)
+		require.NoError(t, err)
+		assert.Equal(t, ActionIdle, res.Action())
+	})
+
+	t.Run("conflicting values", func(t *testing.T) {
+		t.Parallel()
+
+		oe, writer, ddm := newTestObjectEngine(buildObj("testi", "test", withShared, withTestOwner)(nil))
+
+		otherFields := fieldpath.NewSet(fieldpath.MakePathOrDie("data", "key"))
+
+		ddm.
+			On("Compare", mock.Anything, mock.Anything, mock.Anything).
+			Return(CompareResult{
+				ConflictingMangers: []CompareResultManagedFields{
+					{Manager: testFieldOwner + "/other-uid", Fields: otherFields},
+				},
+			}, nil)
+		writer.
+			On("Apply", mock.Anything, mock.Anything, mock.Anything).
+			Return(apierrors.NewApplyConflict([]metav1.StatusCause{{
+				Type:    metav1.CauseTypeFieldManagerConflict,
+				Message: `conflict with "` + testFieldOwner + `/other-uid" using v1`,
+				Field:   ".data.key",
+			}}, "Apply failed with 1 conflict"))
+
+		res, err := oe.Reconcile(t.Context(), 1, buildObj("testi", "test")(nil), sharedOpts...)
+		require.NoError(t, err)
+		require.Equal(t, ActionCollision, res.Action())
+
+		collision := res.(ObjectResultCollision)
+		assert.Equal(t, []CompareResultManagedFields{
+			{Manager: testFieldOwner + "/other-uid", Fields: otherFields},
+		}, collision.CompareResult().ConflictingMangers)
+	})
+
+	t.Run("controlled by other", func(t *testing.T) {
+		t.Parallel()
+
+		oe, _, _ := newTestObjectEngine(buildObj("testi", "test",
+			withOwnerRef("v1", "ConfigMap", "other", "other-uid", true))(nil))
+
+		res, err := oe.Reconcile(t.Context(), 1, buildObj("testi", "test")(nil), sharedOpts...)
+		require.NoError(t, err)
+		assert.Equal(t, ActionCollision, res.Action())
+	})
+
+	t.Run("not shared", func(t *testing.T) {
+		t.Parallel()
+
+		oe, _, _ := newTestObjectEngine(buildObj("testi", "test")(nil))
+
+		res, err := oe.Reconcile(t.Context(), 1, buildObj("testi", "test")(nil), sharedOpts...)
+		require.NoError(t, err)
+		assert.Equal(t, ActionCollision, res.Action())
+	})
+
+	t.Run("without owner", func(t *testing.T) {
+		t.Parallel()
+
+		oe, _, _ := newTestObjectEngine(nil)
+
+		_, err := oe.Reconcile(t.Context(), 1, buildObj("testi", "test")(nil), types.WithShared())
+		require.ErrorIs(t, err, ErrSharedWithoutOwner)
+	})
+
+	teardownOpts := []types.ObjectTeardownOption{
+		types.WithOwner(testOwner, testOwnerStrategy), types.WithShared(),
+	}
+
+	t.Run("teardown other owner left", func(t *testing.T) {
+		t.Parallel()
+
+		oe, writer, _ := newTestObjectEngine(buildObj("testi", "test",
+			withShared, withTestOwner, withOtherOwner, withManagedFields)(nil))
+
+		writer.
+			On("Patch", mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+			Return(nil)
+		writer.
+			On("Apply", mock.Anything, mock.Anything, mock.Anything).
+			Return(nil)
+
+		gone, err := oe.Teardown(t.Context(), 1, buildObj("testi", "test")(nil), teardownOpts...)
+		require.NoError(t, err)
+		assert.True(t, gone)
+
+		patched := writer.Calls[0].Arguments.Get(1).(*unstructured.Unstructured)
+		assert.Equal(t, "other", patched.GetOwnerReferences()[0].Name)
+		assert.Len(t, patched.GetOwnerReferences(), 1)
+		writer.AssertCalled(t, "Apply", mock.Anything, mock.Anything,
+			[]client.ApplyOption{client.FieldOwner(sharedFieldOwner)})
+		writer.AssertNotCalled(t, "Delete", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("teardown last owner", func(t *testing.T) {
+		t.Parallel()
+
+		oe, writer, _ := newTestObjectEngine(buildObj("testi", "test",
+			withShared, withTestOwner, withManagedFields)(nil))
+
+		writer.
+			On("Delete", mock.Anything, mock.Anything, mock.Anything).
+			Return(nil)
+
+		gone, err := oe.Teardown(t.Context(), 1, buildObj("testi", "test")(nil), teardownOpts...)
+		require.NoError(t, err)
+		assert.False(t, gone)
+
+		writer.AssertCalled(t, "Delete", mock.Anything, mock.Anything, mock.Anything)
+		writer.AssertNotCalled(t, "Patch", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+}
@@ -0,0 +1,90 @@
+package types
+
+import (
+	"strconv"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// CheckCollision returns true if the ObjectEngine refuses to reconcile
+// the given existing object with options, because it belongs to someone else.
+// Also returns the current controller of the object, if any.
+// revision is the revision number of the reconciling revision and
+// systemPrefix the system prefix of the ObjectEngine.
+//
+// Used by the ObjectEngine and preflight validation to agree on collisions.
+func CheckCollision(
+	actual client.Object, revision int64, systemPrefix string,
+	options ObjectReconcileOptions,
+) (bool, *metav1.OwnerReference) {
+	annotations := actual.GetAnnotations()
+	actualRevision, hasRevision := annotations[systemPrefix+"/revision"]
+
+	if options.Shared {
+		return checkSharedCollision(actual, systemPrefix, options)
+	}
+
+	if options.Owner == nil {
+		// Objects with a revision annotation are managed by us.
+		return !hasRevision && options.CollisionProtection == CollisionProtectionPrevent, nil
+	}
+
+	ctrl, ok := options.OwnerStrategy.GetController(actual)
+
+	switch {
+	case !ok:
+		// Orphaned objects still carrying a revision annotation
+		// would be deleted by the garbage collector when adopted.
+		return options.CollisionProtection == CollisionProtectionPrevent || hasRevision, nil
+
+	case options.OwnerStrategy.IsController(options.Owner, actual):
+		return false, &ctrl
+
+	case options.SiblingOwnerClassifier != nil && options.SiblingOwnerClassifier(ctrl):
+		// Siblings are only expected to control objects of other revisions.
+		actualRevisionNumber, err := strconv.ParseInt(actualRevision, 10, 64)
+
+		return hasRevision && err == nil && actualRevisionNumber == revision, &ctrl
+
+	case isHandedOverTo(actual, systemPrefix, options):
+		// The current controller is releasing this object to us.
+		return false, &ctrl
+
+	default:
+		return options.CollisionProtection != CollisionProtectionNone, &ctrl
+	}
+}
+
+// checkSharedCollision mirrors CheckCollision for objects shared between owners.
+func checkSharedCollision(
+	actual client.Object, systemPrefix string, options ObjectReconcileOptions,
+) (bool, *metav1.OwnerReference) {
+	if options.Owner == nil {
+		return false, nil
+	}
+
+	// Objects with a controller are not shared.
+	if ctrl, ok := options.OwnerStrategy.GetController(actual); ok {
+		return options.CollisionProtection != CollisionProtectionNone, &ctrl
+	}
+
+	s, ok := options.OwnerStrategy.(interface {
+		IsOwner(owner, obj metav1.Object) bool
+	})
+	isOwner := ok && s.IsOwner(options.Owner, actual)
+	_, isShared := actual.GetAnnotations()[systemPrefix+"/shared"]
+
+	// Object exists, but has not been created as shared object.
+	return !isOwner && !isShared && options.CollisionProtection == CollisionProtectionPrevent, nil
+}
+
+func isHandedOverTo(obj client.Object, systemPrefix string, options ObjectReconcileOptions) bool {
+	if len(options.Owner.GetUID()) == 0 {
+		return false
+	}
+
+	uid, ok := obj.GetAnnotations()[systemPrefix+"/handover-to"]
+
+	return ok && uid == string(options.Owner.GetUID())
+}
@@ -132,7 +132,12 @@ type ObjectReconcileOptions struct {
 	Owner                  client.Object
 	OwnerStrategy          OwnerStrategy
 	Paused                 bool
-	Probes                 map[string]Prober
+	// Shared objects may be owned by multiple owners at the same time.
+	Shared bool
+	Probes map[string]Prober
+	// ProbeStateStore records probe status transitions.
+	// The ObjectEngine uses an in-memory store when unset.
+	ProbeStateStore ProbeStateStore
 }
 
 // Default sets empty Option fields to their default value.
@@ -158,6 +163,8 @@ var (
 	_ ObjectReconcileOption = (WithSiblingOwnerClassifier)(nil)
 	_ ObjectReconcileOption = (WithProbe("", nil))
 	_ ObjectTeardownOption  = (WithTeardownWriter(nil))
+	_ ObjectTeardownOption  = (WithTeardownProbe(nil))
+	_ ObjectTeardownOption  = (WithHandoverTo(""))
 )
 
 // ObjectTeardownOptions holds configuration options changing object teardown.
@@ -166,6 +173,16 @@ type ObjectTeardownOptions struct {
 	TeardownWriter client.Writer
 	Owner          client.Object
 	OwnerStrategy  OwnerStrategy
+	// Shared objects are only deleted when the last owner releases them.
+	Shared bool
+	// HandoverTo is the UID of the owner objects are handed over to,
+	// instead of deleting them.
+	HandoverTo types.UID
+	// TeardownProbes must all succeed, before a deleted object is reported gone.
+	// Probes are evaluated after the object is gone, against the last state
+	// observed before it was deleted. Probes that need live state,
+	// e.g. of dependent resources, have to do their own lookups.
+	TeardownProbes []Prober
 }
 
 // Default sets empty Option fields to their default value.
@@ -298,6 +315,15 @@ func WithProbe(t string, probe Prober) ObjectReconcileOption {
 	}
 }
 
+// WithProbeStateStore records probe status transitions in the given store.
+func WithProbeStateStore(store ProbeStateStore) ObjectReconcileOption {
+	return &optionFn{
+		fn: func(opts *ObjectReconcileOptions) {
+			opts.ProbeStateStore = store
+		},
+	}
+}
+
 // WithOrphan exclude objects from Teardown.
 // use it as WithObjectTeardownOptions(obj, WithOrphan()) to exclude individual objects or
 // use it as WithPhaseTeardownOptions("my-phase", WithOrphan()) to exclude a whole phase.
@@ -309,6 +335,45 @@ func WithOrphan() ObjectTeardownOption {
 	}
 }
 
+// WithShared marks objects as shared between multiple owners.
+// Every owner adds a non-controller owner reference and applies
+// its fields under its own field manager. Conflicting field values
+// between owners are reported as collision.
+// On teardown the owner reference is removed and
+// the object is only deleted when no other owner is left.
+// Requires an owner and an OwnerStrategy implementing
+// SetOwnerReference, IsOwner and GetOwnerReferences.
+func WithShared() interface {
+	ObjectReconcileOption
+	ObjectTeardownOption
+} {
+	return &reconcileAndTeardownOpts{
+		optionFn: optionFn{
+			fn: func(opts *ObjectReconcileOptions) {
+				opts.Shared = true
+			},
+		},
+		teardownOptionFn: teardownOptionFn{
+			fn: func(opts *ObjectTeardownOptions) {
+				opts.Shared = true
+			},
+		},
+	}
+}
+
+// WithHandoverTo hands objects over to the given new owner instead of deleting them.
+// The objects are annotated with the UID of the new owner and remain in place,
+// until an ObjectEngine reconciling them for the new owner adopted them.
+// Objects handed over are reported in the PhaseTeardownResult.
+// Requires an owner to release the objects from.
+func WithHandoverTo(newOwnerUID types.UID) ObjectTeardownOption {
+	return &teardownOptionFn{
+		fn: func(opts *ObjectTeardownOptions) {
+			opts.HandoverTo = newOwnerUID
+		},
+	}
+}
+
 // WithAggregatePhaseReconcileErrors causes phase reconciliation to aggregate all object
 // errors as a single error instead of returning on the first error.
 func WithAggregatePhaseReconcileErrors() PhaseReconcileOption {
@@ -334,6 +399,20 @@ func WithTeardownWriter(writer client.Writer) ObjectTeardownOption {
 	}
 }
 
+// WithTeardownProbe registers a probe that has to succeed before a deleted object is reported gone.
+// The probe is evaluated after the object is gone, against a snapshot of the object state
+// last seen before deletion, not against a live object. Probes needing live state have to
+// lookup related objects themselves, e.g. to wait for a PersistentVolume being reclaimed.
+// Phases are only reported complete and the next phase only starts teardown
+// when the probes of all objects succeeded.
+func WithTeardownProbe(probe Prober) ObjectTeardownOption {
+	return &teardownOptionFn{
+		fn: func(opts *ObjectTeardownOptions) {
+			opts.TeardownProbes = append(opts.TeardownProbes, probe)
+		},
+	}
+}
+
 type withObjectReconcileOptions struct {
 	obj  ObjectRef
 	opts []ObjectReconcileOption
@@ -485,9 +564,16 @@ func (copt *combinedOpts) ApplyToComparatorOptions(opts *ComparatorOptions) {
 	copt.fn(opts)
 }
 
+type reconcileAndTeardownOpts struct {
+	optionFn
+	teardownOptionFn
+}
+
 type ComparatorOptions struct {
 	Owner         client.Object
 	OwnerStrategy OwnerStrategy
+	// FieldOwner overrides the field manager to compare managed fields of.
+	FieldOwner string
 }
 
 type ComparatorOption interface {
@@ -867,3 +867,38 @@ func (m *mockOwnerStrategy) CopyOwnerReferences(objA, objB metav1.Object) {}
 func (m *mockOwnerStrategy) ReleaseController(obj metav1.Object) {}
 
 func (m *mockOwnerStrategy) RemoveOwner(owner, obj metav1.Object) {}
+
+func TestWithTeardownProbe(t *testing.T) {
+	t.Parallel()
+
+	probe := ProbeFunc(func(_ client.Object) ProbeResult {
+		return ProbeResult{Status: ProbeStatusTrue}
+	})
+
+	probeOpt := WithTeardownProbe(probe)
+
+	t.Run("applies to object teardown options", func(t *testing.T) {
+		t.Parallel()
+
+		opts := &ObjectTeardownOptions{}
+		probeOpt.ApplyToObjectTeardownOptions(opts)
+		probeOpt.ApplyToObjectTeardownOptions(opts)
+		assert.Len(t, opts.TeardownProbes, 2)
+	})
+
+	t.Run("applies to phase teardown options", func(t *testing.T) {
+		t.Parallel()
+
+		opts := &PhaseTeardownOptions{}
+		probeOpt.ApplyToPhaseTeardownOptions(opts)
+		require.Len(t, opts.DefaultObjectOptions, 1)
+	})
+
+	t.Run("applies to revision teardown options", func(t *testing.T) {
+		t.Parallel()
+
+		opts := &RevisionTeardownOptions{}
+		probeOpt.ApplyToRevisionTeardownOptions(opts)
+		require.Len(t, opts.DefaultPhaseOptions, 1)
+	})
+}
@@ -1,8 +1,11 @@
 package types
 
 import (
+	"context"
 	"fmt"
+	"time"
 
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
@@ -51,6 +54,62 @@ func (c ProbeResultContainer) Type(t string) ProbeResult {
 	}
 }
 
+// ProbeState records the status of a probe over multiple reconciliations.
+type ProbeState struct {
+	// Status of the last probe result, one of True, False, Unknown.
+	Status ProbeStatus `json:"status"`
+	// LastTransitionTime is the time the status last changed.
+	LastTransitionTime metav1.Time `json:"lastTransitionTime"`
+}
+
+// Since returns how long the probe has been in its current status.
+func (s ProbeState) Since(now time.Time) time.Duration {
+	return now.Sub(s.LastTransitionTime.Time)
+}
+
+// ProbeStateContainer holds states of multiple probes.
+type ProbeStateContainer map[string]ProbeState
+
+// Type returns the probe state for the given probe type.
+// Second return is false when no state has been recorded.
+func (c ProbeStateContainer) Type(t string) (ProbeState, bool) {
+	s, ok := c[t]
+
+	return s, ok
+}
+
+// UpdateProbeStates returns new probe states for the given results,
+// keeping the transition time of previous states with an unchanged status.
+// States of probe types without result are dropped.
+func UpdateProbeStates(
+	previous ProbeStateContainer, results ProbeResultContainer, now time.Time,
+) ProbeStateContainer {
+	states := make(ProbeStateContainer, len(results))
+
+	for t, r := range results {
+		if prev, ok := previous[t]; ok && prev.Status == r.Status {
+			states[t] = prev
+
+			continue
+		}
+
+		states[t] = ProbeState{
+			Status:             r.Status,
+			LastTransitionTime: metav1.NewTime(now),
+		}
+	}
+
+	return states
+}
+
+// ProbeStateStore records probe states of objects across reconciliations.
+type ProbeStateStore interface {
+	// Load returns the previously recorded probe states of the given object.
+	Load(ctx context.Context, obj client.Object) (ProbeStateContainer, error)
+	// Store records the probe states of the given object.
+	Store(ctx context.Context, obj client.Object, states ProbeStateContainer) error
+}
+
 // ProbeFunc wraps the given function to work with the Prober interface.
 func ProbeFunc(fn func(obj client.Object) ProbeResult) Prober {
 	return &probeFn{Fn: fn}
@@ -2,8 +2,10 @@ package types
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 func TestProbeResultContainerType(t *testing.T) {
@@ -36,3 +38,35 @@ func TestProbeResultContainerType(t *testing.T) {
 		assert.Equal(t, expected, r)
 	})
 }
+
+func TestUpdateProbeStates(t *testing.T) {
+	t.Parallel()
+
+	t0 := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	t1 := t0.Add(time.Minute)
+
+	previous := ProbeStateContainer{
+		"Unchanged": {Status: ProbeStatusTrue, LastTransitionTime: metav1.NewTime(t0)},
+		"Changed":   {Status: ProbeStatusFalse, LastTransitionTime: metav1.NewTime(t0)},
+		"Removed":   {Status: ProbeStatusTrue, LastTransitionTime: metav1.NewTime(t0)},
+	}
+	results := ProbeResultContainer{
+		"Unchanged": {Status: ProbeStatusTrue},
+		"Changed":   {Status: ProbeStatusTrue},
+		"New":       {Status: ProbeStatusUnknown},
+	}
+
+	states := UpdateProbeStates(previous, results, t1)
+	assert.Equal(t, ProbeStateContainer{
+		"Unchanged": {Status: ProbeStatusTrue, LastTransitionTime: metav1.NewTime(t0)},
+		"Changed":   {Status: ProbeStatusTrue, LastTransitionTime: metav1.NewTime(t1)},
+		"New":       {Status: ProbeStatusUnknown, LastTransitionTime: metav1.NewTime(t1)},
+	}, states)
+
+	s, ok := states.Type("Unchanged")
+	assert.True(t, ok)
+	assert.Equal(t, time.Minute, s.Since(t1))
+
+	_, ok = states.Type("Removed")
+	assert.False(t, ok)
+}
@@ -0,0 +1,69 @@
+package types
+
+import (
+	"context"
+	"sync"
+)
+
+// Warning is a non-fatal finding of a preflight check or a reconciliation,
+// e.g. the use of a deprecated API version.
+type Warning struct {
+	// ObjectRef references the object the warning was raised for.
+	ObjectRef ObjectRef
+	// Message is a human readable description of the warning.
+	Message string
+}
+
+// String returns a human readable representation of the warning.
+func (w Warning) String() string {
+	return w.ObjectRef.String() + ": " + w.Message
+}
+
+// WarningRecorder collects warnings.
+// It is safe for concurrent use.
+type WarningRecorder struct {
+	mux      sync.Mutex
+	warnings []Warning
+}
+
+// Record adds the given warnings.
+func (r *WarningRecorder) Record(warnings ...Warning) {
+	r.mux.Lock()
+	defer r.mux.Unlock()
+
+	r.warnings = append(r.warnings, warnings...)
+}
+
+// Warnings returns all recorded warnings.
+func (r *WarningRecorder) Warnings() []Warning {
+	r.mux.Lock()
+	defer r.mux.Unlock()
+
+	out := make([]Warning, len(r.warnings))
+	copy(out, r.warnings)
+
+	return out
+}
+
+type warningRecorderKey struct{}
+
+// ContextWithWarningRecorder returns a new context, collecting
+// warnings recorded with it into the given WarningRecorder.
+func ContextWithWarningRecorder(ctx context.Context, r *WarningRecorder) context.Context {
+	return context.WithValue(ctx, warningRecorderKey{}, r)
+}
+
+// WarningRecorderFromContext returns the WarningRecorder of the context or nil.
+func WarningRecorderFromContext(ctx context.Context) *WarningRecorder {
+	r, _ := ctx.Value(warningRecorderKey{}).(*WarningRecorder)
+
+	return r
+}
+
+// RecordWarning records the given warnings into the WarningRecorder of the context.
+// Warnings are dropped if the context has no WarningRecorder.
+func RecordWarning(ctx context.Context, warnings ...Warning) {
+	if r := WarningRecorderFromContext(ctx); r != nil {
+		r.Record(warnings...)
+	}
+}
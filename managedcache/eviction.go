@@ -0,0 +1,152 @@
+package managedcache
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// informerUsage describes an informer of an owners cache.
+type informerUsage struct {
+	owner    AccessManagerKey
+	gvk      schema.GroupVersionKind
+	lastRead time.Time
+	objects  int
+	// watched is true when the informer backs a watch registered via TrackingCache.Watch.
+	// Watched informers are never evicted, because their events are only delivered while running.
+	watched bool
+}
+
+// selectEvictions returns the informers to stop, least recently read first.
+// Informers are evicted when they have been idle for longer than opts.IdleTimeout,
+// and while the object count of their owner or all owners exceed the configured limits.
+// Watched informers are never evicted, but count towards the object limits.
+func selectEvictions(
+	informers []informerUsage, now time.Time,
+	opts ObjectBoundAccessManagerOptions,
+) []informerUsage {
+	lru := slices.Clone(informers)
+	slices.SortFunc(lru, func(a, b informerUsage) int {
+		return cmp.Or(
+			a.lastRead.Compare(b.lastRead),
+			cmp.Compare(a.owner.UID, b.owner.UID),
+			cmp.Compare(a.gvk.String(), b.gvk.String()),
+		)
+	})
+
+	evict := make([]bool, len(lru))
+	objectsPerOwner := map[AccessManagerKey]int{}
+
+	var objects int
+
+	for i, inf := range lru {
+		if !inf.watched && opts.IdleTimeout > 0 && now.Sub(inf.lastRead) >= opts.IdleTimeout {
+			evict[i] = true
+
+			continue
+		}
+
+		objectsPerOwner[inf.owner] += inf.objects
+		objects += inf.objects
+	}
+
+	if opts.MaxObjectsPerOwner > 0 {
+		for i, inf := range lru {
+			if evict[i] || inf.watched || objectsPerOwner[inf.owner] <= opts.MaxObjectsPerOwner {
+				continue
+			}
+
+			evict[i] = true
+			objectsPerOwner[inf.owner] -= inf.objects
+			objects -= inf.objects
+		}
+	}
+
+	if opts.MaxObjects > 0 {
+		for i, inf := range lru {
+			if objects <= opts.MaxObjects {
+				break
+			}
+
+			if evict[i] || inf.watched {
+				continue
+			}
+
+			evict[i] = true
+			objects -= inf.objects
+		}
+	}
+
+	var out []informerUsage
+
+	for i, inf := range lru {
+		if evict[i] {
+			out = append(out, inf)
+		}
+	}
+
+	return out
+}
+
+// evict stops idle informers and informers exceeding the configured object limits.
+func (m *objectBoundAccessManagerImpl[T]) evict(ctx context.Context) error {
+	log := logr.FromContextOrDiscard(ctx)
+
+	metrics, err := m.CollectMetrics(ctx)
+	if err != nil {
+		return fmt.Errorf("collecting metrics: %w", err)
+	}
+
+	m.accessorsLock.RLock()
+	defer m.accessorsLock.RUnlock()
+
+	var informers []informerUsage
+
+	for owner, entry := range m.accessors {
+		if len(entry.sharedKey) > 0 {
+			// Shared informers are stopped when their last owner is released.
+			continue
+		}
+
+		watched := entry.accessor.GetWatchedGVKs()
+
+		for gvk, lastRead := range entry.accessor.GetLastReadPerInformer() {
+			informers = append(informers, informerUsage{
+				owner:    owner,
+				gvk:      gvk,
+				lastRead: lastRead,
+				objects:  metrics[owner][gvk],
+				watched:  watched.Has(gvk),
+			})
+		}
+	}
+
+	var errs []error
+
+	for _, inf := range selectEvictions(informers, m.clock.Now(), m.options) {
+		entry, ok := m.accessors[inf.owner]
+		if !ok {
+			continue
+		}
+
+		log.V(-1).Info("evicting informer",
+			"ownerUID", inf.owner.UID, "gvk", inf.gvk, "objects", inf.objects, "lastRead", inf.lastRead)
+
+		obj := &unstructured.Unstructured{}
+		obj.SetGroupVersionKind(inf.gvk)
+
+		// RemoveInformer waits for in-flight reads to complete.
+		if err := entry.accessor.RemoveInformer(ctx, obj); err != nil {
+			errs = append(errs, fmt.Errorf("evicting informer %s of owner %s: %w", inf.gvk, inf.owner.UID, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
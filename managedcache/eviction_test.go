@@ -0,0 +1,122 @@
+package managedcache
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+func TestSelectEvictions(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	ownerA := AccessManagerKey{UID: "a"}
+	ownerB := AccessManagerKey{UID: "b"}
+	cmGVK := schema.GroupVersionKind{Version: "v1", Kind: "ConfigMap"}
+	secretGVK := schema.GroupVersionKind{Version: "v1", Kind: "Secret"}
+
+	aCM := informerUsage{owner: ownerA, gvk: cmGVK, lastRead: now.Add(-time.Hour), objects: 10}
+	aSecret := informerUsage{owner: ownerA, gvk: secretGVK, lastRead: now.Add(-time.Minute), objects: 20}
+	bCM := informerUsage{owner: ownerB, gvk: cmGVK, lastRead: now.Add(-30 * time.Minute), objects: 5}
+	informers := []informerUsage{aSecret, bCM, aCM}
+
+	tests := []struct {
+		name     string
+		opts     ObjectBoundAccessManagerOptions
+		expected []informerUsage
+	}{
+		{
+			name: "no limits",
+		},
+		{
+			name:     "idle",
+			opts:     ObjectBoundAccessManagerOptions{IdleTimeout: 10 * time.Minute},
+			expected: []informerUsage{aCM, bCM},
+		},
+		{
+			name:     "per owner",
+			opts:     ObjectBoundAccessManagerOptions{MaxObjectsPerOwner: 25},
+			expected: []informerUsage{aCM},
+		},
+		{
+			name:     "global",
+			opts:     ObjectBoundAccessManagerOptions{MaxObjects: 20},
+			expected: []informerUsage{aCM, bCM},
+		},
+		{
+			name: "idle and global",
+			opts: ObjectBoundAccessManagerOptions{
+				IdleTimeout: 45 * time.Minute,
+				MaxObjects:  20,
+			},
+			expected: []informerUsage{aCM, bCM},
+		},
+		{
+			name: "within limits",
+			opts: ObjectBoundAccessManagerOptions{
+				IdleTimeout:        2 * time.Hour,
+				MaxObjectsPerOwner: 30,
+				MaxObjects:         35,
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, test.expected, selectEvictions(informers, now, test.opts))
+		})
+	}
+}
+
+func TestSelectEvictions_Watched(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	owner := AccessManagerKey{UID: "a"}
+
+	// Watched by a controller, but never read.
+	watchedCM := informerUsage{
+		owner: owner, gvk: schema.GroupVersionKind{Version: "v1", Kind: "ConfigMap"},
+		objects: 10, watched: true,
+	}
+	secret := informerUsage{
+		owner: owner, gvk: schema.GroupVersionKind{Version: "v1", Kind: "Secret"},
+		lastRead: now.Add(-time.Minute), objects: 20,
+	}
+	informers := []informerUsage{watchedCM, secret}
+
+	tests := []struct {
+		name     string
+		opts     ObjectBoundAccessManagerOptions
+		expected []informerUsage
+	}{
+		{
+			name: "idle",
+			opts: ObjectBoundAccessManagerOptions{IdleTimeout: 10 * time.Minute},
+		},
+		{
+			name:     "per owner",
+			opts:     ObjectBoundAccessManagerOptions{MaxObjectsPerOwner: 25},
+			expected: []informerUsage{secret},
+		},
+		{
+			name:     "global",
+			opts:     ObjectBoundAccessManagerOptions{MaxObjects: 5},
+			expected: []informerUsage{secret},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, test.expected, selectEvictions(informers, now, test.opts))
+		})
+	}
+}
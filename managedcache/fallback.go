@@ -0,0 +1,235 @@
+package managedcache
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sync"
+	"time"
+
+	"github.com/go-logr/logr"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/utils/clock"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"pkg.package-operator.run/boxcutter/machinery/types"
+)
+
+// DefaultDirectReadFallbackRetryInterval is the default interval after which
+// a GVK served by direct reads tries to start its informer again.
+const DefaultDirectReadFallbackRetryInterval = 10 * time.Minute
+
+// directReadFallback serves reads of GVKs that can't be watched
+// from an uncached reader, memoizing results for a short TTL.
+// Writes through an Accessor invalidate the memo.
+// Writes of other clients are not observed before memoized entries expire.
+type directReadFallback struct {
+	reader        client.Reader
+	clock         clock.PassiveClock
+	ttl           time.Duration
+	retryInterval time.Duration
+
+	lock sync.Mutex
+	// retryAt contains the time to retry starting the informer for each GVK in fallback mode.
+	retryAt map[schema.GroupVersionKind]time.Time
+	memo    map[directReadKey]directReadEntry
+	// generation is increased by every invalidation,
+	// to not memoize reads started before.
+	generation uint64
+}
+
+type directReadKey struct {
+	gvk schema.GroupVersionKind
+	// objType is the go type of the object or list read into.
+	objType string
+	key     client.ObjectKey
+	// opts are the serialized read options.
+	opts string
+}
+
+type directReadEntry struct {
+	obj     runtime.Object
+	err     error
+	expires time.Time
+}
+
+func newDirectReadFallback(reader client.Reader, ttl, retryInterval time.Duration) *directReadFallback {
+	return &directReadFallback{
+		reader:        reader,
+		clock:         clock.RealClock{},
+		ttl:           ttl,
+		retryInterval: retryInterval,
+		retryAt:       map[schema.GroupVersionKind]time.Time{},
+		memo:          map[directReadKey]directReadEntry{},
+	}
+}
+
+// directReadInvalidator is implemented by caches memoizing direct reads.
+type directReadInvalidator interface {
+	invalidateDirectReads()
+}
+
+// invalidateDirectReads drops all memoized direct reads.
+func (c *trackingCache) invalidateDirectReads() {
+	c.fallback.invalidate()
+}
+
+// active returns true if reads of gvk are served by the fallback.
+func (f *directReadFallback) active(gvk schema.GroupVersionKind) bool {
+	if f == nil {
+		return false
+	}
+
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	retryAt, ok := f.retryAt[gvk]
+
+	return ok && f.clock.Now().Before(retryAt)
+}
+
+// activate switches gvk to direct reads if err is caused by missing permissions to watch gvk.
+// Returns true if reads of gvk are served by the fallback.
+func (f *directReadFallback) activate(ctx context.Context, gvk schema.GroupVersionKind, err error) bool {
+	if f == nil || !apierrors.IsForbidden(err) {
+		return false
+	}
+
+	logr.FromContextOrDiscard(ctx).Info(
+		"watch forbidden, falling back to direct reads", "gvk", gvk, "err", err)
+
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	f.retryAt[gvk] = f.clock.Now().Add(f.retryInterval)
+
+	return true
+}
+
+// deactivate switches gvk back to cached reads.
+func (f *directReadFallback) deactivate(gvk schema.GroupVersionKind) {
+	if f == nil {
+		return
+	}
+
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	delete(f.retryAt, gvk)
+}
+
+// invalidate drops all memoized reads.
+func (f *directReadFallback) invalidate() {
+	if f == nil {
+		return
+	}
+
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	clear(f.memo)
+	f.generation++
+}
+
+// Get retrieves obj from the memo or the uncached reader
+// and records a warning into the WarningRecorder of the context.
+func (f *directReadFallback) Get(
+	ctx context.Context, gvk schema.GroupVersionKind, key client.ObjectKey,
+	obj client.Object, opts ...client.GetOption,
+) error {
+	types.RecordWarning(ctx, types.Warning{
+		ObjectRef: types.ObjectRef{GroupVersionKind: gvk, ObjectKey: key},
+		Message:   "watch forbidden, served by uncached direct read",
+	})
+
+	getOpts := &client.GetOptions{}
+	getOpts.ApplyOptions(opts)
+
+	return f.read(directReadKey{
+		gvk:     gvk,
+		objType: fmt.Sprintf("%T", obj),
+		key:     key,
+		opts:    getOpts.AsGetOptions().String(),
+	}, obj, func() error {
+		return f.reader.Get(ctx, key, obj, opts...)
+	})
+}
+
+// List retrieves list from the memo or the uncached reader
+// and records a warning into the WarningRecorder of the context.
+func (f *directReadFallback) List(
+	ctx context.Context, gvk schema.GroupVersionKind,
+	list client.ObjectList, opts ...client.ListOption,
+) error {
+	listOpts := &client.ListOptions{}
+	listOpts.ApplyOptions(opts)
+
+	types.RecordWarning(ctx, types.Warning{
+		ObjectRef: types.ObjectRef{
+			GroupVersionKind: gvk,
+			ObjectKey:        client.ObjectKey{Namespace: listOpts.Namespace},
+		},
+		Message: "watch forbidden, list served by uncached direct read",
+	})
+
+	return f.read(directReadKey{
+		gvk:     gvk,
+		objType: fmt.Sprintf("%T", list),
+		key:     client.ObjectKey{Namespace: listOpts.Namespace},
+		opts:    listOpts.AsListOptions().String(),
+	}, list, func() error {
+		return f.reader.List(ctx, list, opts...)
+	})
+}
+
+// read serves obj from the memo, or calls read and memoizes the result.
+// Only successful reads and NotFound errors are memoized.
+func (f *directReadFallback) read(key directReadKey, obj runtime.Object, read func() error) error {
+	now := f.clock.Now()
+
+	f.lock.Lock()
+	entry, ok := f.memo[key]
+	generation := f.generation
+	f.lock.Unlock()
+
+	if ok && now.Before(entry.expires) {
+		if entry.err != nil {
+			return entry.err
+		}
+
+		reflect.ValueOf(obj).Elem().Set(reflect.ValueOf(entry.obj.DeepCopyObject()).Elem())
+
+		return nil
+	}
+
+	err := read()
+	if err != nil && !apierrors.IsNotFound(err) {
+		return err
+	}
+
+	entry = directReadEntry{err: err, expires: now.Add(f.ttl)}
+	if err == nil {
+		entry.obj = obj.DeepCopyObject()
+	}
+
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	if generation != f.generation {
+		// Invalidated while reading, result may be stale.
+		return err
+	}
+
+	// Drop expired entries to keep the memo small.
+	for k, e := range f.memo {
+		if !now.Before(e.expires) {
+			delete(f.memo, k)
+		}
+	}
+
+	f.memo[key] = entry
+
+	return err
+}
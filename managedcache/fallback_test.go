@@ -0,0 +1,214 @@
+package managedcache
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr/testr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/rest"
+	toolscache "k8s.io/client-go/tools/cache"
+	clocktesting "k8s.io/utils/clock/testing"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"pkg.package-operator.run/boxcutter/internal/testutil"
+	"pkg.package-operator.run/boxcutter/machinery/types"
+)
+
+var errForbidden = apierrors.NewForbidden(
+	schema.GroupResource{Resource: "configmaps"}, "", errSome)
+
+func TestDirectReadFallback(t *testing.T) {
+	t.Parallel()
+
+	cmGVK := schema.GroupVersionKind{Version: "v1", Kind: "ConfigMap"}
+	clk := clocktesting.NewFakePassiveClock(time.Now())
+	reader := testutil.NewClient()
+	f := newDirectReadFallback(reader, time.Minute, time.Hour)
+	f.clock = clk
+
+	reader.
+		On("Get", mock.Anything, client.ObjectKey{Name: "cm"}, mock.Anything, mock.Anything).
+		Run(func(args mock.Arguments) {
+			args.Get(2).(*corev1.ConfigMap).Data = map[string]string{"a": "b"}
+		}).
+		Return(nil)
+	reader.
+		On("Get", mock.Anything, client.ObjectKey{Name: "missing"}, mock.Anything, mock.Anything).
+		Return(apierrors.NewNotFound(schema.GroupResource{Resource: "configmaps"}, "missing"))
+	reader.
+		On("Get", mock.Anything, client.ObjectKey{Name: "broken"}, mock.Anything, mock.Anything).
+		Return(errSome)
+
+	// Activation.
+	assert.False(t, f.active(cmGVK))
+	assert.False(t, f.activate(t.Context(), cmGVK, errSome))
+	assert.True(t, f.activate(t.Context(), cmGVK, errForbidden))
+	assert.True(t, f.active(cmGVK))
+
+	// Memoized reads.
+	warnings := &types.WarningRecorder{}
+	ctx := types.ContextWithWarningRecorder(t.Context(), warnings)
+
+	for range 2 {
+		cm := &corev1.ConfigMap{}
+		require.NoError(t, f.Get(ctx, cmGVK, client.ObjectKey{Name: "cm"}, cm))
+		assert.Equal(t, map[string]string{"a": "b"}, cm.Data)
+
+		err := f.Get(ctx, cmGVK, client.ObjectKey{Name: "missing"}, &corev1.ConfigMap{})
+		require.True(t, apierrors.IsNotFound(err), "expected NotFound, got %v", err)
+
+		require.ErrorIs(t, f.Get(ctx, cmGVK, client.ObjectKey{Name: "broken"}, &corev1.ConfigMap{}), errSome)
+	}
+
+	reader.AssertNumberOfCalls(t, "Get", 4)
+	assert.Len(t, warnings.Warnings(), 6)
+	assert.Equal(t, types.ObjectRef{
+		GroupVersionKind: cmGVK,
+		ObjectKey:        client.ObjectKey{Name: "cm"},
+	}, warnings.Warnings()[0].ObjectRef)
+
+	// Memo expired.
+	clk.SetTime(clk.Now().Add(time.Minute))
+	require.NoError(t, f.Get(ctx, cmGVK, client.ObjectKey{Name: "cm"}, &corev1.ConfigMap{}))
+	reader.AssertNumberOfCalls(t, "Get", 5)
+
+	// Retry informer.
+	clk.SetTime(clk.Now().Add(time.Hour))
+	assert.False(t, f.active(cmGVK))
+
+	f.deactivate(cmGVK)
+	assert.Empty(t, f.retryAt)
+}
+
+func TestTrackingCache_DirectReadFallback(t *testing.T) {
+	t.Parallel()
+
+	cacheMock := &cacheMock{}
+	restMapperMock := &restMapperMock{}
+	directReader := testutil.NewClient()
+
+	var wrappedErrorHandler func(ctx context.Context, r *toolscache.Reflector, err error)
+
+	tc, err := newTrackingCache(
+		testr.New(t), newCacheSource(),
+		func(_ *rest.Config, opts cache.Options) (cache.Cache, error) {
+			wrappedErrorHandler = opts.DefaultWatchErrorHandler
+
+			return cacheMock, nil
+		},
+		nil, cache.Options{
+			Mapper: restMapperMock,
+			Scheme: scheme.Scheme,
+		},
+		WithDirectReader(directReader),
+		WithDirectReadFallback(time.Minute),
+	)
+	require.NoError(t, err)
+
+	itc := tc.(*trackingCache)
+
+	informerMock := &informerMock{}
+
+	cacheMock.
+		On("GetInformer", mock.Anything, mock.Anything, mock.Anything).
+		Return(informerMock, nil)
+	informerMock.
+		On("HasSynced").
+		Run(func(_ mock.Arguments) {
+			go wrappedErrorHandler(t.Context(), &toolscache.Reflector{}, errForbidden)
+		}).
+		Return(false)
+	cacheMock.
+		On("Start", mock.Anything).
+		Run(func(args mock.Arguments) {
+			<-args.Get(0).(context.Context).Done()
+		}).
+		Return(nil)
+	cacheMock.
+		On("RemoveInformer", mock.Anything, mock.Anything).
+		Return(nil)
+	restMapperMock.
+		On("KindsFor", mock.Anything).
+		Return([]schema.GroupVersionKind{
+			{Version: "v1", Kind: "ConfigMap"},
+		}, nil)
+	directReader.
+		On("Get", mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+		Return(nil)
+	directReader.
+		On("List", mock.Anything, mock.Anything, mock.Anything).
+		Return(nil)
+
+	ctx, cancel := context.WithCancel(t.Context())
+
+	var doneWG sync.WaitGroup
+
+	doneWG.Go(func() {
+		if err := tc.Start(ctx); err != nil {
+			panic(err)
+		}
+	})
+
+	// Forbidden watch falls back to direct reads.
+	require.NoError(t, itc.Get(t.Context(), client.ObjectKey{Name: "banana"}, &corev1.ConfigMap{}))
+	directReader.AssertNumberOfCalls(t, "Get", 1)
+
+	// Following reads don't try to start the informer again.
+	require.NoError(t, itc.List(t.Context(), &corev1.ConfigMapList{}))
+	directReader.AssertNumberOfCalls(t, "List", 1)
+	cacheMock.AssertNumberOfCalls(t, "GetInformer", 1)
+
+	status, err := itc.Status(t.Context())
+	require.NoError(t, err)
+	require.Len(t, status.Informers, 1)
+	assert.True(t, status.Informers[0].Stopped)
+	assert.True(t, status.Informers[0].DirectReads)
+	require.NoError(t, status.HealthError())
+	require.NoError(t, status.ReadyError())
+
+	cancel()
+	doneWG.Wait()
+}
+
+func TestAccessor_InvalidatesDirectReads(t *testing.T) {
+	t.Parallel()
+
+	cmGVK := schema.GroupVersionKind{Version: "v1", Kind: "ConfigMap"}
+	reader := testutil.NewClient()
+	writer := testutil.NewClient()
+	tc := &trackingCache{fallback: newDirectReadFallback(reader, time.Hour, time.Hour)}
+	acc := &accessor{TrackingCache: tc, Writer: writer}
+
+	reader.
+		On("Get", mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+		Return(apierrors.NewNotFound(schema.GroupResource{Resource: "configmaps"}, "cm"))
+	writer.
+		On("Create", mock.Anything, mock.Anything, mock.Anything).
+		Return(nil)
+
+	get := func() {
+		t.Helper()
+
+		err := tc.fallback.Get(t.Context(), cmGVK, client.ObjectKey{Name: "cm"}, &corev1.ConfigMap{})
+		require.True(t, apierrors.IsNotFound(err), "expected NotFound, got %v", err)
+	}
+
+	get()
+	get()
+	reader.AssertNumberOfCalls(t, "Get", 1)
+
+	// Memoized NotFound is dropped after create.
+	require.NoError(t, acc.Create(t.Context(), &corev1.ConfigMap{}))
+	get()
+	reader.AssertNumberOfCalls(t, "Get", 2)
+}
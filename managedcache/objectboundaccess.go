@@ -4,14 +4,18 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"net/http"
 	"sync"
+	"time"
 
 	"github.com/go-logr/logr"
 	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/client-go/rest"
+	"k8s.io/utils/clock"
 	"sigs.k8s.io/controller-runtime/pkg/cache"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
@@ -53,6 +57,16 @@ type ObjectBoundAccessManager[T RefType] interface {
 	GetWatchersForGVK(gvk schema.GroupVersionKind) (out []AccessManagerKey)
 
 	CollectMetrics(ctx context.Context) (ObjectsPerOwnerPerGVK, error)
+
+	// Status returns the state of the caches of all owners.
+	// The state of a single owner is available via Accessor.Status.
+	Status(ctx context.Context) (ObjectBoundAccessManagerStatus, error)
+	// Healthz reports informers stopped by watch errors across all owners.
+	// Implements healthz.Checker.
+	Healthz(req *http.Request) error
+	// Readyz reports unsynced and stopped informers across all owners.
+	// Implements healthz.Checker.
+	Readyz(req *http.Request) error
 }
 
 // ObjectsPerOwnerPerGVK is used to store data for collecting managed cache metrics.
@@ -72,23 +86,35 @@ type Accessor interface {
 }
 
 // NewObjectBoundAccessManager returns a new ObjectBoundAccessManager for T.
+// TrackingCacheOptions are passed to the TrackingCache of every Accessor.
 func NewObjectBoundAccessManager[T RefType](
 	log logr.Logger,
 	mapConfig ConfigMapperFunc[T],
 	baseRestConfig *rest.Config,
 	baseCacheOptions cache.Options,
+	opts ...ObjectBoundAccessManagerOption,
 ) ObjectBoundAccessManager[T] {
+	var options ObjectBoundAccessManagerOptions
+	for _, opt := range opts {
+		opt.ApplyToObjectBoundAccessManagerOptions(&options)
+	}
+
+	options.Default()
+
 	return &objectBoundAccessManagerImpl[T]{
 		log:              log.WithName("ObjectBoundAccessManager"),
 		restMapper:       baseCacheOptions.Mapper,
 		mapConfig:        mapConfig,
 		baseRestConfig:   baseRestConfig,
 		baseCacheOptions: baseCacheOptions,
+		options:          options,
+		clock:            clock.RealClock{},
 
 		cacheSourcer: newCacheSource(),
 		newClient:    client.New,
 
 		accessors:         map[AccessManagerKey]accessorEntry{},
+		sharedCaches:      map[string]*sharedCacheEntry{},
 		accessorRequestCh: make(chan accessorRequest[T]),
 		accessorStopCh:    make(chan accessorRequest[T]),
 	}
@@ -114,12 +140,15 @@ type objectBoundAccessManagerImpl[T RefType] struct {
 	mapConfig        ConfigMapperFunc[T]
 	baseRestConfig   *rest.Config
 	baseCacheOptions cache.Options
+	options          ObjectBoundAccessManagerOptions
+	clock            clock.PassiveClock
 
 	cacheSourcer cacheSourcer
 	newClient    newClientFunc
 
 	accessorsLock     sync.RWMutex
 	accessors         map[AccessManagerKey]accessorEntry
+	sharedCaches      map[string]*sharedCacheEntry
 	accessorRequestCh chan accessorRequest[T]
 	accessorStopCh    chan accessorRequest[T]
 }
@@ -137,6 +166,8 @@ type accessorEntry struct {
 	accessor Accessor
 	users    map[AccessManagerKey]sets.Set[schema.GroupVersionKind]
 	cancel   func()
+	// sharedKey is set when the accessor uses a shared cache.
+	sharedKey string
 }
 
 type accessorRequest[T RefType] struct {
@@ -152,8 +183,9 @@ type accessorResponse struct {
 }
 
 type cacheDone struct {
-	err error
-	key AccessManagerKey
+	err    error
+	key    AccessManagerKey
+	shared *sharedCacheEntry
 }
 
 // implements Accessor interface.
@@ -168,6 +200,52 @@ func (a *accessor) UnfilteredReader() client.Reader {
 	return a.unfilteredReader
 }
 
+// Writes through the accessor invalidate memoized direct reads of its cache,
+// so following reads observe the write, e.g. an updated resourceVersion.
+// Writes of other clients are only observed after memoized reads expired.
+
+func (a *accessor) Apply(ctx context.Context, obj runtime.ApplyConfiguration, opts ...client.ApplyOption) error {
+	defer a.invalidateDirectReads()
+
+	return a.Writer.Apply(ctx, obj, opts...)
+}
+
+func (a *accessor) Create(ctx context.Context, obj client.Object, opts ...client.CreateOption) error {
+	defer a.invalidateDirectReads()
+
+	return a.Writer.Create(ctx, obj, opts...)
+}
+
+func (a *accessor) Delete(ctx context.Context, obj client.Object, opts ...client.DeleteOption) error {
+	defer a.invalidateDirectReads()
+
+	return a.Writer.Delete(ctx, obj, opts...)
+}
+
+func (a *accessor) Update(ctx context.Context, obj client.Object, opts ...client.UpdateOption) error {
+	defer a.invalidateDirectReads()
+
+	return a.Writer.Update(ctx, obj, opts...)
+}
+
+func (a *accessor) Patch(ctx context.Context, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+	defer a.invalidateDirectReads()
+
+	return a.Writer.Patch(ctx, obj, patch, opts...)
+}
+
+func (a *accessor) DeleteAllOf(ctx context.Context, obj client.Object, opts ...client.DeleteAllOfOption) error {
+	defer a.invalidateDirectReads()
+
+	return a.Writer.DeleteAllOf(ctx, obj, opts...)
+}
+
+func (a *accessor) invalidateDirectReads() {
+	if inv, ok := a.TrackingCache.(directReadInvalidator); ok {
+		inv.invalidateDirectReads()
+	}
+}
+
 func (m *objectBoundAccessManagerImpl[T]) Source(
 	handler handler.EventHandler, predicates ...predicate.Predicate,
 ) source.Source {
@@ -182,8 +260,23 @@ func (m *objectBoundAccessManagerImpl[T]) Start(ctx context.Context) error {
 	doneCh := make(chan cacheDone)
 	defer close(doneCh)
 
+	// Informer eviction is disabled unless limits have been configured.
+	var evictCh <-chan time.Time
+
+	if m.options.evictionEnabled() {
+		ticker := time.NewTicker(m.options.EvictionInterval)
+		defer ticker.Stop()
+
+		evictCh = ticker.C
+	}
+
 	for {
 		select {
+		case <-evictCh:
+			if err := m.evict(ctx); err != nil {
+				m.log.Error(err, "evicting informers")
+			}
+
 		case done := <-doneCh:
 			if err := m.handleCacheDone(ctx, done); err != nil {
 				return err
@@ -225,6 +318,23 @@ func (m *objectBoundAccessManagerImpl[T]) handleCacheDone(
 	m.accessorsLock.Lock()
 	defer m.accessorsLock.Unlock()
 
+	if done.shared != nil {
+		// Remove all accessors of the shared cache from list.
+		for owner := range done.shared.owners {
+			delete(m.accessors, owner)
+		}
+
+		if m.sharedCaches[done.shared.key] == done.shared {
+			delete(m.sharedCaches, done.shared.key)
+		}
+
+		if done.err != nil && !errors.Is(done.err, context.Canceled) {
+			return fmt.Errorf("shared cache %s crashed: %w", done.shared.key, done.err)
+		}
+
+		return nil
+	}
+
 	// Remove accessor from list.
 	delete(m.accessors, done.key)
 
@@ -273,6 +383,11 @@ func (m *objectBoundAccessManagerImpl[T]) gcCache(ctx context.Context, owner T)
 		return nil
 	}
 
+	if len(entry.sharedKey) > 0 {
+		// Informers are shared with other owners.
+		return entry.accessor.RemoveOtherInformers(ctx, m.sharedUsedGVKs(entry.sharedKey))
+	}
+
 	inUseGVKs := sets.Set[schema.GroupVersionKind]{}
 	for _, gvks := range entry.users {
 		inUseGVKs.Insert(gvks.UnsortedList()...)
@@ -312,11 +427,6 @@ func (m *objectBoundAccessManagerImpl[T]) handleAccessorRequest(
 		return nil, fmt.Errorf("mapping rest.Config and cache.Options: %w", err)
 	}
 
-	ctrlcache, err := newTrackingCache(m.log, m.cacheSourcer, cache.New, restConfig, cacheOpts)
-	if err != nil {
-		return nil, fmt.Errorf("creating new Cache: %w", err)
-	}
-
 	client, err := m.newClient(restConfig, client.Options{
 		Mapper:     m.baseCacheOptions.Mapper,
 		HTTPClient: m.baseCacheOptions.HTTPClient,
@@ -325,6 +435,47 @@ func (m *objectBoundAccessManagerImpl[T]) handleAccessorRequest(
 		return nil, fmt.Errorf("creating new Client: %w", err)
 	}
 
+	// Default to the accessors client for direct reads, but allow overrides.
+	tcOpts := append([]TrackingCacheOption{WithDirectReader(client)}, m.options.TrackingCacheOptions...)
+
+	if m.options.OwnerSelector != nil {
+		if sharedKey, ok := sharedCacheKey(restConfig, cacheOpts); ok {
+			log = log.WithValues("sharedCacheKey", sharedKey)
+			ctx = logr.NewContext(ctx, log)
+
+			view, release, err := m.acquireSharedCache(
+				ctx, sharedKey, req.owner, restConfig, cacheOpts, tcOpts, doneCh, wg)
+			if err != nil {
+				return nil, err
+			}
+
+			entry = accessorEntry{
+				accessor: &accessor{
+					TrackingCache:    view,
+					Writer:           client,
+					unfilteredReader: client,
+				},
+				users:     map[AccessManagerKey]sets.Set[schema.GroupVersionKind]{},
+				cancel:    release,
+				sharedKey: sharedKey,
+			}
+			if req.user != nil {
+				entry.users[toAccessManagerKey(req.user)] = req.gvks
+			}
+
+			m.accessors[key] = entry
+
+			log.V(-1).Info("using shared cache")
+
+			return entry.accessor, nil
+		}
+	}
+
+	ctrlcache, err := newTrackingCache(m.log, m.cacheSourcer, cache.New, restConfig, cacheOpts, tcOpts...)
+	if err != nil {
+		return nil, fmt.Errorf("creating new Cache: %w", err)
+	}
+
 	// start cache
 	ctx, cancel := context.WithCancel(ctx)
 	a := &accessor{
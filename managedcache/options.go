@@ -0,0 +1,194 @@
+package managedcache
+
+import (
+	"time"
+
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// TrackingCacheOptions holds configuration options changing the TrackingCache.
+type TrackingCacheOptions struct {
+	// MetadataOnly contains GVKs backed by PartialObjectMetadata informers.
+	// Reads of full objects of these GVKs fall through to the DirectReader.
+	// The ObjectEngine always reads full objects, see WithMetadataOnly.
+	MetadataOnly sets.Set[schema.GroupVersionKind]
+	// DirectReader is used for reads not served from cache.
+	// Defaults to a new uncached client when MetadataOnly GVKs
+	// or DirectReadFallbackTTL are configured.
+	DirectReader client.Reader
+	// DirectReadFallbackTTL enables serving GVKs that can't be watched due to missing
+	// permissions from the DirectReader. Results are memoized for the given duration.
+	// Without a watch, changes made by other clients are only observed after the TTL expired,
+	// reads may return objects up to TTL old.
+	DirectReadFallbackTTL time.Duration
+	// DirectReadFallbackRetryInterval is the interval after which a GVK served
+	// by direct reads tries to start its informer again.
+	DirectReadFallbackRetryInterval time.Duration
+}
+
+// Default sets empty Option fields to their default value.
+func (opts *TrackingCacheOptions) Default() {
+	if opts.DirectReadFallbackRetryInterval == 0 {
+		opts.DirectReadFallbackRetryInterval = DefaultDirectReadFallbackRetryInterval
+	}
+}
+
+// TrackingCacheOption is the common interface for TrackingCache options.
+// TrackingCacheOptions passed to an ObjectBoundAccessManager apply to the TrackingCache of every Accessor.
+type TrackingCacheOption interface {
+	ApplyToTrackingCacheOptions(opts *TrackingCacheOptions)
+	ObjectBoundAccessManagerOption
+}
+
+// DefaultEvictionInterval is the default interval to check for informers to evict.
+const DefaultEvictionInterval = time.Minute
+
+// ObjectBoundAccessManagerOptions holds configuration options changing the ObjectBoundAccessManager.
+type ObjectBoundAccessManagerOptions struct {
+	// TrackingCacheOptions applying to the TrackingCache of every Accessor.
+	TrackingCacheOptions []TrackingCacheOption
+	// IdleTimeout stops informers that have not been read for the given duration.
+	IdleTimeout time.Duration
+	// MaxObjectsPerOwner limits the number of objects cached per owner.
+	MaxObjectsPerOwner int
+	// MaxObjects limits the number of objects cached across all owners.
+	MaxObjects int
+	// EvictionInterval is the interval to check for informers to evict.
+	EvictionInterval time.Duration
+	// OwnerSelector enables sharing informers between owners with equivalent cache configuration.
+	// Returns the label selector filtering the objects of each owner.
+	OwnerSelector OwnerSelectorFunc
+}
+
+// OwnerSelectorFunc returns a label selector matching all objects belonging to owner.
+type OwnerSelectorFunc func(owner client.Object) (labels.Selector, error)
+
+// Default sets empty Option fields to their default value.
+func (opts *ObjectBoundAccessManagerOptions) Default() {
+	if opts.EvictionInterval == 0 {
+		opts.EvictionInterval = DefaultEvictionInterval
+	}
+}
+
+// evictionEnabled returns true if any eviction limit has been configured.
+func (opts ObjectBoundAccessManagerOptions) evictionEnabled() bool {
+	return opts.IdleTimeout > 0 || opts.MaxObjectsPerOwner > 0 || opts.MaxObjects > 0
+}
+
+// ObjectBoundAccessManagerOption is the common interface for ObjectBoundAccessManager options.
+type ObjectBoundAccessManagerOption interface {
+	ApplyToObjectBoundAccessManagerOptions(opts *ObjectBoundAccessManagerOptions)
+}
+
+type trackingCacheOptionFn func(opts *TrackingCacheOptions)
+
+func (ofn trackingCacheOptionFn) ApplyToTrackingCacheOptions(opts *TrackingCacheOptions) {
+	ofn(opts)
+}
+
+func (ofn trackingCacheOptionFn) ApplyToObjectBoundAccessManagerOptions(opts *ObjectBoundAccessManagerOptions) {
+	opts.TrackingCacheOptions = append(opts.TrackingCacheOptions, ofn)
+}
+
+type objectBoundAccessManagerOptionFn func(opts *ObjectBoundAccessManagerOptions)
+
+func (ofn objectBoundAccessManagerOptionFn) ApplyToObjectBoundAccessManagerOptions(
+	opts *ObjectBoundAccessManagerOptions,
+) {
+	ofn(opts)
+}
+
+// WithMetadataOnly backs the cache for the given GVKs with PartialObjectMetadata informers.
+// Useful to reduce memory usage for large objects, e.g. Secrets or ConfigMaps,
+// that are only needed for ownership and existence checks.
+//
+// Only reads of metav1.PartialObjectMetadata are served from the cache.
+// Every Get or List of full objects, e.g. *unstructured.Unstructured,
+// is an uncached request to the kube-apiserver via the DirectReader.
+// The ObjectEngine reads full objects to compare them, so every reconcile and teardown
+// of an object of these GVKs issues a direct read. Trade memory for API requests
+// only for GVKs with large objects and a low number of reconciles.
+func WithMetadataOnly(gvks ...schema.GroupVersionKind) TrackingCacheOption {
+	return trackingCacheOptionFn(func(opts *TrackingCacheOptions) {
+		if opts.MetadataOnly == nil {
+			opts.MetadataOnly = sets.New[schema.GroupVersionKind]()
+		}
+
+		opts.MetadataOnly.Insert(gvks...)
+	})
+}
+
+// WithDirectReader sets the reader used for reads not served from cache.
+func WithDirectReader(reader client.Reader) TrackingCacheOption {
+	return trackingCacheOptionFn(func(opts *TrackingCacheOptions) {
+		opts.DirectReader = reader
+	})
+}
+
+// WithDirectReadFallback serves GVKs that can't be watched due to missing permissions
+// from the DirectReader, instead of failing every read.
+// Results are memoized for ttl and every fallback read is recorded as types.Warning
+// into the WarningRecorder of the context, if present.
+// Writes through an Accessor invalidate memoized reads, but writes of other clients,
+// e.g. other controllers or users, are not observed until the memoized read expires.
+// Keep ttl below the requeue interval of the reconciler to not act on stale objects.
+func WithDirectReadFallback(ttl time.Duration) TrackingCacheOption {
+	return trackingCacheOptionFn(func(opts *TrackingCacheOptions) {
+		opts.DirectReadFallbackTTL = ttl
+	})
+}
+
+// WithDirectReadFallbackRetryInterval sets the interval after which a GVK
+// served by direct reads tries to start its informer again.
+// Defaults to DefaultDirectReadFallbackRetryInterval.
+func WithDirectReadFallbackRetryInterval(interval time.Duration) TrackingCacheOption {
+	return trackingCacheOptionFn(func(opts *TrackingCacheOptions) {
+		opts.DirectReadFallbackRetryInterval = interval
+	})
+}
+
+// WithIdleTimeout stops informers that have not been read for the given duration.
+// Stopped informers are restarted on the next read.
+func WithIdleTimeout(timeout time.Duration) ObjectBoundAccessManagerOption {
+	return objectBoundAccessManagerOptionFn(func(opts *ObjectBoundAccessManagerOptions) {
+		opts.IdleTimeout = timeout
+	})
+}
+
+// WithMaxObjectsPerOwner limits the number of objects cached per owner.
+// Least recently read informers of an owner are stopped when exceeding the limit.
+func WithMaxObjectsPerOwner(limit int) ObjectBoundAccessManagerOption {
+	return objectBoundAccessManagerOptionFn(func(opts *ObjectBoundAccessManagerOptions) {
+		opts.MaxObjectsPerOwner = limit
+	})
+}
+
+// WithMaxObjects limits the number of objects cached across all owners.
+// Least recently read informers are stopped when exceeding the budget.
+func WithMaxObjects(budget int) ObjectBoundAccessManagerOption {
+	return objectBoundAccessManagerOptionFn(func(opts *ObjectBoundAccessManagerOptions) {
+		opts.MaxObjects = budget
+	})
+}
+
+// WithEvictionInterval sets the interval to check for informers to evict.
+func WithEvictionInterval(interval time.Duration) ObjectBoundAccessManagerOption {
+	return objectBoundAccessManagerOptionFn(func(opts *ObjectBoundAccessManagerOptions) {
+		opts.EvictionInterval = interval
+	})
+}
+
+// WithSharedInformers shares one cache and its informers between all owners
+// with equivalent rest config identity, namespaces and selectors returned by the ConfigMapperFunc.
+// Each owner only sees objects matching the label selector returned by ownerSelector.
+// Owners with ByObject cache options, a custom HTTPClient, or rest configs with
+// auth or exec providers, custom transports, dialers, proxies or insecure TLS never share.
+// Other cache options, e.g. transform functions, must not differ between owners.
+func WithSharedInformers(ownerSelector OwnerSelectorFunc) ObjectBoundAccessManagerOption {
+	return objectBoundAccessManagerOptionFn(func(opts *ObjectBoundAccessManagerOptions) {
+		opts.OwnerSelector = ownerSelector
+	})
+}
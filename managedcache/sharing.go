@@ -0,0 +1,241 @@
+package managedcache
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"slices"
+	"sync"
+
+	"github.com/go-logr/logr"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/rest"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+)
+
+// sharedCacheEntry is a cache shared between owners with equivalent cache configuration.
+type sharedCacheEntry struct {
+	key    string
+	cache  *trackingCache
+	owners sets.Set[AccessManagerKey]
+	cancel func()
+}
+
+// acquireSharedCache returns a view of the shared cache for the given configuration,
+// filtered by the label selector of owner. Starts the shared cache if needed.
+// The returned release func must be called when owner no longer needs the cache.
+// Needs a lock on m.accessorsLock.
+func (m *objectBoundAccessManagerImpl[T]) acquireSharedCache(
+	ctx context.Context, sharedKey string, owner T,
+	restConfig *rest.Config, cacheOpts cache.Options, tcOpts []TrackingCacheOption,
+	doneCh chan<- cacheDone, wg *sync.WaitGroup,
+) (TrackingCache, func(), error) {
+	selector, err := m.options.OwnerSelector(owner)
+	if err != nil {
+		return nil, nil, fmt.Errorf("getting owner selector: %w", err)
+	}
+
+	shared, ok := m.sharedCaches[sharedKey]
+	if !ok {
+		tc, err := newTrackingCache(m.log, m.cacheSourcer, cache.New, restConfig, cacheOpts, tcOpts...)
+		if err != nil {
+			return nil, nil, fmt.Errorf("creating new Cache: %w", err)
+		}
+
+		ctx, cancel := context.WithCancel(ctx)
+		shared = &sharedCacheEntry{
+			key:    sharedKey,
+			cache:  tc.(*trackingCache),
+			owners: sets.New[AccessManagerKey](),
+			cancel: cancel,
+		}
+		m.sharedCaches[sharedKey] = shared
+
+		logr.FromContextOrDiscard(ctx).V(-1).Info("starting new shared cache")
+		wg.Add(1)
+
+		go func(ctx context.Context, doneCh chan<- cacheDone) {
+			defer wg.Done()
+
+			doneCh <- cacheDone{shared: shared, err: shared.cache.Start(ctx)}
+		}(ctx, doneCh)
+	}
+
+	ownerKey := toAccessManagerKey(owner)
+	shared.owners.Insert(ownerKey)
+
+	release := func() {
+		shared.owners.Delete(ownerKey)
+
+		if shared.owners.Len() > 0 {
+			return
+		}
+
+		// No owners left -> close.
+		shared.cancel()
+
+		if m.sharedCaches[sharedKey] == shared {
+			delete(m.sharedCaches, sharedKey)
+		}
+	}
+
+	return &ownerCacheView{trackingCache: shared.cache, selector: selector}, release, nil
+}
+
+// sharedUsedGVKs returns the GVKs used by all owners of the shared cache.
+// Needs a lock on m.accessorsLock.
+func (m *objectBoundAccessManagerImpl[T]) sharedUsedGVKs(sharedKey string) sets.Set[schema.GroupVersionKind] {
+	inUseGVKs := sets.Set[schema.GroupVersionKind]{}
+
+	for _, entry := range m.accessors {
+		if entry.sharedKey != sharedKey {
+			continue
+		}
+
+		for _, gvks := range entry.users {
+			inUseGVKs.Insert(gvks.UnsortedList()...)
+		}
+	}
+
+	return inUseGVKs
+}
+
+// sharedCacheKey returns a key identifying equivalent cache configurations.
+// Returns false if the configuration can't be shared, because it has
+// per object settings or credentials and transports that can't be compared.
+func sharedCacheKey(cfg *rest.Config, opts cache.Options) (string, bool) {
+	if len(opts.ByObject) > 0 || opts.HTTPClient != nil || !isComparableRestConfig(cfg) {
+		return "", false
+	}
+
+	h := sha256.New()
+
+	// Identity.
+	writeKeyFields(h, cfg.Host, cfg.APIPath,
+		cfg.Username, cfg.Password, cfg.BearerToken, cfg.BearerTokenFile,
+		cfg.Impersonate.UserName, cfg.Impersonate.UID,
+		fmt.Sprint(cfg.Impersonate.Groups), fmt.Sprint(cfg.Impersonate.Extra),
+		cfg.CertFile, cfg.KeyFile, cfg.CAFile, cfg.ServerName,
+		string(cfg.CertData), string(cfg.KeyData), string(cfg.CAData))
+
+	// Namespaces.
+	namespaces := make([]string, 0, len(opts.DefaultNamespaces))
+	for ns := range opts.DefaultNamespaces {
+		namespaces = append(namespaces, ns)
+	}
+
+	slices.Sort(namespaces)
+
+	for _, ns := range namespaces {
+		nsConfig := opts.DefaultNamespaces[ns]
+		writeKeyFields(h, ns, stringOrEmpty(nsConfig.LabelSelector), stringOrEmpty(nsConfig.FieldSelector))
+	}
+
+	// Selectors.
+	writeKeyFields(h, stringOrEmpty(opts.DefaultLabelSelector), stringOrEmpty(opts.DefaultFieldSelector))
+
+	return hex.EncodeToString(h.Sum(nil)), true
+}
+
+// isComparableRestConfig returns true if the identity of cfg
+// is fully described by the fields hashed in sharedCacheKey.
+func isComparableRestConfig(cfg *rest.Config) bool {
+	return cfg.ExecProvider == nil &&
+		cfg.AuthProvider == nil &&
+		cfg.AuthConfigPersister == nil &&
+		cfg.Transport == nil &&
+		cfg.WrapTransport == nil &&
+		cfg.Dial == nil &&
+		cfg.Proxy == nil &&
+		!cfg.Insecure
+}
+
+func writeKeyFields(w io.Writer, fields ...string) {
+	for _, f := range fields {
+		// Length prefix to prevent ambiguous concatenations.
+		_, _ = fmt.Fprintf(w, "%d:%s;", len(f), f)
+	}
+}
+
+func stringOrEmpty(s fmt.Stringer) string {
+	if s == nil {
+		return ""
+	}
+
+	return s.String()
+}
+
+// ownerCacheView filters a shared TrackingCache by the label selector of a single owner.
+// Informers returned by GetInformer are shared and not filtered.
+type ownerCacheView struct {
+	*trackingCache
+
+	selector labels.Selector
+}
+
+// Get retrieves an obj for the given object key from the Kubernetes Cluster.
+// Returns NotFound if the object does not belong to the owner.
+func (v *ownerCacheView) Get(
+	ctx context.Context, key client.ObjectKey,
+	obj client.Object, opts ...client.GetOption,
+) error {
+	if err := v.trackingCache.Get(ctx, key, obj, opts...); err != nil {
+		return err
+	}
+
+	if v.selector.Matches(labels.Set(obj.GetLabels())) {
+		return nil
+	}
+
+	gvk, err := apiutil.GVKForObject(obj, v.scheme)
+	if err != nil {
+		return err
+	}
+
+	mapping, err := v.restMapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+	if err != nil {
+		return err
+	}
+
+	return apierrors.NewNotFound(mapping.Resource.GroupResource(), key.Name)
+}
+
+// List retrieves list of objects belonging to the owner for a given namespace and list options.
+func (v *ownerCacheView) List(
+	ctx context.Context, list client.ObjectList,
+	opts ...client.ListOption,
+) error {
+	return v.trackingCache.List(ctx, list, v.listOptions(opts...))
+}
+
+// GetObjectsPerInformer counts the objects belonging to the owner in each informer.
+func (v *ownerCacheView) GetObjectsPerInformer(ctx context.Context) (map[schema.GroupVersionKind]int, error) {
+	return v.objectsPerInformer(ctx, v.listOptions())
+}
+
+// listOptions returns opts restricted to objects matching the owner selector.
+func (v *ownerCacheView) listOptions(opts ...client.ListOption) *client.ListOptions {
+	listOpts := &client.ListOptions{}
+	listOpts.ApplyOptions(opts)
+
+	selector := v.selector
+	if listOpts.LabelSelector != nil {
+		reqs, selectable := listOpts.LabelSelector.Requirements()
+		if !selectable {
+			selector = labels.Nothing()
+		} else {
+			selector = selector.Add(reqs...)
+		}
+	}
+
+	listOpts.LabelSelector = selector
+
+	return listOpts
+}
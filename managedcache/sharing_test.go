@@ -0,0 +1,295 @@
+package managedcache
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"testing"
+
+	"github.com/go-logr/logr/testr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/rest"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func TestSharedCacheKey(t *testing.T) {
+	t.Parallel()
+
+	base := func() (*rest.Config, cache.Options) {
+		return &rest.Config{Host: "https://cluster", BearerToken: "token"},
+			cache.Options{
+				DefaultNamespaces: map[string]cache.Config{"a": {}, "b": {}},
+			}
+	}
+
+	baseKey, ok := sharedCacheKey(base())
+	require.True(t, ok)
+
+	tests := []struct {
+		name  string
+		mod   func(cfg *rest.Config, opts *cache.Options)
+		equal bool
+	}{
+		{
+			name:  "same",
+			mod:   func(*rest.Config, *cache.Options) {},
+			equal: true,
+		},
+		{
+			name: "host",
+			mod: func(cfg *rest.Config, _ *cache.Options) {
+				cfg.Host = "https://other"
+			},
+		},
+		{
+			name: "impersonation",
+			mod: func(cfg *rest.Config, _ *cache.Options) {
+				cfg.Impersonate.UserName = "system:serviceaccount:a:b"
+			},
+		},
+		{
+			name: "namespaces",
+			mod: func(_ *rest.Config, opts *cache.Options) {
+				opts.DefaultNamespaces = map[string]cache.Config{"a": {}}
+			},
+		},
+		{
+			name: "namespace selector",
+			mod: func(_ *rest.Config, opts *cache.Options) {
+				opts.DefaultNamespaces["a"] = cache.Config{
+					LabelSelector: labels.SelectorFromSet(labels.Set{"a": "b"}),
+				}
+			},
+		},
+		{
+			name: "label selector",
+			mod: func(_ *rest.Config, opts *cache.Options) {
+				opts.DefaultLabelSelector = labels.SelectorFromSet(labels.Set{"a": "b"})
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			cfg, opts := base()
+			test.mod(cfg, &opts)
+
+			key, ok := sharedCacheKey(cfg, opts)
+			require.True(t, ok)
+
+			if test.equal {
+				assert.Equal(t, baseKey, key)
+			} else {
+				assert.NotEqual(t, baseKey, key)
+			}
+		})
+	}
+
+	unshareable := []struct {
+		name string
+		mod  func(cfg *rest.Config, opts *cache.Options)
+	}{
+		{
+			name: "ByObject",
+			mod: func(_ *rest.Config, opts *cache.Options) {
+				opts.ByObject = map[client.Object]cache.ByObject{&corev1.Secret{}: {}}
+			},
+		},
+		{
+			name: "HTTPClient",
+			mod: func(_ *rest.Config, opts *cache.Options) {
+				opts.HTTPClient = &http.Client{}
+			},
+		},
+		{
+			name: "ExecProvider",
+			mod: func(cfg *rest.Config, _ *cache.Options) {
+				cfg.ExecProvider = &clientcmdapi.ExecConfig{Command: "creds"}
+			},
+		},
+		{
+			name: "AuthProvider",
+			mod: func(cfg *rest.Config, _ *cache.Options) {
+				cfg.AuthProvider = &clientcmdapi.AuthProviderConfig{Name: "oidc"}
+			},
+		},
+		{
+			name: "Transport",
+			mod: func(cfg *rest.Config, _ *cache.Options) {
+				cfg.Transport = http.DefaultTransport
+			},
+		},
+		{
+			name: "WrapTransport",
+			mod: func(cfg *rest.Config, _ *cache.Options) {
+				cfg.WrapTransport = func(rt http.RoundTripper) http.RoundTripper { return rt }
+			},
+		},
+		{
+			name: "Insecure",
+			mod: func(cfg *rest.Config, _ *cache.Options) {
+				cfg.Insecure = true
+			},
+		},
+	}
+
+	for _, test := range unshareable {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			cfg, opts := base()
+			test.mod(cfg, &opts)
+
+			_, ok := sharedCacheKey(cfg, opts)
+			assert.False(t, ok)
+		})
+	}
+}
+
+func TestOwnerCacheView(t *testing.T) {
+	t.Parallel()
+
+	cacheMock := &cacheMock{}
+	informerMock := &informerMock{}
+	restMapperMock := &restMapperMock{}
+
+	tc, err := newTrackingCache(
+		testr.New(t), newCacheSource(),
+		func(_ *rest.Config, _ cache.Options) (cache.Cache, error) {
+			return cacheMock, nil
+		},
+		nil, cache.Options{
+			Mapper: restMapperMock,
+			Scheme: scheme.Scheme,
+		},
+	)
+	require.NoError(t, err)
+
+	view := &ownerCacheView{
+		trackingCache: tc.(*trackingCache),
+		selector:      labels.SelectorFromSet(labels.Set{"owner": "a"}),
+	}
+
+	cacheMock.
+		On("GetInformer", mock.Anything, mock.Anything, mock.Anything).
+		Return(informerMock, nil)
+	informerMock.
+		On("HasSynced").
+		Return(true)
+	cacheMock.
+		On("Start", mock.Anything).
+		Run(func(args mock.Arguments) {
+			<-args.Get(0).(context.Context).Done()
+		}).
+		Return(nil).
+		Maybe()
+	cacheMock.
+		On("Get", mock.Anything, client.ObjectKey{Name: "mine"}, mock.Anything, mock.Anything).
+		Run(func(args mock.Arguments) {
+			args.Get(2).(*corev1.ConfigMap).Labels = map[string]string{"owner": "a"}
+		}).
+		Return(nil)
+	cacheMock.
+		On("Get", mock.Anything, client.ObjectKey{Name: "other"}, mock.Anything, mock.Anything).
+		Run(func(args mock.Arguments) {
+			args.Get(2).(*corev1.ConfigMap).Labels = map[string]string{"owner": "b"}
+		}).
+		Return(nil)
+	cacheMock.
+		On("List", mock.Anything, mock.Anything, mock.Anything).
+		Return(nil)
+	restMapperMock.
+		On("RESTMapping", schema.GroupKind{Kind: "ConfigMap"}, []string{"v1"}).
+		Return(&meta.RESTMapping{Resource: schema.GroupVersionResource{Version: "v1", Resource: "configmaps"}}, nil)
+
+	ctx, cancel := context.WithCancel(t.Context())
+
+	var doneWG sync.WaitGroup
+
+	doneWG.Go(func() {
+		if err := tc.Start(ctx); err != nil {
+			panic(err)
+		}
+	})
+
+	require.NoError(t, view.Get(t.Context(), client.ObjectKey{Name: "mine"}, &corev1.ConfigMap{}))
+
+	err = view.Get(t.Context(), client.ObjectKey{Name: "other"}, &corev1.ConfigMap{})
+	require.True(t, apierrors.IsNotFound(err), "expected NotFound, got %v", err)
+	assert.Equal(t, "configmaps", err.(apierrors.APIStatus).Status().Details.Kind)
+
+	require.NoError(t, view.List(t.Context(), &corev1.ConfigMapList{}, client.MatchingLabels{"app": "x"}))
+
+	listOpts := &client.ListOptions{}
+	listOpts.ApplyOptions(cacheMock.Calls[len(cacheMock.Calls)-1].Arguments.Get(2).([]client.ListOption))
+
+	assert.True(t, listOpts.LabelSelector.Matches(labels.Set{"owner": "a", "app": "x"}))
+	assert.False(t, listOpts.LabelSelector.Matches(labels.Set{"owner": "b", "app": "x"}))
+	assert.False(t, listOpts.LabelSelector.Matches(labels.Set{"owner": "a"}))
+
+	cancel()
+	doneWG.Wait()
+}
+
+func TestObjectBoundAccessManager_SharedCache(t *testing.T) {
+	t.Parallel()
+
+	cfg := &rest.Config{Host: "https://127.0.0.1:1"}
+	cacheOpts := cache.Options{
+		Mapper: &restMapperMock{},
+		Scheme: scheme.Scheme,
+	}
+
+	m := NewObjectBoundAccessManager[*corev1.ConfigMap](
+		testr.New(t), nil, cfg, cacheOpts,
+		WithSharedInformers(func(owner client.Object) (labels.Selector, error) {
+			return labels.SelectorFromSet(labels.Set{"owner": owner.GetName()}), nil
+		}),
+	).(*objectBoundAccessManagerImpl[*corev1.ConfigMap])
+
+	ownerA := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "a", UID: "a"}}
+	ownerB := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "b", UID: "b"}}
+
+	doneCh := make(chan cacheDone, 1)
+
+	var wg sync.WaitGroup
+
+	viewA, releaseA, err := m.acquireSharedCache(t.Context(), "key", ownerA, cfg, cacheOpts, nil, doneCh, &wg)
+	require.NoError(t, err)
+	viewB, releaseB, err := m.acquireSharedCache(t.Context(), "key", ownerB, cfg, cacheOpts, nil, doneCh, &wg)
+	require.NoError(t, err)
+
+	// One underlying cache with owner specific selectors.
+	assert.Same(t, viewA.(*ownerCacheView).trackingCache, viewB.(*ownerCacheView).trackingCache)
+	assert.True(t, viewA.(*ownerCacheView).selector.Matches(labels.Set{"owner": "a"}))
+	assert.True(t, viewB.(*ownerCacheView).selector.Matches(labels.Set{"owner": "b"}))
+
+	shared := m.sharedCaches["key"]
+	require.NotNil(t, shared)
+
+	releaseA()
+	assert.Contains(t, m.sharedCaches, "key")
+
+	releaseB()
+	assert.NotContains(t, m.sharedCaches, "key")
+
+	// Shared cache stopped after last owner released it.
+	done := <-doneCh
+	assert.Same(t, shared, done.shared)
+	require.NoError(t, done.err)
+
+	wg.Wait()
+}
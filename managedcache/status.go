@@ -0,0 +1,241 @@
+package managedcache
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"time"
+
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/healthz"
+)
+
+var (
+	_ healthz.Checker = (&trackingCache{}).Healthz
+	_ healthz.Checker = (&trackingCache{}).Readyz
+)
+
+// InformerStatus describes the state of a single informer.
+type InformerStatus struct {
+	// GVK of the objects watched by the informer.
+	GVK schema.GroupVersionKind
+	// Synced is true after the informer completed its initial list.
+	Synced bool
+	// Stopped is true when the informer has been stopped by a watch error.
+	// Stopped informers are restarted on the next read,
+	// or after the direct read fallback retry interval when DirectReads is true.
+	Stopped bool
+	// LastError is the watch error that stopped the informer.
+	LastError error
+	// LastErrorTime is the time the informer has been stopped.
+	LastErrorTime time.Time
+	// DirectReads is true when reads are served by uncached direct reads,
+	// because the informer can't watch due to missing permissions.
+	// Informers with DirectReads are degraded, but considered healthy and ready.
+	DirectReads bool
+}
+
+// TrackingCacheStatus describes the state of all informers of a TrackingCache.
+type TrackingCacheStatus struct {
+	// Informers sorted by GVK.
+	Informers []InformerStatus
+}
+
+// HealthError returns an error listing all informers stopped by watch errors,
+// that are not served by direct reads.
+// Returns nil if no informer has been stopped.
+func (s TrackingCacheStatus) HealthError() error {
+	var errs []error
+
+	for _, inf := range s.Informers {
+		if inf.Stopped && !inf.DirectReads {
+			errs = append(errs, fmt.Errorf("informer %s stopped: %w", inf.GVK, inf.LastError))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// ReadyError returns an error listing all unsynced and stopped informers,
+// that are not served by direct reads.
+// Returns nil if all informers are synced.
+func (s TrackingCacheStatus) ReadyError() error {
+	var errs []error
+
+	for _, inf := range s.Informers {
+		switch {
+		case inf.DirectReads:
+			// Degraded, but reads are served.
+		case inf.Stopped:
+			errs = append(errs, fmt.Errorf("informer %s stopped: %w", inf.GVK, inf.LastError))
+		case !inf.Synced:
+			errs = append(errs, fmt.Errorf("informer %s not synced", inf.GVK))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// ObjectBoundAccessManagerStatus describes the state of the caches of all owners.
+type ObjectBoundAccessManagerStatus struct {
+	Owners map[AccessManagerKey]TrackingCacheStatus
+}
+
+// HealthError returns an error listing all informers stopped by watch errors per owner.
+// Returns nil if no informer has been stopped.
+func (s ObjectBoundAccessManagerStatus) HealthError() error {
+	return s.join(TrackingCacheStatus.HealthError)
+}
+
+// ReadyError returns an error listing all unsynced and stopped informers per owner.
+// Returns nil if all informers are synced.
+func (s ObjectBoundAccessManagerStatus) ReadyError() error {
+	return s.join(TrackingCacheStatus.ReadyError)
+}
+
+func (s ObjectBoundAccessManagerStatus) join(ownerErr func(TrackingCacheStatus) error) error {
+	owners := make([]AccessManagerKey, 0, len(s.Owners))
+	for owner := range s.Owners {
+		owners = append(owners, owner)
+	}
+
+	slices.SortFunc(owners, func(a, b AccessManagerKey) int {
+		return cmp.Compare(a.UID, b.UID)
+	})
+
+	var errs []error
+
+	for _, owner := range owners {
+		if err := ownerErr(s.Owners[owner]); err != nil {
+			errs = append(errs, fmt.Errorf("owner %s %s: %w", owner.GroupVersionKind.Kind, owner.ObjectKey, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// stoppedInformer records the watch error that stopped an informer.
+type stoppedInformer struct {
+	err  error
+	time time.Time
+}
+
+// Status returns the state of all informers.
+func (c *trackingCache) Status(ctx context.Context) (TrackingCacheStatus, error) {
+	statusCh := make(chan TrackingCacheStatus, 1)
+	req := trackingCacheRequest{
+		do: func(_ context.Context) {
+			statusCh <- c.status()
+		},
+	}
+
+	select {
+	case c.gvkRequestCh <- req:
+	case <-ctx.Done():
+		return TrackingCacheStatus{}, ctx.Err()
+	}
+
+	select {
+	case status := <-statusCh:
+		return status, nil
+	case <-ctx.Done():
+		return TrackingCacheStatus{}, ctx.Err()
+	}
+}
+
+// status, only call this within the main control goroutine.
+func (c *trackingCache) status() TrackingCacheStatus {
+	var status TrackingCacheStatus
+
+	for gvk := range c.knownInformers {
+		_, waiting := c.cacheWaitInFlight[gvk]
+		status.Informers = append(status.Informers, InformerStatus{
+			GVK:    gvk,
+			Synced: !waiting,
+		})
+	}
+
+	for gvk, stopped := range c.stoppedInformers {
+		status.Informers = append(status.Informers, InformerStatus{
+			GVK:           gvk,
+			Stopped:       true,
+			LastError:     stopped.err,
+			LastErrorTime: stopped.time,
+			DirectReads:   c.fallback.active(gvk),
+		})
+	}
+
+	slices.SortFunc(status.Informers, func(a, b InformerStatus) int {
+		return cmp.Compare(a.GVK.String(), b.GVK.String())
+	})
+
+	return status
+}
+
+// Healthz reports informers stopped by watch errors.
+// Implements healthz.Checker.
+func (c *trackingCache) Healthz(req *http.Request) error {
+	status, err := c.Status(req.Context())
+	if err != nil {
+		return err
+	}
+
+	return status.HealthError()
+}
+
+// Readyz reports unsynced and stopped informers.
+// Implements healthz.Checker.
+func (c *trackingCache) Readyz(req *http.Request) error {
+	status, err := c.Status(req.Context())
+	if err != nil {
+		return err
+	}
+
+	return status.ReadyError()
+}
+
+// Status returns the state of the caches of all owners.
+func (m *objectBoundAccessManagerImpl[T]) Status(ctx context.Context) (ObjectBoundAccessManagerStatus, error) {
+	m.accessorsLock.RLock()
+	defer m.accessorsLock.RUnlock()
+
+	status := ObjectBoundAccessManagerStatus{
+		Owners: make(map[AccessManagerKey]TrackingCacheStatus, len(m.accessors)),
+	}
+
+	for owner, entry := range m.accessors {
+		ownerStatus, err := entry.accessor.Status(ctx)
+		if err != nil {
+			return ObjectBoundAccessManagerStatus{}, fmt.Errorf("getting status of owner '%s': %w", owner.UID, err)
+		}
+
+		status.Owners[owner] = ownerStatus
+	}
+
+	return status, nil
+}
+
+// Healthz reports informers stopped by watch errors across all owners.
+// Implements healthz.Checker.
+func (m *objectBoundAccessManagerImpl[T]) Healthz(req *http.Request) error {
+	status, err := m.Status(req.Context())
+	if err != nil {
+		return err
+	}
+
+	return status.HealthError()
+}
+
+// Readyz reports unsynced and stopped informers across all owners.
+// Implements healthz.Checker.
+func (m *objectBoundAccessManagerImpl[T]) Readyz(req *http.Request) error {
+	status, err := m.Status(req.Context())
+	if err != nil {
+		return err
+	}
+
+	return status.ReadyError()
+}
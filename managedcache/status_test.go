@@ -0,0 +1,99 @@
+package managedcache
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func TestObjectBoundAccessManagerStatus(t *testing.T) {
+	t.Parallel()
+
+	cmGVK := schema.GroupVersionKind{Version: "v1", Kind: "ConfigMap"}
+	secretGVK := schema.GroupVersionKind{Version: "v1", Kind: "Secret"}
+	ownerA := AccessManagerKey{
+		UID:              "a",
+		ObjectKey:        client.ObjectKey{Namespace: "ns", Name: "a"},
+		GroupVersionKind: schema.GroupVersionKind{Version: "v1", Kind: "Owner"},
+	}
+	ownerB := AccessManagerKey{
+		UID:              "b",
+		ObjectKey:        client.ObjectKey{Namespace: "ns", Name: "b"},
+		GroupVersionKind: schema.GroupVersionKind{Version: "v1", Kind: "Owner"},
+	}
+
+	tests := []struct {
+		name     string
+		status   ObjectBoundAccessManagerStatus
+		health   string
+		ready    string
+		noErrors bool
+	}{
+		{
+			name:     "empty",
+			noErrors: true,
+		},
+		{
+			name: "synced",
+			status: ObjectBoundAccessManagerStatus{Owners: map[AccessManagerKey]TrackingCacheStatus{
+				ownerA: {Informers: []InformerStatus{{GVK: cmGVK, Synced: true}}},
+			}},
+			noErrors: true,
+		},
+		{
+			name: "unsynced",
+			status: ObjectBoundAccessManagerStatus{Owners: map[AccessManagerKey]TrackingCacheStatus{
+				ownerA: {Informers: []InformerStatus{{GVK: cmGVK, Synced: true}, {GVK: secretGVK}}},
+			}},
+			ready: "owner Owner ns/a: informer /v1, Kind=Secret not synced",
+		},
+		{
+			name: "direct reads",
+			status: ObjectBoundAccessManagerStatus{Owners: map[AccessManagerKey]TrackingCacheStatus{
+				ownerA: {Informers: []InformerStatus{
+					{GVK: secretGVK, Stopped: true, LastError: errSome, DirectReads: true},
+				}},
+			}},
+			noErrors: true,
+		},
+		{
+			name: "stopped",
+			status: ObjectBoundAccessManagerStatus{Owners: map[AccessManagerKey]TrackingCacheStatus{
+				ownerB: {Informers: []InformerStatus{{GVK: secretGVK, Stopped: true, LastError: errSome}}},
+				ownerA: {Informers: []InformerStatus{{GVK: cmGVK}}},
+			}},
+			health: "owner Owner ns/b: informer /v1, Kind=Secret stopped: boom",
+			ready: "owner Owner ns/a: informer /v1, Kind=ConfigMap not synced\n" +
+				"owner Owner ns/b: informer /v1, Kind=Secret stopped: boom",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			if test.noErrors {
+				require.NoError(t, test.status.HealthError())
+				require.NoError(t, test.status.ReadyError())
+
+				return
+			}
+
+			if test.health == "" {
+				require.NoError(t, test.status.HealthError())
+			} else {
+				require.EqualError(t, test.status.HealthError(), test.health)
+			}
+
+			require.EqualError(t, test.status.ReadyError(), test.ready)
+		})
+	}
+
+	stopped := ObjectBoundAccessManagerStatus{Owners: map[AccessManagerKey]TrackingCacheStatus{
+		ownerA: {Informers: []InformerStatus{{GVK: cmGVK, Stopped: true, LastError: errSome}}},
+	}}
+	assert.ErrorIs(t, stopped.HealthError(), errSome)
+}
@@ -4,19 +4,23 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"net/http"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/go-logr/logr"
 	"golang.org/x/sync/errgroup"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/client-go/rest"
 	toolscache "k8s.io/client-go/tools/cache"
+	"k8s.io/utils/clock"
 	"sigs.k8s.io/controller-runtime/pkg/cache"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
@@ -41,8 +45,21 @@ type TrackingCache interface {
 
 	Watch(ctx context.Context, user client.Object, gvks sets.Set[schema.GroupVersionKind]) error
 	Free(ctx context.Context, user client.Object) error
+	// GetWatchedGVKs returns the GVKs watched by all users registered via Watch.
+	GetWatchedGVKs() sets.Set[schema.GroupVersionKind]
 
 	GetObjectsPerInformer(ctx context.Context) (map[schema.GroupVersionKind]int, error)
+	// GetLastReadPerInformer returns the last time each informer has been read from.
+	GetLastReadPerInformer() map[schema.GroupVersionKind]time.Time
+
+	// Status returns the state of all informers.
+	Status(ctx context.Context) (TrackingCacheStatus, error)
+	// Healthz reports informers stopped by watch errors.
+	// Implements healthz.Checker.
+	Healthz(req *http.Request) error
+	// Readyz reports unsynced and stopped informers.
+	// Implements healthz.Checker.
+	Readyz(req *http.Request) error
 }
 
 type cacheSourcer interface {
@@ -59,6 +76,13 @@ type trackingCache struct {
 	cacheSourcer cacheSourcer
 	scheme       *runtime.Scheme
 
+	// GVKs backed by PartialObjectMetadata informers.
+	metadataOnly sets.Set[schema.GroupVersionKind]
+	// Serves reads of full objects for metadataOnly GVKs.
+	directReader client.Reader
+	// Serves reads of GVKs that can't be watched, nil if disabled.
+	fallback *directReadFallback
+
 	// Guards against informers getting removed
 	// while someone is still reading.
 	accessLock sync.RWMutex
@@ -67,6 +91,8 @@ type trackingCache struct {
 	gvkRequestCh      chan trackingCacheRequest
 	informerSyncCh    chan informerSyncResponse
 	knownInformers    sets.Set[schema.GroupVersionKind]
+	// stoppedInformers contains informers stopped by watch errors until they are restarted.
+	stoppedInformers map[schema.GroupVersionKind]stoppedInformer
 
 	// waitingForSync contains a slice of error channels for each GVK.
 	// The error channels are waiting for the initial cache sync of the GVK's associated informer.
@@ -80,6 +106,11 @@ type trackingCache struct {
 	// Watches by user
 	watchesByUser     map[AccessManagerKey]sets.Set[schema.GroupVersionKind]
 	watchesByUserLock sync.Mutex
+
+	// Last read per informer, to evict idle informers.
+	clock        clock.PassiveClock
+	lastRead     map[schema.GroupVersionKind]time.Time
+	lastReadLock sync.Mutex
 }
 
 type informerSyncResponse struct {
@@ -94,27 +125,57 @@ type trackingCacheRequest struct {
 type newCacheFn func(cfg *rest.Config, opts cache.Options) (cache.Cache, error)
 
 // NewTrackingCache returns a new TrackingCache instance.
-func NewTrackingCache(log logr.Logger, config *rest.Config, opts cache.Options) (TrackingCache, error) {
-	return newTrackingCache(log, newCacheSource(), cache.New, config, opts)
+func NewTrackingCache(
+	log logr.Logger, config *rest.Config, opts cache.Options,
+	tcOpts ...TrackingCacheOption,
+) (TrackingCache, error) {
+	return newTrackingCache(log, newCacheSource(), cache.New, config, opts, tcOpts...)
 }
 
 func newTrackingCache(
 	log logr.Logger, cacheSourcer cacheSourcer, newCache newCacheFn,
 	config *rest.Config, opts cache.Options,
+	tcOpts ...TrackingCacheOption,
 ) (TrackingCache, error) {
+	var options TrackingCacheOptions
+	for _, opt := range tcOpts {
+		opt.ApplyToTrackingCacheOptions(&options)
+	}
+
+	options.Default()
+
+	if options.DirectReader == nil &&
+		(options.MetadataOnly.Len() > 0 || options.DirectReadFallbackTTL > 0) {
+		directReader, err := client.New(config, client.Options{
+			Scheme:     opts.Scheme,
+			Mapper:     opts.Mapper,
+			HTTPClient: opts.HTTPClient,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("creating direct reader: %w", err)
+		}
+
+		options.DirectReader = directReader
+	}
+
 	wehc := &trackingCache{
 		log:          log.WithName("TrackingCache"),
 		restMapper:   opts.Mapper,
 		cacheSourcer: cacheSourcer,
 		scheme:       opts.Scheme,
+		metadataOnly: options.MetadataOnly,
+		directReader: options.DirectReader,
 
 		cacheWatchErrorCh: make(chan error),
 		gvkRequestCh:      make(chan trackingCacheRequest),
 		informerSyncCh:    make(chan informerSyncResponse),
 		knownInformers:    sets.Set[schema.GroupVersionKind]{},
+		stoppedInformers:  map[schema.GroupVersionKind]stoppedInformer{},
 		waitingForSync:    map[schema.GroupVersionKind][]chan error{},
 		cacheWaitInFlight: map[schema.GroupVersionKind]chan struct{}{},
 		watchesByUser:     map[AccessManagerKey]sets.Set[schema.GroupVersionKind]{},
+		clock:             clock.RealClock{},
+		lastRead:          map[schema.GroupVersionKind]time.Time{},
 	}
 	errHandler := opts.DefaultWatchErrorHandler
 	opts.DefaultWatchErrorHandler = func(ctx context.Context, r *toolscache.Reflector, err error) {
@@ -138,6 +199,11 @@ func newTrackingCache(
 
 	wehc.Cache = c
 
+	if options.DirectReadFallbackTTL > 0 {
+		wehc.fallback = newDirectReadFallback(
+			options.DirectReader, options.DirectReadFallbackTTL, options.DirectReadFallbackRetryInterval)
+	}
+
 	return wehc, nil
 }
 
@@ -221,35 +287,9 @@ func (c *trackingCache) handleCacheWatchError(ctx context.Context, err error) er
 	return nil
 }
 
-func (c *trackingCache) ensureCacheSync(ctx context.Context, obj client.Object) error {
-	gvk, err := apiutil.GVKForObject(obj, c.scheme)
-	if err != nil {
-		return err
-	}
-
-	if err := c.ensureCacheSyncForGVK(ctx, gvk); err != nil {
-		return fmt.Errorf("ensuring cache sync for GVK: %w", err)
-	}
-
-	return nil
-}
-
-func (c *trackingCache) ensureCacheSyncList(ctx context.Context, list client.ObjectList) error {
-	gvk, err := apiutil.GVKForObject(list, c.scheme)
-	if err != nil {
-		return err
-	}
-	// We need the non-list GVK, so chop off the "List" from the end of the kind.
-	gvk.Kind = strings.TrimSuffix(gvk.Kind, "List")
-
-	if err := c.ensureCacheSyncForGVK(ctx, gvk); err != nil {
-		return fmt.Errorf("ensuring cache sync for (list) GVK: %w", err)
-	}
-
-	return nil
-}
-
 func (c *trackingCache) ensureCacheSyncForGVK(ctx context.Context, gvk schema.GroupVersionKind) error {
+	c.touch(gvk)
+
 	errCh := make(chan error, 1)
 
 	// This goroutine MUST NOT defer close(errCh),
@@ -269,10 +309,7 @@ func (c *trackingCache) ensureCacheSyncForGVK(ctx context.Context, gvk schema.Gr
 				return
 			}
 
-			obj := &unstructured.Unstructured{}
-			obj.SetGroupVersionKind(gvk)
-
-			i, err := c.Cache.GetInformer(ctx, obj, cache.BlockUntilSynced(false))
+			i, err := c.Cache.GetInformer(ctx, c.informerObject(gvk), cache.BlockUntilSynced(false))
 			if err != nil {
 				errCh <- err
 
@@ -283,6 +320,7 @@ func (c *trackingCache) ensureCacheSyncForGVK(ctx context.Context, gvk schema.Gr
 			isNewInformer := !c.knownInformers.Has(gvk)
 			if isNewInformer {
 				c.knownInformers.Insert(gvk)
+				delete(c.stoppedInformers, gvk)
 
 				if err := c.cacheSourcer.handleNewInformer(i); err != nil {
 					errCh <- err
@@ -337,11 +375,31 @@ func (c *trackingCache) Get(
 	c.accessLock.RLock()
 	defer c.accessLock.RUnlock()
 
-	if err := c.ensureCacheSync(ctx, obj); err != nil {
+	gvk, err := apiutil.GVKForObject(obj, c.scheme)
+	if err != nil {
 		return err
 	}
 
-	err := c.Cache.Get(ctx, key, obj, opts...)
+	if c.isDirectRead(gvk, obj) {
+		// Full object requested, but only metadata is cached.
+		return c.directReader.Get(ctx, key, obj, opts...)
+	}
+
+	if c.fallback.active(gvk) {
+		return c.fallback.Get(ctx, gvk, key, obj, opts...)
+	}
+
+	if err := c.ensureCacheSyncForGVK(ctx, gvk); err != nil {
+		if c.fallback.activate(ctx, gvk, err) {
+			return c.fallback.Get(ctx, gvk, key, obj, opts...)
+		}
+
+		return fmt.Errorf("ensuring cache sync for GVK: %w", err)
+	}
+
+	c.fallback.deactivate(gvk)
+
+	err = c.Cache.Get(ctx, key, obj, opts...)
 	if err != nil {
 		return fmt.Errorf("getting object: %w", err)
 	}
@@ -356,9 +414,31 @@ func (c *trackingCache) List(
 	c.accessLock.RLock()
 	defer c.accessLock.RUnlock()
 
-	if err := c.ensureCacheSyncList(ctx, list); err != nil {
+	gvk, err := apiutil.GVKForObject(list, c.scheme)
+	if err != nil {
 		return err
 	}
+	// We need the non-list GVK, so chop off the "List" from the end of the kind.
+	gvk.Kind = strings.TrimSuffix(gvk.Kind, "List")
+
+	if c.isDirectRead(gvk, list) {
+		// Full objects requested, but only metadata is cached.
+		return c.directReader.List(ctx, list, opts...)
+	}
+
+	if c.fallback.active(gvk) {
+		return c.fallback.List(ctx, gvk, list, opts...)
+	}
+
+	if err := c.ensureCacheSyncForGVK(ctx, gvk); err != nil {
+		if c.fallback.activate(ctx, gvk, err) {
+			return c.fallback.List(ctx, gvk, list, opts...)
+		}
+
+		return fmt.Errorf("ensuring cache sync for (list) GVK: %w", err)
+	}
+
+	c.fallback.deactivate(gvk)
 
 	return c.Cache.List(ctx, list, opts...)
 }
@@ -370,10 +450,19 @@ func (c *trackingCache) GetInformer(
 	c.accessLock.RLock()
 	defer c.accessLock.RUnlock()
 
-	if err := c.ensureCacheSync(ctx, obj); err != nil {
+	gvk, err := apiutil.GVKForObject(obj, c.scheme)
+	if err != nil {
 		return nil, err
 	}
 
+	if err := c.ensureCacheSyncForGVK(ctx, gvk); err != nil {
+		return nil, fmt.Errorf("ensuring cache sync for GVK: %w", err)
+	}
+
+	if c.metadataOnly.Has(gvk) {
+		obj = c.informerObject(gvk)
+	}
+
 	return c.Cache.GetInformer(ctx, obj, opts...)
 }
 
@@ -388,6 +477,10 @@ func (c *trackingCache) GetInformerForKind(
 		return nil, err
 	}
 
+	if c.metadataOnly.Has(gvk) {
+		return c.Cache.GetInformer(ctx, c.informerObject(gvk), opts...)
+	}
+
 	return c.Cache.GetInformerForKind(ctx, gvk, opts...)
 }
 
@@ -405,7 +498,7 @@ func (c *trackingCache) RemoveInformer(ctx context.Context, obj client.Object) e
 		do: func(ctx context.Context) {
 			defer close(errCh)
 
-			err := c.Cache.RemoveInformer(ctx, obj)
+			err := c.Cache.RemoveInformer(ctx, c.informerObject(gvk))
 			if err != nil {
 				errCh <- err
 
@@ -429,10 +522,7 @@ func (c *trackingCache) stopInformer(ctx context.Context, gvk schema.GroupVersio
 	log := logr.FromContextOrDiscard(ctx)
 	log.V(-1).Info("stopping informer", "gvk", gvk)
 
-	obj := &unstructured.Unstructured{}
-	obj.SetGroupVersionKind(gvk)
-
-	err := c.Cache.RemoveInformer(ctx, obj)
+	err := c.Cache.RemoveInformer(ctx, c.informerObject(gvk))
 	if err != nil {
 		return err
 	}
@@ -450,6 +540,16 @@ func (c *trackingCache) stopInformer(ctx context.Context, gvk schema.GroupVersio
 
 	c.knownInformers.Delete(gvk)
 
+	if cause != nil {
+		c.stoppedInformers[gvk] = stoppedInformer{err: cause, time: c.clock.Now()}
+	} else {
+		delete(c.stoppedInformers, gvk)
+	}
+
+	c.lastReadLock.Lock()
+	delete(c.lastRead, gvk)
+	c.lastReadLock.Unlock()
+
 	return nil
 }
 
@@ -476,10 +576,7 @@ func (c *trackingCache) removeOtherInformers(ctx context.Context, gvksToKeep set
 			var errs []error
 
 			for _, gvkToStop := range gvksToStop {
-				obj := &unstructured.Unstructured{}
-				obj.SetGroupVersionKind(gvkToStop)
-
-				if err := c.Cache.RemoveInformer(ctx, obj); err != nil {
+				if err := c.Cache.RemoveInformer(ctx, c.informerObject(gvkToStop)); err != nil {
 					errs = append(errs, err)
 
 					continue
@@ -536,6 +633,19 @@ func (c *trackingCache) watch(ctx context.Context, gvks sets.Set[schema.GroupVer
 	return g.Wait()
 }
 
+// GetWatchedGVKs returns the GVKs watched by all users registered via Watch.
+func (c *trackingCache) GetWatchedGVKs() sets.Set[schema.GroupVersionKind] {
+	c.watchesByUserLock.Lock()
+	defer c.watchesByUserLock.Unlock()
+
+	watched := sets.Set[schema.GroupVersionKind]{}
+	for _, gvks := range c.watchesByUser {
+		watched.Insert(gvks.UnsortedList()...)
+	}
+
+	return watched
+}
+
 func (c *trackingCache) Free(ctx context.Context, user client.Object) error {
 	c.watchesByUserLock.Lock()
 	defer c.watchesByUserLock.Unlock()
@@ -564,20 +674,42 @@ func (c *trackingCache) gcUnusedGVK(ctx context.Context) error {
 }
 
 func (c *trackingCache) GetObjectsPerInformer(ctx context.Context) (map[schema.GroupVersionKind]int, error) {
+	return c.objectsPerInformer(ctx)
+}
+
+// objectsPerInformer counts the objects matching opts in each informer.
+func (c *trackingCache) objectsPerInformer(
+	ctx context.Context, opts ...client.ListOption,
+) (map[schema.GroupVersionKind]int, error) {
 	c.accessLock.RLock()
 	defer c.accessLock.RUnlock()
 
 	objects := make(map[schema.GroupVersionKind]int, len(c.knownInformers))
 
 	for gvk := range c.knownInformers {
-		listObj := &unstructured.UnstructuredList{}
-		listObj.SetGroupVersionKind(schema.GroupVersionKind{
+		listGVK := schema.GroupVersionKind{
 			Group:   gvk.Group,
 			Version: gvk.Version,
 			Kind:    gvk.Kind + "List",
-		})
+		}
+
+		if c.metadataOnly.Has(gvk) {
+			listObj := &metav1.PartialObjectMetadataList{}
+			listObj.SetGroupVersionKind(listGVK)
 
-		if err := c.Cache.List(ctx, listObj); err != nil {
+			if err := c.Cache.List(ctx, listObj, opts...); err != nil {
+				return nil, fmt.Errorf("listing objects for GVK '%s': %w", gvk.String(), err)
+			}
+
+			objects[gvk] = len(listObj.Items)
+
+			continue
+		}
+
+		listObj := &unstructured.UnstructuredList{}
+		listObj.SetGroupVersionKind(listGVK)
+
+		if err := c.Cache.List(ctx, listObj, opts...); err != nil {
 			return nil, fmt.Errorf("listing objects for GVK '%s': %w", gvk.String(), err)
 		}
 
@@ -586,3 +718,56 @@ func (c *trackingCache) GetObjectsPerInformer(ctx context.Context) (map[schema.G
 
 	return objects, nil
 }
+
+// GetLastReadPerInformer returns the last time each informer has been read from.
+func (c *trackingCache) GetLastReadPerInformer() map[schema.GroupVersionKind]time.Time {
+	c.accessLock.RLock()
+	defer c.accessLock.RUnlock()
+
+	c.lastReadLock.Lock()
+	defer c.lastReadLock.Unlock()
+
+	lastRead := make(map[schema.GroupVersionKind]time.Time, len(c.knownInformers))
+	for gvk := range c.knownInformers {
+		lastRead[gvk] = c.lastRead[gvk]
+	}
+
+	return lastRead
+}
+
+// touch records a read of the informer for gvk.
+func (c *trackingCache) touch(gvk schema.GroupVersionKind) {
+	c.lastReadLock.Lock()
+	defer c.lastReadLock.Unlock()
+
+	c.lastRead[gvk] = c.clock.Now()
+}
+
+// informerObject returns the object type used to start and stop the informer for gvk.
+func (c *trackingCache) informerObject(gvk schema.GroupVersionKind) client.Object {
+	if c.metadataOnly.Has(gvk) {
+		obj := &metav1.PartialObjectMetadata{}
+		obj.SetGroupVersionKind(gvk)
+
+		return obj
+	}
+
+	obj := &unstructured.Unstructured{}
+	obj.SetGroupVersionKind(gvk)
+
+	return obj
+}
+
+// isDirectRead returns true if obj can't be served from the metadata only cache of gvk.
+func (c *trackingCache) isDirectRead(gvk schema.GroupVersionKind, obj runtime.Object) bool {
+	if !c.metadataOnly.Has(gvk) {
+		return false
+	}
+
+	switch obj.(type) {
+	case *metav1.PartialObjectMetadata, *metav1.PartialObjectMetadataList:
+		return false
+	default:
+		return true
+	}
+}
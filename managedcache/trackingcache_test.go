@@ -3,6 +3,8 @@ package managedcache
 import (
 	"context"
 	"errors"
+	"net/http"
+	"net/http/httptest"
 	"sync"
 	"testing"
 	"time"
@@ -23,6 +25,8 @@ import (
 	toolscache "k8s.io/client-go/tools/cache"
 	"sigs.k8s.io/controller-runtime/pkg/cache"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"pkg.package-operator.run/boxcutter/internal/testutil"
 )
 
 var (
@@ -108,9 +112,17 @@ func TestTrackingCache_createTeardownInformers(t *testing.T) {
 	assert.Equal(t, []schema.GroupVersionKind{
 		{Kind: "ConfigMap", Version: "v1"},
 	}, itc.GetGVKs())
+	assert.Contains(t, itc.GetLastReadPerInformer(), schema.GroupVersionKind{Kind: "ConfigMap", Version: "v1"})
+
+	status, err := itc.Status(t.Context())
+	require.NoError(t, err)
+	assert.Equal(t, TrackingCacheStatus{
+		Informers: []InformerStatus{{GVK: schema.GroupVersionKind{Kind: "ConfigMap", Version: "v1"}, Synced: true}},
+	}, status)
 
 	err = itc.RemoveInformer(t.Context(), cmObj)
 	require.NoError(t, err)
+	assert.Empty(t, itc.GetLastReadPerInformer())
 
 	informerMock.AssertExpectations(t)
 	restMapperMock.AssertExpectations(t)
@@ -277,8 +289,11 @@ func TestTrackingCache_WatchFree(t *testing.T) {
 	}, cmObj)
 	require.NoError(t, err)
 
+	assert.Equal(t, sets.New(schema.GroupVersionKind{Version: "v1", Kind: "ConfigMap"}), itc.GetWatchedGVKs())
+
 	err = itc.Free(t.Context(), user)
 	require.NoError(t, err)
+	assert.Empty(t, itc.GetWatchedGVKs())
 
 	informerMock.AssertExpectations(t)
 	restMapperMock.AssertExpectations(t)
@@ -454,6 +469,17 @@ func TestTrackingCacheWatchErrorHandling_Get(t *testing.T) {
 	reflectorWatchErrorHandlerMock.AssertCalled(
 		t, "ErrorHandler", mock.Anything, mock.Anything, mock.Anything)
 
+	status, err := itc.Status(t.Context())
+	require.NoError(t, err)
+	require.Len(t, status.Informers, 1)
+	assert.Equal(t, schema.GroupVersionKind{Version: "v1", Kind: "ConfigMap"}, status.Informers[0].GVK)
+	assert.True(t, status.Informers[0].Stopped)
+	require.ErrorIs(t, status.Informers[0].LastError, errAPIStatus)
+
+	req := httptest.NewRequest(http.MethodGet, "/healthz", nil)
+	require.ErrorIs(t, itc.Healthz(req), errAPIStatus)
+	require.ErrorIs(t, itc.Readyz(req), errAPIStatus)
+
 	informerMock.AssertExpectations(t)
 	restMapperMock.AssertExpectations(t)
 	cacheMock.AssertExpectations(t)
@@ -653,6 +679,97 @@ func TestTrackingCache_GetObjectsPerInformer(t *testing.T) {
 	cacheMock.AssertExpectations(t)
 }
 
+func TestTrackingCache_MetadataOnly(t *testing.T) {
+	t.Parallel()
+	log := testr.New(t)
+	cacheMock := &cacheMock{}
+	restMapperMock := &restMapperMock{}
+	directReader := testutil.NewClient()
+
+	cmGVK := schema.GroupVersionKind{Version: "v1", Kind: "ConfigMap"}
+
+	tc, err := newTrackingCache(
+		log, newCacheSource(),
+		func(_ *rest.Config, _ cache.Options) (cache.Cache, error) {
+			return cacheMock, nil
+		},
+		nil, cache.Options{
+			Mapper: restMapperMock,
+			Scheme: scheme.Scheme,
+		},
+		WithMetadataOnly(cmGVK), WithDirectReader(directReader),
+	)
+	require.NoError(t, err)
+
+	itc := tc.(*trackingCache)
+
+	informerMock := &informerMock{}
+
+	// Mocks
+	cacheMock.
+		On("GetInformer", mock.Anything, mock.AnythingOfType("*v1.PartialObjectMetadata"), mock.Anything).
+		Return(informerMock, nil)
+	informerMock.
+		On("HasSynced").
+		Return(true)
+	cacheMock.
+		On("Start", mock.Anything).
+		Run(func(args mock.Arguments) {
+			ctx := args.Get(0).(context.Context)
+			<-ctx.Done()
+		}).
+		Return(nil).
+		Maybe()
+	cacheMock.
+		On("Get", mock.Anything, mock.Anything, mock.AnythingOfType("*v1.PartialObjectMetadata"), mock.Anything).
+		Return(nil)
+	cacheMock.
+		On("List", mock.Anything, mock.AnythingOfType("*v1.PartialObjectMetadataList"), mock.Anything).
+		Return(nil)
+	directReader.
+		On("Get", mock.Anything, mock.Anything, mock.AnythingOfType("*v1.ConfigMap"), mock.Anything).
+		Return(nil)
+	directReader.
+		On("List", mock.Anything, mock.AnythingOfType("*v1.ConfigMapList"), mock.Anything).
+		Return(nil)
+
+	ctx, cancel := context.WithCancel(t.Context())
+
+	var doneWG sync.WaitGroup
+
+	doneWG.Go(func() {
+		err := tc.Start(ctx)
+		if err != nil {
+			panic(err)
+		}
+	})
+
+	key := client.ObjectKey{Name: "banana"}
+
+	// Full objects are read directly.
+	require.NoError(t, itc.Get(t.Context(), key, &corev1.ConfigMap{}))
+	require.NoError(t, itc.List(t.Context(), &corev1.ConfigMapList{}))
+	assert.Empty(t, itc.GetGVKs())
+
+	// Metadata is served from cache.
+	metaObj := &metav1.PartialObjectMetadata{}
+	metaObj.SetGroupVersionKind(cmGVK)
+	require.NoError(t, itc.Get(t.Context(), key, metaObj))
+
+	metaList := &metav1.PartialObjectMetadataList{}
+	metaList.SetGroupVersionKind(cmGVK.GroupVersion().WithKind("ConfigMapList"))
+	require.NoError(t, itc.List(t.Context(), metaList))
+
+	assert.Equal(t, []schema.GroupVersionKind{cmGVK}, itc.GetGVKs())
+
+	cancel()
+	doneWG.Wait()
+
+	informerMock.AssertExpectations(t)
+	cacheMock.AssertExpectations(t)
+	directReader.AssertExpectations(t)
+}
+
 type reflectorWatchErrorHandlerMock struct {
 	mock.Mock
 }
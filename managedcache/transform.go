@@ -0,0 +1,31 @@
+package managedcache
+
+import (
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	toolscache "k8s.io/client-go/tools/cache"
+)
+
+// TransformStripFields returns a TransformFunc removing .metadata.managedFields
+// and the given (nested) fields from objects before they are stored in the cache.
+// Use with cache.Options.DefaultTransform or cache.Options.ByObject, e.g.:
+//
+//	TransformStripFields([]string{"data"}, []string{"binaryData"})
+//
+// Fields are only removed from unstructured objects,
+// PartialObjectMetadata objects just lose their managedFields.
+func TransformStripFields(fields ...[]string) toolscache.TransformFunc {
+	return func(in any) (any, error) {
+		if obj, err := meta.Accessor(in); err == nil && obj.GetManagedFields() != nil {
+			obj.SetManagedFields(nil)
+		}
+
+		if obj, ok := in.(*unstructured.Unstructured); ok {
+			for _, field := range fields {
+				unstructured.RemoveNestedField(obj.Object, field...)
+			}
+		}
+
+		return in, nil
+	}
+}
@@ -0,0 +1,62 @@
+package managedcache
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestTransformStripFields(t *testing.T) {
+	t.Parallel()
+
+	managedFields := []metav1.ManagedFieldsEntry{{Manager: "test"}}
+
+	tests := []struct {
+		name     string
+		in       func() any
+		expected any
+	}{
+		{
+			name: "unstructured",
+			in: func() any {
+				obj := &unstructured.Unstructured{Object: map[string]any{
+					"data":       map[string]any{"key": "value"},
+					"binaryData": map[string]any{"key": "dmFsdWU="},
+					"immutable":  true,
+				}}
+				obj.SetName("test")
+				obj.SetManagedFields(managedFields)
+
+				return obj
+			},
+			expected: &unstructured.Unstructured{Object: map[string]any{
+				"immutable": true,
+				"metadata":  map[string]any{"name": "test"},
+			}},
+		},
+		{
+			name: "metadata",
+			in: func() any {
+				return &metav1.PartialObjectMetadata{
+					ObjectMeta: metav1.ObjectMeta{Name: "test", ManagedFields: managedFields},
+				}
+			},
+			expected: &metav1.PartialObjectMetadata{
+				ObjectMeta: metav1.ObjectMeta{Name: "test"},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			out, err := TransformStripFields([]string{"data"}, []string{"binaryData"})(test.in())
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, out)
+		})
+	}
+}
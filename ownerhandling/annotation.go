@@ -27,6 +27,9 @@ var _ ownerStrategy = (*OwnerStrategyAnnotation)(nil)
 type OwnerStrategyAnnotation struct {
 	scheme        *runtime.Scheme
 	annotationKey string
+	// cluster identifies the cluster owners live in.
+	// Empty for owners in the same cluster.
+	cluster string
 }
 
 // NewAnnotation returns a new OwnerStrategyAnnotation instance.
@@ -42,14 +45,36 @@ func (s *OwnerStrategyAnnotation) GetController(obj metav1.Object) (
 	metav1.OwnerReference, bool,
 ) {
 	for _, ref := range s.getOwnerReferences(obj) {
-		if ref.Controller != nil && *ref.Controller {
-			return ref.ToMetaV1OwnerRef(), true
+		if ref.isController() {
+			return s.toMetaV1OwnerRef(ref), true
 		}
 	}
 
 	return metav1.OwnerReference{}, false
 }
 
+// GetOwnerReferences returns all OwnerReferences of obj.
+func (s *OwnerStrategyAnnotation) GetOwnerReferences(obj metav1.Object) []metav1.OwnerReference {
+	refs := s.getOwnerReferences(obj)
+	ownerRefs := make([]metav1.OwnerReference, 0, len(refs))
+
+	for _, ref := range refs {
+		ownerRefs = append(ownerRefs, s.toMetaV1OwnerRef(ref))
+	}
+
+	return ownerRefs
+}
+
+func (s *OwnerStrategyAnnotation) toMetaV1OwnerRef(ref annotationOwnerRef) metav1.OwnerReference {
+	ownerRef := ref.ToMetaV1OwnerRef()
+	if ref.Cluster != s.cluster {
+		// Owners from other clusters must never match local objects.
+		ownerRef.UID = types.UID(ref.Cluster + "/" + string(ref.UID))
+	}
+
+	return ownerRef
+}
+
 // CopyOwnerReferences copies all OwnerReferences from objA to objB,
 // overriding any existing OwnerReferences on objB.
 func (s *OwnerStrategyAnnotation) CopyOwnerReferences(objA, objB metav1.Object) {
@@ -89,6 +114,7 @@ func (s *OwnerStrategyAnnotation) SetOwnerReference(owner, obj metav1.Object) er
 		UID:        owner.GetUID(),
 		Name:       owner.GetName(),
 		Namespace:  owner.GetNamespace(),
+		Cluster:    s.cluster,
 	}
 
 	ownerIndex := s.indexOf(ownerRefs, ownerRef)
@@ -136,6 +162,7 @@ func (s *OwnerStrategyAnnotation) SetControllerReference(owner, obj metav1.Objec
 		UID:        owner.GetUID(),
 		Name:       owner.GetName(),
 		Namespace:  owner.GetNamespace(),
+		Cluster:    s.cluster,
 		Controller: new(true),
 	}
 
@@ -270,6 +297,7 @@ func (s *OwnerStrategyAnnotation) ownerRefForCompare(owner metav1.Object) annota
 		Kind:       gvk.Kind,
 		UID:        owner.GetUID(),
 		Name:       owner.GetName(),
+		Cluster:    s.cluster,
 	}
 
 	return ref
@@ -287,7 +315,8 @@ func (s *OwnerStrategyAnnotation) referSameObject(a, b annotationOwnerRef) bool
 		return false
 	}
 
-	return aGV.Group == bGV.Group && a.Kind == b.Kind && a.Name == b.Name && a.UID == b.UID
+	return aGV.Group == bGV.Group && a.Kind == b.Kind && a.Name == b.Name && a.UID == b.UID &&
+		a.Cluster == b.Cluster
 }
 
 type annotationOwnerRef struct {
@@ -305,6 +334,9 @@ type annotationOwnerRef struct {
 	// UID of the referent.
 	// More info: http://kubernetes.io/docs/user-guide/identifiers#uids
 	UID types.UID `json:"uid"`
+	// Cluster the referent lives in, empty for the local cluster.
+	// +optional
+	Cluster string `json:"cluster,omitempty"`
 	// If true, this reference struct points to the managing controller.
 	// +optional
 	Controller *bool `json:"controller,omitempty"`
@@ -394,6 +426,10 @@ func (e *AnnotationEnqueueRequestForOwner) getOwnerReconcileRequest(object metav
 			continue
 		}
 
+		if ownerRef.Cluster != e.ownerStrategy.cluster {
+			continue
+		}
+
 		if e.IsController && !ownerRef.isController() {
 			continue
 		}
@@ -0,0 +1,402 @@
+package ownerhandling
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/prometheus/client_golang/prometheus"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/tools/events"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// AnnotationGCPolicy decides what happens to objects
+// when their controller referenced via annotation no longer exists.
+type AnnotationGCPolicy string
+
+const (
+	// AnnotationGCPolicyOrphan removes the reference to the missing
+	// controller from the object and leaves the object in place.
+	AnnotationGCPolicyOrphan AnnotationGCPolicy = "Orphan"
+	// AnnotationGCPolicyDelete deletes the object.
+	AnnotationGCPolicyDelete AnnotationGCPolicy = "Delete"
+)
+
+// DefaultAnnotationGCInterval is the default duration between garbage collection runs.
+const DefaultAnnotationGCInterval = 10 * time.Minute
+
+// AnnotationGarbageCollectorOptions configures the AnnotationGarbageCollector.
+type AnnotationGarbageCollectorOptions struct {
+	// GVKs of objects to garbage collect.
+	GVKs []schema.GroupVersionKind
+	// Policy to apply to objects whose controller is gone.
+	// Defaults to AnnotationGCPolicyOrphan.
+	Policy AnnotationGCPolicy
+	// Interval between garbage collection runs.
+	// Defaults to DefaultAnnotationGCInterval.
+	Interval time.Duration
+	// EventRecorder reports actions taken on objects, optional.
+	EventRecorder events.EventRecorder
+	// Registerer exports metrics, optional.
+	// e.g. sigs.k8s.io/controller-runtime/pkg/metrics.Registry.
+	// Collectors sharing a Registerer share their metrics.
+	Registerer prometheus.Registerer
+}
+
+// annotationGCCache is implemented by managedcache.TrackingCache.
+type annotationGCCache interface {
+	client.Reader
+	Watch(ctx context.Context, user client.Object, gvks sets.Set[schema.GroupVersionKind]) error
+}
+
+// annotationGCUser identifies the garbage collector as user of cache informers.
+var annotationGCUser = &metav1.PartialObjectMetadata{
+	ObjectMeta: metav1.ObjectMeta{Name: "boxcutter-annotation-gc"},
+}
+
+// AnnotationGarbageCollector cleans up after owners referenced via OwnerStrategyAnnotation.
+// The Kubernetes garbage collector does not know about these references,
+// so objects are leaked when their owner is deleted without a clean teardown.
+//
+// Every interval, objects of the configured GVKs are listed from the cache
+// and indexed by their controller. Controllers that no longer exist or have been
+// re-created with a different UID are considered gone and the configured policy is
+// applied to all of their objects. Controllers whose API is no longer served
+// are never considered gone.
+//
+// Actions are reported via events and the prometheus metric
+// boxcutter_annotation_gc_objects_total, exported via the configured Registerer.
+// AnnotationGarbageCollector implements manager.Runnable and requires leader election.
+type AnnotationGarbageCollector struct {
+	log         logr.Logger
+	strategy    *OwnerStrategyAnnotation
+	cache       annotationGCCache
+	ownerReader client.Reader
+	writer      client.Writer
+	restMapper  meta.RESTMapper
+	opts        AnnotationGarbageCollectorOptions
+
+	objects *prometheus.CounterVec
+}
+
+// NewAnnotationGarbageCollector returns a new AnnotationGarbageCollector instance.
+// cache is used to list objects, usually a managedcache.TrackingCache.
+// ownerReader is used to lookup owners and should not be cached,
+// to not start informers for every owner type.
+func NewAnnotationGarbageCollector(
+	log logr.Logger,
+	strategy *OwnerStrategyAnnotation,
+	cache annotationGCCache,
+	ownerReader client.Reader,
+	writer client.Writer,
+	restMapper meta.RESTMapper,
+	opts AnnotationGarbageCollectorOptions,
+) *AnnotationGarbageCollector {
+	if len(opts.Policy) == 0 {
+		opts.Policy = AnnotationGCPolicyOrphan
+	}
+
+	if opts.Interval == 0 {
+		opts.Interval = DefaultAnnotationGCInterval
+	}
+
+	return &AnnotationGarbageCollector{
+		log:         log.WithName("AnnotationGarbageCollector"),
+		strategy:    strategy,
+		cache:       cache,
+		ownerReader: ownerReader,
+		writer:      writer,
+		restMapper:  restMapper,
+		opts:        opts,
+
+		objects: annotationGCObjectsCounter(opts.Registerer),
+	}
+}
+
+// annotationGCObjectsCounter returns the objects counter registered with r,
+// reusing the counter of another collector already registered.
+func annotationGCObjectsCounter(r prometheus.Registerer) *prometheus.CounterVec {
+	objects := prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "boxcutter_annotation_gc_objects_total",
+		Help: "Objects with a missing annotation controller, by action taken.",
+	}, []string{"action", "group", "kind"})
+
+	if r == nil {
+		return objects
+	}
+
+	err := r.Register(objects)
+
+	var are prometheus.AlreadyRegisteredError
+	if errors.As(err, &are) {
+		if existing, ok := are.ExistingCollector.(*prometheus.CounterVec); ok {
+			return existing
+		}
+	}
+
+	if err != nil {
+		// Same as prometheus.MustRegister, conflicting metrics are a programming error.
+		panic(err)
+	}
+
+	return objects
+}
+
+// NeedLeaderElection implements manager.LeaderElectionRunnable.
+func (g *AnnotationGarbageCollector) NeedLeaderElection() bool {
+	return true
+}
+
+// Start implements manager.Runnable.
+// Runs garbage collection every interval until the context is cancelled.
+func (g *AnnotationGarbageCollector) Start(ctx context.Context) error {
+	if err := g.cache.Watch(ctx, annotationGCUser, sets.New(g.opts.GVKs...)); err != nil {
+		return fmt.Errorf("watching objects: %w", err)
+	}
+
+	wait.UntilWithContext(ctx, func(ctx context.Context) {
+		if err := g.Run(ctx); err != nil {
+			g.log.Error(err, "garbage collection")
+		}
+	}, g.opts.Interval)
+
+	return nil
+}
+
+// annotationGCOwnerKey identifies an owner independent of the controller flag.
+type annotationGCOwnerKey struct {
+	APIVersion, Kind, Namespace, Name string
+	UID                               types.UID
+}
+
+// Run runs garbage collection once.
+func (g *AnnotationGarbageCollector) Run(ctx context.Context) error {
+	var errs []error
+
+	for _, gvk := range g.opts.GVKs {
+		if err := g.runForGVK(ctx, gvk); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", gvk, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func (g *AnnotationGarbageCollector) runForGVK(ctx context.Context, gvk schema.GroupVersionKind) error {
+	list := &unstructured.UnstructuredList{}
+	list.SetGroupVersionKind(gvk.GroupVersion().WithKind(gvk.Kind + "List"))
+
+	if err := g.cache.List(ctx, list); err != nil {
+		return fmt.Errorf("listing objects: %w", err)
+	}
+
+	// Index objects by controller, to lookup each owner only once.
+	byController := map[annotationGCOwnerKey][]*unstructured.Unstructured{}
+
+	for i := range list.Items {
+		obj := &list.Items[i]
+		if obj.GetDeletionTimestamp() != nil {
+			continue
+		}
+
+		ctrl, ok := g.controllerOf(obj)
+		if !ok {
+			continue
+		}
+
+		byController[ctrl] = append(byController[ctrl], obj)
+	}
+
+	var errs []error
+
+	for ctrl, objs := range byController {
+		gone, err := g.ownerGone(ctx, ctrl)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("looking up owner %s %s/%s: %w", ctrl.Kind, ctrl.Namespace, ctrl.Name, err))
+
+			continue
+		}
+
+		if !gone {
+			continue
+		}
+
+		for _, obj := range objs {
+			if err := g.collect(ctx, obj, ctrl); err != nil {
+				g.objects.WithLabelValues("failed", gvk.Group, gvk.Kind).Inc()
+				errs = append(errs, fmt.Errorf("%s %s/%s: %w", g.opts.Policy, obj.GetNamespace(), obj.GetName(), err))
+			}
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func (g *AnnotationGarbageCollector) controllerOf(obj *unstructured.Unstructured) (annotationGCOwnerKey, bool) {
+	refs, err := g.ownerReferences(obj)
+	if err != nil {
+		g.log.Info("skipping object with invalid owner annotation",
+			"namespace", obj.GetNamespace(), "name", obj.GetName(), "err", err)
+
+		return annotationGCOwnerKey{}, false
+	}
+
+	for _, ref := range refs {
+		if ref.isController() {
+			if ref.Cluster != g.strategy.cluster {
+				// Controller lives in another cluster.
+				return annotationGCOwnerKey{}, false
+			}
+
+			return annotationGCOwnerKey{
+				APIVersion: ref.APIVersion,
+				Kind:       ref.Kind,
+				Namespace:  ref.Namespace,
+				Name:       ref.Name,
+				UID:        ref.UID,
+			}, true
+		}
+	}
+
+	return annotationGCOwnerKey{}, false
+}
+
+// ownerReferences parses the owner annotation without panicking on invalid content.
+func (g *AnnotationGarbageCollector) ownerReferences(obj metav1.Object) ([]annotationOwnerRef, error) {
+	v := obj.GetAnnotations()[g.strategy.annotationKey]
+	if len(v) == 0 {
+		return nil, nil
+	}
+
+	var refs []annotationOwnerRef
+	if err := json.Unmarshal([]byte(v), &refs); err != nil {
+		return nil, err
+	}
+
+	return refs, nil
+}
+
+func (g *AnnotationGarbageCollector) ownerGone(ctx context.Context, key annotationGCOwnerKey) (bool, error) {
+	gvk := schema.FromAPIVersionAndKind(key.APIVersion, key.Kind)
+
+	mapping, err := g.restMapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+	if meta.IsNoMatchError(err) {
+		// Can't tell if the owner still exists.
+		return false, nil
+	}
+
+	if err != nil {
+		return false, err
+	}
+
+	if mapping.Scope.Name() == meta.RESTScopeNameNamespace && len(key.Namespace) == 0 {
+		// Reference from before namespaces were recorded, can't be looked up.
+		return false, nil
+	}
+
+	owner := &metav1.PartialObjectMetadata{}
+	owner.SetGroupVersionKind(gvk)
+
+	err = g.ownerReader.Get(ctx, client.ObjectKey{Namespace: key.Namespace, Name: key.Name}, owner)
+	if apierrors.IsNotFound(err) {
+		return true, nil
+	}
+
+	if err != nil {
+		return false, err
+	}
+
+	// Re-created owners are a different object.
+	return owner.GetUID() != key.UID, nil
+}
+
+func (g *AnnotationGarbageCollector) collect(
+	ctx context.Context, obj *unstructured.Unstructured, ctrl annotationGCOwnerKey,
+) error {
+	gvk := obj.GroupVersionKind()
+
+	var action, note string
+
+	switch g.opts.Policy {
+	case AnnotationGCPolicyDelete:
+		uid, rv := obj.GetUID(), obj.GetResourceVersion()
+
+		err := g.writer.Delete(ctx, obj,
+			client.Preconditions{UID: &uid, ResourceVersion: &rv},
+			client.PropagationPolicy(metav1.DeletePropagationBackground))
+		if apierrors.IsNotFound(err) || apierrors.IsConflict(err) {
+			// Changed in the meantime, check again next run.
+			return nil
+		}
+
+		if err != nil {
+			return err
+		}
+
+		action, note = "deleted", "deleted object"
+
+	case AnnotationGCPolicyOrphan:
+		refs, err := g.ownerReferences(obj)
+		if err != nil {
+			return err
+		}
+
+		remaining := make([]annotationOwnerRef, 0, len(refs))
+
+		for _, ref := range refs {
+			if ref.UID != ctrl.UID {
+				remaining = append(remaining, ref)
+			}
+		}
+
+		updated := obj.DeepCopy()
+		if len(remaining) == 0 {
+			annotations := updated.GetAnnotations()
+			delete(annotations, g.strategy.annotationKey)
+			updated.SetAnnotations(annotations)
+		} else {
+			g.strategy.setOwnerReferences(updated, remaining)
+		}
+
+		err = g.writer.Patch(ctx, updated,
+			client.MergeFromWithOptions(obj, client.MergeFromWithOptimisticLock{}))
+		if apierrors.IsNotFound(err) || apierrors.IsConflict(err) {
+			// Changed in the meantime, check again next run.
+			return nil
+		}
+
+		if err != nil {
+			return err
+		}
+
+		action, note = "orphaned", "removed owner reference"
+
+	default:
+		return fmt.Errorf("unknown policy %q", g.opts.Policy)
+	}
+
+	g.objects.WithLabelValues(action, gvk.Group, gvk.Kind).Inc()
+	g.log.Info("controller gone, "+note,
+		"namespace", obj.GetNamespace(), "name", obj.GetName(),
+		"controller", fmt.Sprintf("%s %s/%s", ctrl.Kind, ctrl.Namespace, ctrl.Name))
+
+	if g.opts.EventRecorder != nil {
+		g.opts.EventRecorder.Eventf(obj, nil, corev1.EventTypeNormal, "ControllerGone", "GarbageCollect",
+			"Controller %s %s/%s (%s) no longer exists, %s.",
+			ctrl.Kind, ctrl.Namespace, ctrl.Name, ctrl.UID, note)
+	}
+
+	return nil
+}
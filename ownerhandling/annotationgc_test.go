@@ -0,0 +1,219 @@
+package ownerhandling
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-logr/logr/testr"
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/tools/events"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"pkg.package-operator.run/boxcutter/internal/testutil"
+)
+
+type gcCacheMock struct {
+	*testutil.CtrlClient
+}
+
+func (c *gcCacheMock) Watch(ctx context.Context, user client.Object, gvks sets.Set[schema.GroupVersionKind]) error {
+	args := c.Called(ctx, user, gvks)
+
+	return args.Error(0)
+}
+
+func counterValue(t *testing.T, c prometheus.Counter) float64 {
+	t.Helper()
+
+	m := &dto.Metric{}
+	require.NoError(t, c.Write(m))
+
+	return m.GetCounter().GetValue()
+}
+
+func TestAnnotationGarbageCollector(t *testing.T) {
+	t.Parallel()
+
+	cmGVK := corev1.SchemeGroupVersion.WithKind("ConfigMap")
+
+	newObj := func(name, annotation string) unstructured.Unstructured {
+		obj := unstructured.Unstructured{}
+		obj.SetGroupVersionKind(cmGVK)
+		obj.SetName(name)
+		obj.SetNamespace("test")
+		obj.SetUID(types.UID(name + "-uid"))
+		obj.SetResourceVersion("1")
+
+		if len(annotation) > 0 {
+			obj.SetAnnotations(map[string]string{testAnnotationKey: annotation})
+		}
+
+		return obj
+	}
+
+	objects := []unstructured.Unstructured{
+		// Controller deleted, additional non-controller owner.
+		newObj("gone", `[`+
+			`{"apiVersion":"v1","kind":"ConfigMap","name":"dead","namespace":"test","uid":"1","controller":true},`+
+			`{"apiVersion":"v1","kind":"ConfigMap","name":"alive","namespace":"test","uid":"3"}]`),
+		// Controller re-created with a new UID.
+		newObj("recreated", `[`+
+			`{"apiVersion":"v1","kind":"ConfigMap","name":"recreated","namespace":"test","uid":"2","controller":true}]`),
+		newObj("alive", `[`+
+			`{"apiVersion":"v1","kind":"ConfigMap","name":"alive","namespace":"test","uid":"3","controller":true}]`),
+		// API of controller not served.
+		newObj("unknown", `[`+
+			`{"apiVersion":"example.com/v1","kind":"Foo","name":"foo","namespace":"test","uid":"4","controller":true}]`),
+		// Namespaced controller without recorded namespace.
+		newObj("no-namespace", `[{"apiVersion":"v1","kind":"ConfigMap","name":"dead","uid":"1","controller":true}]`),
+		// Controller in another cluster.
+		newObj("foreign", `[`+
+			`{"apiVersion":"v1","kind":"ConfigMap","name":"dead","namespace":"test","uid":"1","cluster":"c","controller":true}]`),
+		newObj("invalid", `{`),
+		newObj("unowned", ""),
+	}
+
+	restMapper := meta.NewDefaultRESTMapper(nil)
+	restMapper.Add(cmGVK, meta.RESTScopeNamespace)
+
+	newGC := func(t *testing.T, policy AnnotationGCPolicy) (
+		*AnnotationGarbageCollector, *testutil.CtrlClient, *events.FakeRecorder,
+	) {
+		t.Helper()
+
+		cache := &gcCacheMock{CtrlClient: testutil.NewClient()}
+		cache.
+			On("List", mock.Anything, mock.Anything, mock.Anything).
+			Run(func(args mock.Arguments) {
+				list := args.Get(1).(*unstructured.UnstructuredList)
+				for _, obj := range objects {
+					list.Items = append(list.Items, *obj.DeepCopy())
+				}
+			}).
+			Return(nil)
+
+		ownerReader := testutil.NewClient()
+		ownerReader.
+			On("Get", mock.Anything, client.ObjectKey{Namespace: "test", Name: "dead"}, mock.Anything, mock.Anything).
+			Return(apierrors.NewNotFound(schema.GroupResource{Resource: "configmaps"}, "dead"))
+
+		for name, uid := range map[string]types.UID{"recreated": "20", "alive": "3"} {
+			ownerReader.
+				On("Get", mock.Anything, client.ObjectKey{Namespace: "test", Name: name}, mock.Anything, mock.Anything).
+				Run(func(args mock.Arguments) {
+					args.Get(2).(*metav1.PartialObjectMetadata).SetUID(uid)
+				}).
+				Return(nil)
+		}
+
+		writer := testutil.NewClient()
+		recorder := events.NewFakeRecorder(10)
+
+		gc := NewAnnotationGarbageCollector(
+			testr.New(t), NewAnnotation(testScheme, testAnnotationKey),
+			cache, ownerReader, writer, restMapper,
+			AnnotationGarbageCollectorOptions{
+				GVKs:          []schema.GroupVersionKind{cmGVK},
+				Policy:        policy,
+				EventRecorder: recorder,
+			})
+
+		return gc, writer, recorder
+	}
+
+	t.Run("orphan", func(t *testing.T) {
+		t.Parallel()
+
+		gc, writer, recorder := newGC(t, "")
+
+		patched := map[string]map[string]string{}
+
+		writer.
+			On("Patch", mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+			Run(func(args mock.Arguments) {
+				obj := args.Get(1).(*unstructured.Unstructured)
+				patched[obj.GetName()] = obj.GetAnnotations()
+			}).
+			Return(nil)
+
+		require.NoError(t, gc.Run(t.Context()))
+
+		assert.Equal(t, map[string]map[string]string{
+			"gone": {
+				testAnnotationKey: `[{"apiVersion":"v1","kind":"ConfigMap","name":"alive","namespace":"test","uid":"3"}]`,
+			},
+			"recreated": {},
+		}, patched)
+		assert.Len(t, recorder.Events, 2)
+		assert.InDelta(t, 2, counterValue(t, gc.objects.WithLabelValues("orphaned", "", "ConfigMap")), 0)
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		t.Parallel()
+
+		gc, writer, recorder := newGC(t, AnnotationGCPolicyDelete)
+
+		var deleted []string
+
+		writer.
+			On("Delete", mock.Anything, mock.Anything, mock.Anything).
+			Run(func(args mock.Arguments) {
+				deleted = append(deleted, args.Get(1).(client.Object).GetName())
+			}).
+			Return(nil)
+
+		require.NoError(t, gc.Run(t.Context()))
+
+		assert.ElementsMatch(t, []string{"gone", "recreated"}, deleted)
+		assert.Len(t, recorder.Events, 2)
+		assert.InDelta(t, 2, counterValue(t, gc.objects.WithLabelValues("deleted", "", "ConfigMap")), 0)
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		t.Parallel()
+
+		gc, writer, _ := newGC(t, AnnotationGCPolicyDelete)
+
+		writer.
+			On("Delete", mock.Anything, mock.Anything, mock.Anything).
+			Return(apierrors.NewForbidden(schema.GroupResource{Resource: "configmaps"}, "", nil))
+
+		require.Error(t, gc.Run(t.Context()))
+		assert.InDelta(t, 2, counterValue(t, gc.objects.WithLabelValues("failed", "", "ConfigMap")), 0)
+	})
+}
+
+func TestAnnotationGarbageCollector_Registerer(t *testing.T) {
+	t.Parallel()
+
+	registry := prometheus.NewRegistry()
+	opts := AnnotationGarbageCollectorOptions{Registerer: registry}
+
+	gc1 := NewAnnotationGarbageCollector(
+		testr.New(t), NewAnnotation(testScheme, testAnnotationKey),
+		nil, nil, nil, nil, opts)
+	gc2 := NewAnnotationGarbageCollector(
+		testr.New(t), NewAnnotation(testScheme, "other"),
+		nil, nil, nil, nil, opts)
+
+	assert.Same(t, gc1.objects, gc2.objects)
+
+	gc1.objects.WithLabelValues("deleted", "", "ConfigMap").Inc()
+
+	families, err := registry.Gather()
+	require.NoError(t, err)
+	require.Len(t, families, 1)
+	assert.Equal(t, "boxcutter_annotation_gc_objects_total", families[0].GetName())
+}
@@ -27,6 +27,8 @@ type ownerStrategy interface {
 	RemoveOwner(owner, obj metav1.Object)
 	// IsOwner returns true if owner is contained in object OwnerReference list.
 	IsOwner(owner, obj metav1.Object) bool
+	// GetOwnerReferences returns all OwnerReferences of obj.
+	GetOwnerReferences(obj metav1.Object) []metav1.OwnerReference
 }
 
 // Removes the given index from the slice.
@@ -0,0 +1,236 @@
+package ownerhandling
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/util/workqueue"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+var _ ownerStrategy = (*OwnerStrategyMigrating)(nil)
+
+// OwnerStrategyMigrating migrates owner references between two strategies,
+// e.g. from OwnerStrategyNative to OwnerStrategyAnnotation.
+// References are read from both strategies, but only written using the new strategy.
+// References of the previous strategy are removed from objects,
+// so the next apply strips them from the cluster.
+type OwnerStrategyMigrating struct {
+	from, to ownerStrategy
+}
+
+// NewMigrating returns a new OwnerStrategyMigrating instance,
+// migrating references from the `from` strategy to the `to` strategy.
+func NewMigrating(from, to ownerStrategy) *OwnerStrategyMigrating {
+	return &OwnerStrategyMigrating{
+		from: from,
+		to:   to,
+	}
+}
+
+// GetController returns the OwnerReference with Controller==true, if one exist.
+// References of the new strategy take precedence.
+func (s *OwnerStrategyMigrating) GetController(obj metav1.Object) (
+	metav1.OwnerReference, bool,
+) {
+	if ref, ok := s.to.GetController(obj); ok {
+		return ref, true
+	}
+
+	return s.from.GetController(obj)
+}
+
+// GetOwnerReferences returns all OwnerReferences of obj.
+func (s *OwnerStrategyMigrating) GetOwnerReferences(obj metav1.Object) []metav1.OwnerReference {
+	refs := s.to.GetOwnerReferences(obj)
+
+	for _, ref := range s.from.GetOwnerReferences(obj) {
+		if !slices.ContainsFunc(refs, func(r metav1.OwnerReference) bool {
+			return r.UID == ref.UID
+		}) {
+			refs = append(refs, ref)
+		}
+	}
+
+	return refs
+}
+
+// CopyOwnerReferences copies all OwnerReferences of the new strategy from objA to objB,
+// overriding any existing OwnerReferences on objB.
+// OwnerReferences of the previous strategy are removed from objB.
+func (s *OwnerStrategyMigrating) CopyOwnerReferences(objA, objB metav1.Object) {
+	for _, ref := range s.from.GetOwnerReferences(objB) {
+		s.from.RemoveOwner(ownerFromReference(ref), objB)
+	}
+
+	s.to.CopyOwnerReferences(objA, objB)
+}
+
+// ownerFromReference returns an owner object identified by the given OwnerReference.
+func ownerFromReference(ref metav1.OwnerReference) *metav1.PartialObjectMetadata {
+	owner := &metav1.PartialObjectMetadata{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: ref.Name,
+			UID:  ref.UID,
+		},
+	}
+	owner.SetGroupVersionKind(schema.FromAPIVersionAndKind(ref.APIVersion, ref.Kind))
+
+	return owner
+}
+
+// EnqueueRequestForOwner returns a EventHandler to enqueue the owner
+// referenced via either strategy.
+func (s *OwnerStrategyMigrating) EnqueueRequestForOwner(
+	ownerType client.Object, mapper meta.RESTMapper, isController bool,
+) handler.EventHandler {
+	return multiEventHandler{
+		s.from.EnqueueRequestForOwner(ownerType, mapper, isController),
+		s.to.EnqueueRequestForOwner(ownerType, mapper, isController),
+	}
+}
+
+// SetOwnerReference adds owner as OwnerReference to obj, with Controller set to false.
+// Removes the owner from references of the previous strategy.
+func (s *OwnerStrategyMigrating) SetOwnerReference(owner, obj metav1.Object) error {
+	if err := s.to.SetOwnerReference(owner, obj); err != nil {
+		return err
+	}
+
+	s.from.RemoveOwner(owner, obj)
+
+	return nil
+}
+
+// SetControllerReference adds owner as OwnerReference to obj, with Controller set to true.
+// Removes the owner from references of the previous strategy.
+func (s *OwnerStrategyMigrating) SetControllerReference(owner, obj metav1.Object) error {
+	// Ensure that there is no other controller using the previous strategy.
+	if ref, ok := s.from.GetController(obj); ok && !s.from.IsController(owner, obj) {
+		return &controllerutil.AlreadyOwnedError{
+			Object: obj,
+			Owner:  ref,
+		}
+	}
+
+	if err := s.to.SetControllerReference(owner, obj); err != nil {
+		return err
+	}
+
+	s.from.RemoveOwner(owner, obj)
+
+	return nil
+}
+
+// IsOwner returns true if owner is contained in object OwnerReference list.
+func (s *OwnerStrategyMigrating) IsOwner(owner, obj metav1.Object) bool {
+	return s.to.IsOwner(owner, obj) || s.from.IsOwner(owner, obj)
+}
+
+// IsController returns true if the given owner is the controller of obj.
+func (s *OwnerStrategyMigrating) IsController(owner, obj metav1.Object) bool {
+	return s.to.IsController(owner, obj) || s.from.IsController(owner, obj)
+}
+
+// RemoveOwner removes the owner from objs OwnerReference list.
+func (s *OwnerStrategyMigrating) RemoveOwner(owner, obj metav1.Object) {
+	s.from.RemoveOwner(owner, obj)
+	s.to.RemoveOwner(owner, obj)
+}
+
+// ReleaseController sets all OwnerReferences Controller to false.
+func (s *OwnerStrategyMigrating) ReleaseController(obj metav1.Object) {
+	s.from.ReleaseController(obj)
+	s.to.ReleaseController(obj)
+}
+
+// IsMigrated returns true if obj no longer references owner using the previous strategy.
+func (s *OwnerStrategyMigrating) IsMigrated(owner, obj metav1.Object) bool {
+	return !s.from.IsOwner(owner, obj)
+}
+
+// MigrationProgress reports how many objects of an owner have been migrated.
+type MigrationProgress struct {
+	// Total number of objects checked.
+	Total int
+	// Migrated number of objects.
+	Migrated int
+	// Pending objects still referencing the owner using the previous strategy.
+	Pending []metav1.Object
+}
+
+// Done returns true when all objects have been migrated.
+func (p MigrationProgress) Done() bool {
+	return p.Migrated == p.Total
+}
+
+// String returns a human readable description of the progress.
+func (p MigrationProgress) String() string {
+	return fmt.Sprintf("%d/%d objects migrated", p.Migrated, p.Total)
+}
+
+// Progress checks the given objects for references to owner using the previous strategy.
+// Pass objects as last seen on the cluster, e.g. from the ObjectResults of a RevisionResult,
+// to report migration progress across a revision.
+func (s *OwnerStrategyMigrating) Progress(owner metav1.Object, objs ...metav1.Object) MigrationProgress {
+	p := MigrationProgress{Total: len(objs)}
+
+	for _, obj := range objs {
+		if s.IsMigrated(owner, obj) {
+			p.Migrated++
+
+			continue
+		}
+
+		p.Pending = append(p.Pending, obj)
+	}
+
+	return p
+}
+
+// multiEventHandler forwards events to all handlers.
+type multiEventHandler []handler.EventHandler
+
+// Create implements EventHandler.
+func (h multiEventHandler) Create(
+	ctx context.Context, evt event.CreateEvent, q workqueue.TypedRateLimitingInterface[reconcile.Request],
+) {
+	for _, eh := range h {
+		eh.Create(ctx, evt, q)
+	}
+}
+
+// Update implements EventHandler.
+func (h multiEventHandler) Update(
+	ctx context.Context, evt event.UpdateEvent, q workqueue.TypedRateLimitingInterface[reconcile.Request],
+) {
+	for _, eh := range h {
+		eh.Update(ctx, evt, q)
+	}
+}
+
+// Delete implements EventHandler.
+func (h multiEventHandler) Delete(
+	ctx context.Context, evt event.DeleteEvent, q workqueue.TypedRateLimitingInterface[reconcile.Request],
+) {
+	for _, eh := range h {
+		eh.Delete(ctx, evt, q)
+	}
+}
+
+// Generic implements EventHandler.
+func (h multiEventHandler) Generic(
+	ctx context.Context, evt event.GenericEvent, q workqueue.TypedRateLimitingInterface[reconcile.Request],
+) {
+	for _, eh := range h {
+		eh.Generic(ctx, evt, q)
+	}
+}
@@ -0,0 +1,115 @@
+package ownerhandling
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+func TestOwnerStrategyMigrating(t *testing.T) {
+	t.Parallel()
+
+	native := NewNative(testScheme)
+	annotation := NewAnnotation(testScheme, testAnnotationKey)
+	s := NewMigrating(native, annotation)
+
+	newOwner := func(name string, uid types.UID) *corev1.ConfigMap {
+		return &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "test", UID: uid},
+		}
+	}
+
+	owner := newOwner("owner", "1")
+	other := newOwner("other", "2")
+
+	legacy := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "legacy", Namespace: "test"}}
+	require.NoError(t, native.SetControllerReference(owner, legacy))
+
+	t.Run("reads previous strategy", func(t *testing.T) {
+		t.Parallel()
+
+		ref, ok := s.GetController(legacy)
+		require.True(t, ok)
+		assert.Equal(t, types.UID("1"), ref.UID)
+		assert.True(t, s.IsController(owner, legacy))
+		assert.True(t, s.IsOwner(owner, legacy))
+		assert.False(t, s.IsController(other, legacy))
+		assert.Len(t, s.GetOwnerReferences(legacy), 1)
+	})
+
+	t.Run("writes new strategy", func(t *testing.T) {
+		t.Parallel()
+
+		obj := legacy.DeepCopy()
+		require.NoError(t, s.SetControllerReference(owner, obj))
+
+		assert.Empty(t, obj.OwnerReferences)
+		assert.True(t, annotation.IsController(owner, obj))
+		assert.True(t, s.IsMigrated(owner, obj))
+	})
+
+	t.Run("collision with previous strategy", func(t *testing.T) {
+		t.Parallel()
+
+		obj := legacy.DeepCopy()
+
+		var aoErr *controllerutil.AlreadyOwnedError
+		require.ErrorAs(t, s.SetControllerReference(other, obj), &aoErr)
+		assert.Equal(t, "owner", aoErr.Owner.Name)
+	})
+
+	t.Run("RemoveOwner", func(t *testing.T) {
+		t.Parallel()
+
+		obj := legacy.DeepCopy()
+		require.NoError(t, annotation.SetOwnerReference(owner, obj))
+
+		s.RemoveOwner(owner, obj)
+		assert.False(t, s.IsOwner(owner, obj))
+	})
+
+	t.Run("CopyOwnerReferences", func(t *testing.T) {
+		t.Parallel()
+
+		actual := legacy.DeepCopy()
+		require.NoError(t, annotation.SetOwnerReference(other, actual))
+
+		desired := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "legacy", Namespace: "test"}}
+		require.NoError(t, native.SetOwnerReference(other, desired))
+
+		s.CopyOwnerReferences(actual, desired)
+
+		assert.Empty(t, desired.OwnerReferences)
+		assert.True(t, annotation.IsOwner(other, desired))
+		assert.False(t, s.IsOwner(owner, desired))
+	})
+
+	t.Run("Progress", func(t *testing.T) {
+		t.Parallel()
+
+		migrated := legacy.DeepCopy()
+		migrated.Name = "migrated"
+		require.NoError(t, s.SetControllerReference(owner, migrated))
+
+		p := s.Progress(owner, legacy, migrated)
+		assert.Equal(t, 2, p.Total)
+		assert.Equal(t, 1, p.Migrated)
+		assert.Equal(t, []metav1.Object{legacy}, p.Pending)
+		assert.False(t, p.Done())
+		assert.Equal(t, "1/2 objects migrated", p.String())
+
+		assert.True(t, s.Progress(owner, migrated).Done())
+	})
+
+	t.Run("EnqueueRequestForOwner", func(t *testing.T) {
+		t.Parallel()
+
+		h := s.EnqueueRequestForOwner(&corev1.ConfigMap{}, nil, true)
+		assert.Len(t, h, 2)
+	})
+}
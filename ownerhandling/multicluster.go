@@ -0,0 +1,45 @@
+package ownerhandling
+
+import (
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+var _ ownerStrategy = (*OwnerStrategyMultiCluster)(nil)
+
+// OwnerStrategyMultiCluster handling strategy uses .metadata.annotations
+// and records the cluster owners live in within each reference.
+// Allows owners in a different cluster than the objects they own,
+// e.g. owners in a management cluster and objects in a hosted cluster.
+//
+// References of other clusters are never considered to be the same owner
+// and GetController reports controllers of other clusters with a UID prefixed by their cluster,
+// so they are treated as unknown controllers.
+//
+// To reconcile owners on changes in the remote cluster,
+// pass the handler returned by EnqueueRequestForOwner to the Source
+// of the remote clusters managedcache.TrackingCache.
+// Only references of this cluster are enqueued.
+type OwnerStrategyMultiCluster struct {
+	*OwnerStrategyAnnotation
+}
+
+// NewMultiCluster returns a new OwnerStrategyMultiCluster instance.
+// cluster must uniquely identify the cluster owners are living in.
+func NewMultiCluster(scheme *runtime.Scheme, annotationKey, cluster string) *OwnerStrategyMultiCluster {
+	if len(cluster) == 0 {
+		panic("cluster identifier must not be empty")
+	}
+
+	return &OwnerStrategyMultiCluster{
+		OwnerStrategyAnnotation: &OwnerStrategyAnnotation{
+			scheme:        scheme,
+			annotationKey: annotationKey,
+			cluster:       cluster,
+		},
+	}
+}
+
+// Cluster returns the identifier of the cluster owners are living in.
+func (s *OwnerStrategyMultiCluster) Cluster() string {
+	return s.cluster
+}
@@ -0,0 +1,114 @@
+package ownerhandling
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+func TestOwnerStrategyMultiCluster(t *testing.T) {
+	t.Parallel()
+
+	owner := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "owner",
+			Namespace: "mgmt",
+			UID:       types.UID("1234"),
+		},
+	}
+
+	mgmt := NewMultiCluster(testScheme, testAnnotationKey, "mgmt-cluster")
+	other := NewMultiCluster(testScheme, testAnnotationKey, "other-cluster")
+	local := NewAnnotation(testScheme, testAnnotationKey)
+
+	obj := &corev1.Secret{}
+	require.NoError(t, mgmt.SetControllerReference(owner, obj))
+
+	assert.JSONEq(t, `[{
+		"apiVersion": "v1",
+		"kind": "ConfigMap",
+		"name": "owner",
+		"namespace": "mgmt",
+		"uid": "1234",
+		"cluster": "mgmt-cluster",
+		"controller": true
+	}]`, obj.Annotations[testAnnotationKey])
+
+	t.Run("IsController", func(t *testing.T) {
+		t.Parallel()
+
+		assert.True(t, mgmt.IsController(owner, obj))
+		assert.True(t, mgmt.IsOwner(owner, obj))
+		assert.False(t, other.IsController(owner, obj))
+		assert.False(t, other.IsOwner(owner, obj))
+		assert.False(t, local.IsController(owner, obj))
+	})
+
+	t.Run("GetController", func(t *testing.T) {
+		t.Parallel()
+
+		ref, ok := mgmt.GetController(obj)
+		require.True(t, ok)
+		assert.Equal(t, types.UID("1234"), ref.UID)
+
+		ref, ok = other.GetController(obj)
+		require.True(t, ok)
+		assert.Equal(t, types.UID("mgmt-cluster/1234"), ref.UID)
+	})
+
+	t.Run("GetOwnerReferences", func(t *testing.T) {
+		t.Parallel()
+
+		refs := mgmt.GetOwnerReferences(obj)
+		require.Len(t, refs, 1)
+		assert.Equal(t, types.UID("1234"), refs[0].UID)
+
+		refs = local.GetOwnerReferences(obj)
+		require.Len(t, refs, 1)
+		assert.Equal(t, types.UID("mgmt-cluster/1234"), refs[0].UID)
+	})
+
+	t.Run("SetControllerReference collision", func(t *testing.T) {
+		t.Parallel()
+
+		o := obj.DeepCopy()
+
+		var aoErr *controllerutil.AlreadyOwnedError
+		require.ErrorAs(t, other.SetControllerReference(owner, o), &aoErr)
+	})
+
+	t.Run("RemoveOwner", func(t *testing.T) {
+		t.Parallel()
+
+		o := obj.DeepCopy()
+		other.RemoveOwner(owner, o)
+		assert.True(t, mgmt.IsController(owner, o))
+
+		mgmt.RemoveOwner(owner, o)
+		assert.False(t, mgmt.IsOwner(owner, o))
+	})
+
+	t.Run("EnqueueRequestForOwner", func(t *testing.T) {
+		t.Parallel()
+
+		expected := []reconcile.Request{{
+			NamespacedName: client.ObjectKey{Name: "owner", Namespace: "mgmt"},
+		}}
+
+		h := mgmt.EnqueueRequestForOwner(&corev1.ConfigMap{}, nil, true).(*AnnotationEnqueueRequestForOwner)
+		assert.Equal(t, expected, h.getOwnerReconcileRequest(obj))
+
+		h = other.EnqueueRequestForOwner(&corev1.ConfigMap{}, nil, true).(*AnnotationEnqueueRequestForOwner)
+		assert.Empty(t, h.getOwnerReconcileRequest(obj))
+
+		h = local.EnqueueRequestForOwner(&corev1.ConfigMap{}, nil, true).(*AnnotationEnqueueRequestForOwner)
+		assert.Empty(t, h.getOwnerReconcileRequest(obj))
+	})
+}
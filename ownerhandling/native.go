@@ -46,6 +46,11 @@ func (s *OwnerStrategyNative) CopyOwnerReferences(objA, objB metav1.Object) {
 	objB.SetOwnerReferences(objA.GetOwnerReferences())
 }
 
+// GetOwnerReferences returns all OwnerReferences of obj.
+func (s *OwnerStrategyNative) GetOwnerReferences(obj metav1.Object) []metav1.OwnerReference {
+	return obj.GetOwnerReferences()
+}
+
 // IsOwner returns true if owner is contained in object OwnerReference list.
 func (s *OwnerStrategyNative) IsOwner(owner, obj metav1.Object) bool {
 	ownerRefComp := s.ownerRefForCompare(owner)
@@ -3,74 +3,229 @@ package probing
 import (
 	"errors"
 	"fmt"
+	"reflect"
+	"sync"
 
 	"github.com/google/cel-go/cel"
-	"github.com/google/cel-go/ext"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
-	"k8s.io/apiserver/pkg/cel/library"
+	"k8s.io/apimachinery/pkg/runtime"
+	celconfig "k8s.io/apiserver/pkg/apis/cel"
+	"k8s.io/utils/lru"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"pkg.package-operator.run/boxcutter/internal/celenv"
 )
 
 // CELProbe uses the common expression language for probing.
+//
+// Variables are bound when the probe is created.
+// Probes using "revision", "phase" or "owner" must be created per reconcile,
+// so they don't evaluate against stale values. Compiled programs are cached,
+// so creating probes again is cheap.
 type CELProbe struct {
 	Program cel.Program
 	Message string
+	// MessageProgram is evaluated to produce the failure message, if set.
+	// Falls back to Message when evaluation fails.
+	// Use NewCELMessageProgram to compile it.
+	MessageProgram cel.Program
+	// Variables are made available to the CEL programs in addition to "self".
+	Variables map[string]any
 }
 
 var _ Prober = (*CELProbe)(nil)
 
-// ErrCELInvalidEvaluationType is raised when a CEL expression does not evaluate to a boolean.
-var ErrCELInvalidEvaluationType = errors.New("cel expression must evaluate to a bool")
+var (
+	// ErrCELInvalidEvaluationType is raised when a CEL expression does not evaluate to a boolean.
+	ErrCELInvalidEvaluationType = errors.New("cel expression must evaluate to a bool")
+	// ErrCELInvalidMessageEvaluationType is raised when a CEL message expression does not evaluate to a string.
+	ErrCELInvalidMessageEvaluationType = errors.New("cel message expression must evaluate to a string")
+	// ErrCELNilOwner is raised when WithCELOwner is given a nil owner.
+	ErrCELNilOwner = errors.New("cel owner must not be nil")
+)
+
+const (
+	// DefaultCELCostLimit is the runtime cost limit applied to each CEL program evaluation,
+	// unless configured otherwise. Matches the per-call limit of the kube-apiserver.
+	DefaultCELCostLimit uint64 = celconfig.PerCallLimit
+	// DefaultCELCompileCacheSize is the number of compiled programs kept in the default compile cache.
+	DefaultCELCompileCacheSize = 1024
+)
+
+// CELOptions holds configuration options for CEL probes.
+type CELOptions struct {
+	// CostLimit limits the runtime cost of each evaluation.
+	CostLimit uint64
+	// CompileCache to lookup and store compiled programs.
+	CompileCache *CELCompileCache
+	// Variables are made available to the CEL programs in addition to "self".
+	Variables map[string]any
+
+	// err is raised by options unable to apply their value.
+	err error
+}
+
+// Default sets empty Option fields to their default value.
+func (opts *CELOptions) Default() {
+	if opts.CostLimit == 0 {
+		opts.CostLimit = DefaultCELCostLimit
+	}
+
+	if opts.CompileCache == nil {
+		opts.CompileCache = defaultCELCompileCache
+	}
+}
+
+// CELOption is the common interface for CEL probe options.
+type CELOption interface {
+	ApplyToCELOptions(opts *CELOptions)
+}
+
+var (
+	_ CELOption = (WithCELCostLimit)(0)
+	_ CELOption = (WithCELCompileCache(nil))
+	_ CELOption = (WithCELRevision{})
+	_ CELOption = (WithCELPhase)("")
+	_ CELOption = (WithCELOwner{})
+)
+
+// WithCELCostLimit limits the runtime cost of each CEL program evaluation.
+type WithCELCostLimit uint64
+
+// ApplyToCELOptions implements CELOption.
+func (w WithCELCostLimit) ApplyToCELOptions(opts *CELOptions) {
+	opts.CostLimit = uint64(w)
+}
+
+// WithCELCompileCache uses the given cache to lookup and store compiled programs.
+func WithCELCompileCache(cache *CELCompileCache) CELOption {
+	return celOptionFn(func(opts *CELOptions) {
+		opts.CompileCache = cache
+	})
+}
+
+// WithCELRevision makes the "revision" variable available to CEL programs.
+// Fields are accessible as revision.name and revision.number.
+type WithCELRevision struct {
+	Name   string
+	Number int64
+}
+
+// ApplyToCELOptions implements CELOption.
+func (w WithCELRevision) ApplyToCELOptions(opts *CELOptions) {
+	setCELVariable(opts, "revision", map[string]any{
+		"name":   w.Name,
+		"number": w.Number,
+	})
+}
+
+// WithCELPhase makes the "phase" variable available to CEL programs.
+// The phase name is accessible as phase.name.
+type WithCELPhase string
+
+// ApplyToCELOptions implements CELOption.
+func (w WithCELPhase) ApplyToCELOptions(opts *CELOptions) {
+	setCELVariable(opts, "phase", map[string]any{
+		"name": string(w),
+	})
+}
+
+// WithCELOwner makes the "owner" variable available to CEL programs.
+// A nil Object fails probe creation with ErrCELNilOwner.
+type WithCELOwner struct {
+	client.Object
+}
+
+// ApplyToCELOptions implements CELOption.
+func (w WithCELOwner) ApplyToCELOptions(opts *CELOptions) {
+	if rv := reflect.ValueOf(w.Object); w.Object == nil || rv.Kind() == reflect.Pointer && rv.IsNil() {
+		opts.err = ErrCELNilOwner
+
+		return
+	}
+
+	owner, err := runtime.DefaultUnstructuredConverter.ToUnstructured(w.Object)
+	if err != nil {
+		opts.err = fmt.Errorf("converting cel owner: %w", err)
+
+		return
+	}
+
+	setCELVariable(opts, "owner", owner)
+}
+
+type celOptionFn func(opts *CELOptions)
+
+// ApplyToCELOptions implements CELOption.
+func (fn celOptionFn) ApplyToCELOptions(opts *CELOptions) {
+	fn(opts)
+}
+
+func setCELVariable(opts *CELOptions, name string, value any) {
+	if opts.Variables == nil {
+		opts.Variables = map[string]any{}
+	}
+
+	opts.Variables[name] = value
+}
 
 // NewCELProbe creates a new CEL (Common Expression Language) Probe.
 // A CEL probe runs a CEL expression against the target object that needs to evaluate to a bool.
-func NewCELProbe(rule, message string) (
+// The object is available as "self", the optional variables "revision", "phase"
+// and "owner" can be provided via options.
+func NewCELProbe(rule, message string, opts ...CELOption) (
 	*CELProbe, error,
 ) {
-	env, err := cel.NewEnv(
-		cel.Variable("self", cel.DynType),
-		cel.HomogeneousAggregateLiterals(),
-		cel.EagerlyValidateDeclarations(true),
-		cel.DefaultUTCTimeZone(true),
-
-		ext.Strings(ext.StringsVersion(0)),
-		library.URLs(),
-		library.Regex(),
-		library.Lists(),
-	)
-	if err != nil {
-		return nil, fmt.Errorf("creating CEL env: %w", err)
+	var options CELOptions
+	for _, opt := range opts {
+		opt.ApplyToCELOptions(&options)
 	}
 
-	ast, issues := env.Compile(rule)
-	if issues != nil {
-		return nil, fmt.Errorf("compiling CEL: %w", issues.Err())
-	}
+	options.Default()
 
-	if ast.OutputType() != cel.BoolType {
-		return nil, ErrCELInvalidEvaluationType
+	if options.err != nil {
+		return nil, options.err
 	}
 
-	prgm, err := env.Program(ast)
+	prgm, err := options.CompileCache.compile(rule, cel.BoolType, options.CostLimit)
 	if err != nil {
-		return nil, fmt.Errorf("CEL program failed: %w", err)
+		return nil, err
 	}
 
 	return &CELProbe{
-		Program: prgm,
-		Message: message,
+		Program:   prgm,
+		Message:   message,
+		Variables: options.Variables,
 	}, nil
 }
 
+// NewCELMessageProgram compiles a CEL expression evaluating to a string,
+// to be used as CELProbe.MessageProgram.
+func NewCELMessageProgram(expression string, opts ...CELOption) (cel.Program, error) {
+	var options CELOptions
+	for _, opt := range opts {
+		opt.ApplyToCELOptions(&options)
+	}
+
+	options.Default()
+
+	return options.CompileCache.compile(expression, cel.StringType, options.CostLimit)
+}
+
 // Probe executes the probe.
 func (p *CELProbe) Probe(obj client.Object) Result {
 	return probeUnstructuredSingleMsg(obj, p.probe)
 }
 
 func (p *CELProbe) probe(obj *unstructured.Unstructured) Result {
-	val, _, err := p.Program.Eval(map[string]any{
-		"self": obj.Object,
-	})
+	activation := make(map[string]any, len(p.Variables)+1)
+	for k, v := range p.Variables {
+		activation[k] = v
+	}
+
+	activation["self"] = obj.Object
+
+	val, _, err := p.Program.Eval(activation)
 	if err != nil {
 		return Result{
 			Status:   StatusUnknown,
@@ -87,6 +242,112 @@ func (p *CELProbe) probe(obj *unstructured.Unstructured) Result {
 
 	return Result{
 		Status:   StatusFalse,
-		Messages: []string{p.Message},
+		Messages: []string{p.message(activation)},
+	}
+}
+
+func (p *CELProbe) message(activation map[string]any) string {
+	if p.MessageProgram == nil {
+		return p.Message
+	}
+
+	val, _, err := p.MessageProgram.Eval(activation)
+	if err == nil {
+		if msg, ok := val.Value().(string); ok && len(msg) > 0 {
+			return msg
+		}
+	}
+
+	if len(p.Message) > 0 {
+		return p.Message
+	}
+
+	if err != nil {
+		return fmt.Sprintf("CEL message expression failed: %v", err)
+	}
+
+	return "CEL message expression returned an empty string"
+}
+
+// CELCompileCache caches compiled CEL programs,
+// so the same expression used across many probes is only compiled once.
+// CELCompileCache is safe for concurrent use.
+type CELCompileCache struct {
+	cache *lru.Cache
+}
+
+// NewCELCompileCache returns a new CELCompileCache holding at most maxEntries programs.
+func NewCELCompileCache(maxEntries int) *CELCompileCache {
+	return &CELCompileCache{cache: lru.New(maxEntries)}
+}
+
+var defaultCELCompileCache = NewCELCompileCache(DefaultCELCompileCacheSize)
+
+type celCompileCacheKey struct {
+	expression string
+	outputType string
+	costLimit  uint64
+}
+
+type celCompileCacheEntry struct {
+	program cel.Program
+	err     error
+}
+
+func (c *CELCompileCache) compile(
+	expression string, outputType *cel.Type, costLimit uint64,
+) (cel.Program, error) {
+	key := celCompileCacheKey{
+		expression: expression,
+		outputType: outputType.String(),
+		costLimit:  costLimit,
+	}
+	if entry, ok := c.cache.Get(key); ok {
+		e := entry.(celCompileCacheEntry)
+
+		return e.program, e.err
+	}
+
+	prgm, err := compileCEL(expression, outputType, costLimit)
+	c.cache.Add(key, celCompileCacheEntry{program: prgm, err: err})
+
+	return prgm, err
+}
+
+var celEnv = sync.OnceValues(func() (*cel.Env, error) {
+	return celenv.New(
+		cel.Variable("self", cel.DynType),
+		cel.Variable("revision", cel.DynType),
+		cel.Variable("phase", cel.DynType),
+		cel.Variable("owner", cel.DynType),
+	)
+})
+
+func compileCEL(expression string, outputType *cel.Type, costLimit uint64) (cel.Program, error) {
+	env, err := celEnv()
+	if err != nil {
+		return nil, fmt.Errorf("creating CEL env: %w", err)
+	}
+
+	ast, issues := env.Compile(expression)
+	if issues != nil && issues.Err() != nil {
+		return nil, fmt.Errorf("compiling CEL: %w", issues.Err())
+	}
+
+	switch {
+	case ast.OutputType().IsExactType(outputType):
+	case ast.OutputType().IsExactType(cel.DynType) && outputType == cel.StringType:
+		// Dynamic results are checked at runtime.
+	case outputType == cel.StringType:
+		return nil, ErrCELInvalidMessageEvaluationType
+	default:
+		return nil, ErrCELInvalidEvaluationType
 	}
+
+	prgm, err := celenv.Program(env, ast, costLimit)
+	if err != nil {
+		return nil, fmt.Errorf("CEL program failed: %w", err)
+	}
+
+	return prgm, nil
 }
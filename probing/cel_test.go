@@ -129,3 +129,146 @@ func Test_celProbe(t *testing.T) {
 		})
 	}
 }
+
+func Test_celProbe_Variables(t *testing.T) {
+	t.Parallel()
+
+	owner := &unstructured.Unstructured{
+		Object: map[string]any{
+			"metadata": map[string]any{
+				"name": "owner",
+			},
+		},
+	}
+
+	p, err := NewCELProbe(
+		`revision.number == 3 && revision.name == "rev" && phase.name == "deploy" && owner.metadata.name == "owner"`,
+		"context mismatch",
+		WithCELRevision{Name: "rev", Number: 3},
+		WithCELPhase("deploy"),
+		WithCELOwner{Object: owner},
+	)
+	require.NoError(t, err)
+
+	r := p.Probe(&unstructured.Unstructured{Object: map[string]any{}})
+	assert.Equal(t, StatusTrue, r.Status)
+
+	t.Run("unset variable", func(t *testing.T) {
+		t.Parallel()
+
+		p, err := NewCELProbe(`phase.name == "deploy"`, "")
+		require.NoError(t, err)
+
+		r := p.Probe(&unstructured.Unstructured{Object: map[string]any{}})
+		assert.Equal(t, StatusUnknown, r.Status)
+	})
+
+	t.Run("nil owner", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := NewCELProbe(`true`, "", WithCELOwner{})
+		require.ErrorIs(t, err, ErrCELNilOwner)
+
+		var nilOwner *unstructured.Unstructured
+
+		_, err = NewCELProbe(`true`, "", WithCELOwner{Object: nilOwner})
+		require.ErrorIs(t, err, ErrCELNilOwner)
+	})
+}
+
+func Test_celProbe_MessageExpression(t *testing.T) {
+	t.Parallel()
+
+	obj := &unstructured.Unstructured{
+		Object: map[string]any{
+			"spec":   map[string]any{"replicas": int64(5)},
+			"status": map[string]any{"readyReplicas": int64(3)},
+		},
+	}
+
+	t.Run("evaluated", func(t *testing.T) {
+		t.Parallel()
+
+		p, err := NewCELProbe(`self.status.readyReplicas == self.spec.replicas`, "not ready")
+		require.NoError(t, err)
+
+		p.MessageProgram, err = NewCELMessageProgram(
+			`string(self.status.readyReplicas) + "/" + string(self.spec.replicas) + " replicas ready"`)
+		require.NoError(t, err)
+
+		r := p.Probe(obj)
+		assert.Equal(t, StatusFalse, r.Status)
+		assert.Equal(t, []string{"3/5 replicas ready"}, r.Messages)
+	})
+
+	t.Run("falls back to message", func(t *testing.T) {
+		t.Parallel()
+
+		p, err := NewCELProbe(`self.status.readyReplicas == self.spec.replicas`, "not ready")
+		require.NoError(t, err)
+
+		p.MessageProgram, err = NewCELMessageProgram(`self.status.missing`)
+		require.NoError(t, err)
+
+		r := p.Probe(obj)
+		assert.Equal(t, StatusFalse, r.Status)
+		assert.Equal(t, []string{"not ready"}, r.Messages)
+	})
+
+	t.Run("invalid type", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := NewCELMessageProgram(`1 + 1`)
+		require.ErrorIs(t, err, ErrCELInvalidMessageEvaluationType)
+	})
+}
+
+func Test_celProbe_CostLimit(t *testing.T) {
+	t.Parallel()
+
+	items := make([]any, 100)
+	for i := range items {
+		items[i] = int64(i)
+	}
+
+	obj := &unstructured.Unstructured{
+		Object: map[string]any{"items": items},
+	}
+	rule := `self.items.all(a, self.items.all(b, a + b >= 0))`
+
+	p, err := NewCELProbe(rule, "", WithCELCostLimit(100))
+	require.NoError(t, err)
+
+	r := p.Probe(obj)
+	assert.Equal(t, StatusUnknown, r.Status)
+	assert.Contains(t, r.Messages[0], "cost limit exceeded")
+
+	p, err = NewCELProbe(rule, "")
+	require.NoError(t, err)
+
+	r = p.Probe(obj)
+	assert.Equal(t, StatusTrue, r.Status)
+}
+
+func Test_CELCompileCache(t *testing.T) {
+	t.Parallel()
+
+	cache := NewCELCompileCache(10)
+
+	p1, err := NewCELProbe(`self.metadata.name == "hans"`, "a", WithCELCompileCache(cache))
+	require.NoError(t, err)
+	p2, err := NewCELProbe(`self.metadata.name == "hans"`, "b", WithCELCompileCache(cache))
+	require.NoError(t, err)
+	p3, err := NewCELProbe(`self.metadata.name == "hans"`, "b",
+		WithCELCompileCache(cache), WithCELCostLimit(10))
+	require.NoError(t, err)
+
+	assert.Same(t, p1.Program, p2.Program)
+	assert.NotSame(t, p1.Program, p3.Program)
+	assert.Equal(t, 2, cache.cache.Len())
+
+	_, err = NewCELProbe(`self.test`, "", WithCELCompileCache(cache))
+	require.ErrorIs(t, err, ErrCELInvalidEvaluationType)
+	_, err = NewCELProbe(`self.test`, "", WithCELCompileCache(cache))
+	require.ErrorIs(t, err, ErrCELInvalidEvaluationType)
+}
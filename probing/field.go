@@ -0,0 +1,358 @@
+package probing
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/util/jsonpath"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// FieldOperator is used by FieldProbe to evaluate values found under a JSONPath.
+type FieldOperator string
+
+const (
+	// FieldOperatorEqual requires all found values to be equal to the single given value.
+	FieldOperatorEqual FieldOperator = "=="
+	// FieldOperatorGreaterOrEqual requires all found values to be numbers or
+	// quantities greater or equal to the single given value.
+	FieldOperatorGreaterOrEqual FieldOperator = ">="
+	// FieldOperatorLessOrEqual requires all found values to be numbers or
+	// quantities less or equal to the single given value.
+	FieldOperatorLessOrEqual FieldOperator = "<="
+	// FieldOperatorIn requires all found values to be equal to one of the given values.
+	FieldOperatorIn FieldOperator = "in"
+	// FieldOperatorMatches requires all found values to match the single given regular expression.
+	// The expression is unanchored and matches substrings, use ^ and $ to match whole values.
+	FieldOperatorMatches FieldOperator = "matches"
+	// FieldOperatorExists requires at least one value to be found. Takes no values.
+	FieldOperatorExists FieldOperator = "exists"
+	// FieldOperatorNotExists requires no value to be found. Takes no values.
+	FieldOperatorNotExists FieldOperator = "notExists"
+)
+
+var (
+	// ErrFieldProbeUnknownOperator is returned when an unsupported FieldOperator is used.
+	ErrFieldProbeUnknownOperator = errors.New("unknown field operator")
+	// ErrFieldProbeInvalidPath is returned when the JSONPath can't be parsed.
+	ErrFieldProbeInvalidPath = errors.New("invalid JSONPath")
+	// ErrFieldProbeInvalidValues is returned when the values do not fit the operator.
+	ErrFieldProbeInvalidValues = errors.New("invalid values for operator")
+)
+
+// FieldProbe evaluates the values found under a JSONPath expression using an operator.
+// The path supports list filters like `.status.conditions[?(@.type=="Ready")].status`.
+// All values found under the path have to satisfy the operator.
+// Use NewFieldProbe to validate the probe upfront,
+// invalid probes constructed otherwise report StatusUnknown.
+// Probes are compiled once, fields must not be changed after the first call to Probe.
+type FieldProbe struct {
+	Path     string
+	Operator FieldOperator
+	Values   []string
+
+	// compileOnce compiles probes not created by NewFieldProbe on first use.
+	compileOnce sync.Once
+	compiled    *compiledFieldProbe
+	compileErr  error
+}
+
+// compiledFieldProbe holds the parsed path and operator values.
+// It is safe for concurrent use.
+type compiledFieldProbe struct {
+	regexp   *regexp.Regexp
+	quantity resource.Quantity
+
+	// jsonPathMux guards jsonPath, as JSONPath keeps state while evaluating.
+	jsonPathMux sync.Mutex
+	jsonPath    *jsonpath.JSONPath
+}
+
+// findResults evaluates the JSONPath against obj.
+func (c *compiledFieldProbe) findResults(obj map[string]any) ([][]reflect.Value, error) {
+	c.jsonPathMux.Lock()
+	defer c.jsonPathMux.Unlock()
+
+	return c.jsonPath.FindResults(obj)
+}
+
+var _ Prober = (*FieldProbe)(nil)
+
+// NewFieldProbe parses the given JSONPath and operator values and returns a new FieldProbe.
+func NewFieldProbe(path string, op FieldOperator, values ...string) (*FieldProbe, error) {
+	fp := &FieldProbe{
+		Path:     path,
+		Operator: op,
+		Values:   values,
+	}
+
+	compiled, err := fp.compile()
+	if err != nil {
+		return nil, err
+	}
+
+	fp.compileOnce.Do(func() { fp.compiled = compiled })
+
+	return fp, nil
+}
+
+// compile validates the path, operator and values.
+func (fp *FieldProbe) compile() (*compiledFieldProbe, error) {
+	jp, err := parseJSONPath(fp.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	op, values := fp.Operator, fp.Values
+
+	switch op {
+	case FieldOperatorExists, FieldOperatorNotExists:
+		if len(values) != 0 {
+			return nil, fmt.Errorf("%w %q: expected none, got %d", ErrFieldProbeInvalidValues, op, len(values))
+		}
+
+	case FieldOperatorIn:
+		if len(values) == 0 {
+			return nil, fmt.Errorf("%w %q: expected at least one", ErrFieldProbeInvalidValues, op)
+		}
+
+	case FieldOperatorEqual, FieldOperatorMatches,
+		FieldOperatorGreaterOrEqual, FieldOperatorLessOrEqual:
+		if len(values) != 1 {
+			return nil, fmt.Errorf("%w %q: expected exactly one, got %d", ErrFieldProbeInvalidValues, op, len(values))
+		}
+
+	default:
+		return nil, fmt.Errorf("%w %q", ErrFieldProbeUnknownOperator, op)
+	}
+
+	compiled := &compiledFieldProbe{jsonPath: jp}
+
+	switch op {
+	case FieldOperatorMatches:
+		re, err := regexp.Compile(values[0])
+		if err != nil {
+			return nil, fmt.Errorf("%w %q: parsing regular expression: %w", ErrFieldProbeInvalidValues, op, err)
+		}
+
+		compiled.regexp = re
+
+	case FieldOperatorGreaterOrEqual, FieldOperatorLessOrEqual:
+		q, err := resource.ParseQuantity(values[0])
+		if err != nil {
+			return nil, fmt.Errorf("%w %q: parsing number: %w", ErrFieldProbeInvalidValues, op, err)
+		}
+
+		compiled.quantity = q
+	}
+
+	return compiled, nil
+}
+
+// parseJSONPath returns a new JSONPath for path.
+// JSONPath keeps state while evaluating and must not be used concurrently.
+func parseJSONPath(path string) (*jsonpath.JSONPath, error) {
+	jp := jsonpath.New("").AllowMissingKeys(true)
+	if err := jp.Parse(normalizeJSONPath(path)); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrFieldProbeInvalidPath, err)
+	}
+
+	return jp, nil
+}
+
+// Probe executes the probe.
+func (fp *FieldProbe) Probe(obj client.Object) Result {
+	return probeUnstructuredSingleMsg(obj, fp.probe)
+}
+
+func (fp *FieldProbe) probe(obj *unstructured.Unstructured) Result {
+	fp.compileOnce.Do(func() { fp.compiled, fp.compileErr = fp.compile() })
+
+	compiled := fp.compiled
+	if fp.compileErr != nil {
+		return Result{
+			Status:   StatusUnknown,
+			Messages: []string{fmt.Sprintf(`invalid probe for %q: %v`, fp.Path, fp.compileErr)},
+		}
+	}
+
+	results, err := compiled.findResults(obj.Object)
+	if err != nil {
+		return Result{
+			Status:   StatusUnknown,
+			Messages: []string{fmt.Sprintf(`evaluating %q: %v`, fp.Path, err)},
+		}
+	}
+
+	var found []any
+
+	for _, rs := range results {
+		for _, r := range rs {
+			if !r.IsValid() || (r.Kind() == reflect.Interface && r.IsNil()) {
+				continue
+			}
+
+			found = append(found, r.Interface())
+		}
+	}
+
+	switch fp.Operator {
+	case FieldOperatorExists:
+		if len(found) == 0 {
+			return FalseResult(fmt.Sprintf(`%q missing`, fp.Path))
+		}
+
+		return TrueResult(fmt.Sprintf(`%q exists`, fp.Path))
+
+	case FieldOperatorNotExists:
+		if len(found) > 0 {
+			return FalseResult(fmt.Sprintf(`%q exists; observed: %s`, fp.Path, observedString(found)))
+		}
+
+		return TrueResult(fmt.Sprintf(`%q does not exist`, fp.Path))
+	}
+
+	if len(found) == 0 {
+		return FalseResult(fmt.Sprintf(`%q missing`, fp.Path))
+	}
+
+	for _, v := range found {
+		ok, err := fp.evaluate(compiled, v)
+		if errors.Is(err, ErrFieldProbeUnknownOperator) {
+			return Result{
+				Status:   StatusUnknown,
+				Messages: []string{fmt.Sprintf(`invalid probe for %q: %v`, fp.Path, err)},
+			}
+		}
+
+		if err != nil {
+			return FalseResult(fmt.Sprintf(`%q %s: %v; observed: %s`,
+				fp.Path, fp.Operator, err, observedString(found)))
+		}
+
+		if !ok {
+			return FalseResult(fmt.Sprintf(`%q %s %s failed; observed: %s`,
+				fp.Path, fp.Operator, fp.valuesString(), observedString(found)))
+		}
+	}
+
+	return TrueResult(fmt.Sprintf(`%q %s %s`, fp.Path, fp.Operator, fp.valuesString()))
+}
+
+func (fp *FieldProbe) evaluate(compiled *compiledFieldProbe, v any) (bool, error) {
+	switch fp.Operator {
+	case FieldOperatorEqual:
+		return valueString(v) == fp.Values[0], nil
+
+	case FieldOperatorIn:
+		return slices.Contains(fp.Values, valueString(v)), nil
+
+	case FieldOperatorMatches:
+		s, ok := v.(string)
+		if !ok {
+			return false, errors.New("value is not a string")
+		}
+
+		return compiled.regexp.MatchString(s), nil
+
+	case FieldOperatorGreaterOrEqual, FieldOperatorLessOrEqual:
+		q, err := valueQuantity(v)
+		if err != nil {
+			return false, err
+		}
+
+		c := q.Cmp(compiled.quantity)
+		if fp.Operator == FieldOperatorGreaterOrEqual {
+			return c >= 0, nil
+		}
+
+		return c <= 0, nil
+	}
+
+	return false, fmt.Errorf("%w %q", ErrFieldProbeUnknownOperator, fp.Operator)
+}
+
+func (fp *FieldProbe) valuesString() string {
+	quoted := make([]string, len(fp.Values))
+	for i, v := range fp.Values {
+		quoted[i] = strconv.Quote(v)
+	}
+
+	if fp.Operator == FieldOperatorIn {
+		return "[" + strings.Join(quoted, ", ") + "]"
+	}
+
+	return strings.Join(quoted, ", ")
+}
+
+// normalizeJSONPath accepts paths with or without leading dot and curly braces.
+func normalizeJSONPath(path string) string {
+	path = strings.TrimSpace(path)
+	if strings.HasPrefix(path, "{") {
+		return path
+	}
+
+	if !strings.HasPrefix(path, ".") && !strings.HasPrefix(path, "[") {
+		path = "." + path
+	}
+
+	return "{" + path + "}"
+}
+
+func valueString(v any) string {
+	switch tv := v.(type) {
+	case string:
+		return tv
+	case bool, int, int32, int64, float32, float64:
+		return fmt.Sprint(tv)
+	}
+
+	b, err := json.Marshal(v)
+	if err != nil {
+		return "<value marshal failed>"
+	}
+
+	return string(b)
+}
+
+func valueQuantity(v any) (resource.Quantity, error) {
+	switch tv := v.(type) {
+	case int64:
+		return *resource.NewQuantity(tv, resource.DecimalSI), nil
+	case int:
+		return *resource.NewQuantity(int64(tv), resource.DecimalSI), nil
+	case float64:
+		return resource.ParseQuantity(strconv.FormatFloat(tv, 'f', -1, 64))
+	case string:
+		return resource.ParseQuantity(tv)
+	}
+
+	return resource.Quantity{}, fmt.Errorf("value of type %T is not a number", v)
+}
+
+func observedString(found []any) string {
+	if len(found) == 1 {
+		b, err := json.Marshal(found[0])
+		if err != nil {
+			return "<value marshal failed>"
+		}
+
+		return string(b)
+	}
+
+	b, err := json.Marshal(found)
+	if err != nil {
+		return "<value marshal failed>"
+	}
+
+	return string(b)
+}
@@ -0,0 +1,278 @@
+package probing
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func Test_FieldProbe(t *testing.T) {
+	t.Parallel()
+
+	obj := &unstructured.Unstructured{
+		Object: map[string]any{
+			"spec": map[string]any{
+				"replicas": int64(3),
+				"image":    "quay.io/test/app:v1.2.3",
+				"memory":   "512Mi",
+			},
+			"status": map[string]any{
+				"readyReplicas": int64(2),
+				"conditions": []any{
+					map[string]any{"type": "Available", "status": "True"},
+					map[string]any{"type": "Ready", "status": "False"},
+				},
+			},
+		},
+	}
+
+	tests := []struct {
+		name     string
+		path     string
+		op       FieldOperator
+		values   []string
+		status   Status
+		messages []string
+	}{
+		{
+			name:     "equal with list filter",
+			path:     `status.conditions[?(@.type=="Available")].status`,
+			op:       FieldOperatorEqual,
+			values:   []string{"True"},
+			status:   StatusTrue,
+			messages: []string{`"status.conditions[?(@.type==\"Available\")].status" == "True"`},
+		},
+		{
+			name:     "equal with list filter failure",
+			path:     `.status.conditions[?(@.type=="Ready")].status`,
+			op:       FieldOperatorEqual,
+			values:   []string{"True"},
+			status:   StatusFalse,
+			messages: []string{`".status.conditions[?(@.type==\"Ready\")].status" == "True" failed; observed: "False"`},
+		},
+		{
+			name:     "greater or equal",
+			path:     `.status.readyReplicas`,
+			op:       FieldOperatorGreaterOrEqual,
+			values:   []string{"2"},
+			status:   StatusTrue,
+			messages: []string{`".status.readyReplicas" >= "2"`},
+		},
+		{
+			name:     "greater or equal failure",
+			path:     `.status.readyReplicas`,
+			op:       FieldOperatorGreaterOrEqual,
+			values:   []string{"3"},
+			status:   StatusFalse,
+			messages: []string{`".status.readyReplicas" >= "3" failed; observed: 2`},
+		},
+		{
+			name:     "less or equal quantity",
+			path:     `{.spec.memory}`,
+			op:       FieldOperatorLessOrEqual,
+			values:   []string{"1Gi"},
+			status:   StatusTrue,
+			messages: []string{`"{.spec.memory}" <= "1Gi"`},
+		},
+		{
+			name:     "not a number",
+			path:     `.spec.image`,
+			op:       FieldOperatorLessOrEqual,
+			values:   []string{"1"},
+			status:   StatusFalse,
+			messages: []string{`".spec.image" <=: quantities must match the regular expression '^([+-]?[0-9.]+)([eEinumkKMGTP]*[-+]?[0-9]*)$'; observed: "quay.io/test/app:v1.2.3"`},
+		},
+		{
+			name:     "in",
+			path:     `.status.conditions[*].status`,
+			op:       FieldOperatorIn,
+			values:   []string{"True", "False"},
+			status:   StatusTrue,
+			messages: []string{`".status.conditions[*].status" in ["True", "False"]`},
+		},
+		{
+			name:     "in failure",
+			path:     `.status.conditions[*].status`,
+			op:       FieldOperatorIn,
+			values:   []string{"True"},
+			status:   StatusFalse,
+			messages: []string{`".status.conditions[*].status" in ["True"] failed; observed: ["True","False"]`},
+		},
+		{
+			name:     "matches",
+			path:     `.spec.image`,
+			op:       FieldOperatorMatches,
+			values:   []string{`^quay\.io/`},
+			status:   StatusTrue,
+			messages: []string{`".spec.image" matches "^quay\\.io/"`},
+		},
+		{
+			name:     "exists",
+			path:     `.status.conditions[?(@.type=="Ready")]`,
+			op:       FieldOperatorExists,
+			status:   StatusTrue,
+			messages: []string{`".status.conditions[?(@.type==\"Ready\")]" exists`},
+		},
+		{
+			name:     "exists failure",
+			path:     `.status.conditions[?(@.type=="Progressing")]`,
+			op:       FieldOperatorExists,
+			status:   StatusFalse,
+			messages: []string{`".status.conditions[?(@.type==\"Progressing\")]" missing`},
+		},
+		{
+			name:     "notExists",
+			path:     `.status.missing`,
+			op:       FieldOperatorNotExists,
+			status:   StatusTrue,
+			messages: []string{`".status.missing" does not exist`},
+		},
+		{
+			name:     "notExists failure",
+			path:     `.spec.replicas`,
+			op:       FieldOperatorNotExists,
+			status:   StatusFalse,
+			messages: []string{`".spec.replicas" exists; observed: 3`},
+		},
+		{
+			name:     "missing",
+			path:     `.status.missing`,
+			op:       FieldOperatorEqual,
+			values:   []string{"x"},
+			status:   StatusFalse,
+			messages: []string{`".status.missing" missing`},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			p, err := NewFieldProbe(test.path, test.op, test.values...)
+			require.NoError(t, err)
+
+			r := p.Probe(obj)
+			assert.Equal(t, test.status, r.Status)
+			assert.Equal(t, test.messages, r.Messages)
+		})
+	}
+}
+
+func Test_NewFieldProbe(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		path   string
+		op     FieldOperator
+		values []string
+		err    error
+	}{
+		{name: "unknown operator", path: ".spec", op: "!=", values: []string{"x"}, err: ErrFieldProbeUnknownOperator},
+		{name: "invalid path", path: ".spec[", op: FieldOperatorExists, err: ErrFieldProbeInvalidPath},
+		{name: "exists with values", path: ".spec", op: FieldOperatorExists, values: []string{"x"}, err: ErrFieldProbeInvalidValues},
+		{name: "in without values", path: ".spec", op: FieldOperatorIn, err: ErrFieldProbeInvalidValues},
+		{name: "invalid regex", path: ".spec", op: FieldOperatorMatches, values: []string{"("}, err: ErrFieldProbeInvalidValues},
+		{name: "invalid number", path: ".spec", op: FieldOperatorGreaterOrEqual, values: []string{"x"}, err: ErrFieldProbeInvalidValues},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := NewFieldProbe(test.path, test.op, test.values...)
+			require.ErrorIs(t, err, test.err)
+		})
+	}
+}
+
+func Test_FieldProbe_Literal(t *testing.T) {
+	t.Parallel()
+
+	obj := &unstructured.Unstructured{
+		Object: map[string]any{
+			"spec": map[string]any{"image": "quay.io/test/app:v1.2.3"},
+		},
+	}
+
+	tests := []struct {
+		name   string
+		probe  *FieldProbe
+		status Status
+	}{
+		{
+			name:   "valid",
+			probe:  &FieldProbe{Path: ".spec.image", Operator: FieldOperatorMatches, Values: []string{`^quay\.io/`}},
+			status: StatusTrue,
+		},
+		{
+			name:   "unanchored",
+			probe:  &FieldProbe{Path: ".spec.image", Operator: FieldOperatorMatches, Values: []string{`test/app`}},
+			status: StatusTrue,
+		},
+		{
+			name:   "anchored",
+			probe:  &FieldProbe{Path: ".spec.image", Operator: FieldOperatorMatches, Values: []string{`^test/app`}},
+			status: StatusFalse,
+		},
+		{
+			name:   "unknown operator",
+			probe:  &FieldProbe{Path: ".spec.image", Operator: "!=", Values: []string{"x"}},
+			status: StatusUnknown,
+		},
+		{
+			name:   "invalid path",
+			probe:  &FieldProbe{Path: ".spec[", Operator: FieldOperatorExists},
+			status: StatusUnknown,
+		},
+		{
+			name:   "invalid values",
+			probe:  &FieldProbe{Path: ".spec.image", Operator: FieldOperatorMatches},
+			status: StatusUnknown,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			r := test.probe.Probe(obj)
+			assert.Equal(t, test.status, r.Status, r.Messages)
+
+			// Compiled once on first use.
+			compiled := test.probe.compiled
+			test.probe.Probe(obj)
+			assert.Same(t, compiled, test.probe.compiled)
+		})
+	}
+}
+
+func Test_FieldProbe_Concurrent(t *testing.T) {
+	t.Parallel()
+
+	p, err := NewFieldProbe(`.status.conditions[?(@.type=="Ready")].status`, FieldOperatorEqual, "True")
+	require.NoError(t, err)
+
+	obj := &unstructured.Unstructured{
+		Object: map[string]any{
+			"status": map[string]any{
+				"conditions": []any{
+					map[string]any{"type": "Ready", "status": "True"},
+				},
+			},
+		},
+	}
+
+	var wg sync.WaitGroup
+
+	for range 10 {
+		wg.Go(func() {
+			assert.Equal(t, StatusTrue, p.Probe(obj).Status)
+		})
+	}
+
+	wg.Wait()
+}
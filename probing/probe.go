@@ -80,6 +80,83 @@ func (p And) Probe(obj client.Object) Result {
 	}
 }
 
+// Or combines multiple Prober.
+// The returned status is:
+// - True if at least one ProbeResult is True
+// - Unknown if none are True and at least one ProbeResult is Unknown
+// - False if all ProbeResults are False
+// Messages of the same Status will be combined.
+type Or []Prober
+
+var _ Prober = (Or)(nil)
+
+// Probe runs probes against the given object and returns the result.
+func (p Or) Probe(obj client.Object) Result {
+	var unknownMsgs, trueMsgs, falseMsgs []string
+
+	var statusUnknown, statusTrue bool
+
+	for _, probe := range p {
+		r := probe.Probe(obj)
+		switch r.Status {
+		case StatusTrue:
+			statusTrue = true
+
+			trueMsgs = append(trueMsgs, r.Messages...)
+		case StatusFalse:
+			falseMsgs = append(falseMsgs, r.Messages...)
+		case StatusUnknown:
+			statusUnknown = true
+
+			unknownMsgs = append(unknownMsgs, r.Messages...)
+		}
+	}
+
+	if statusTrue {
+		return Result{
+			Status:   StatusTrue,
+			Messages: trueMsgs,
+		}
+	}
+
+	if statusUnknown {
+		return Result{
+			Status:   StatusUnknown,
+			Messages: unknownMsgs,
+		}
+	}
+
+	return Result{
+		Status:   StatusFalse,
+		Messages: falseMsgs,
+	}
+}
+
+// Not inverts the result of a Prober.
+// True and False are swapped, Unknown is returned unchanged.
+type Not struct {
+	Prober Prober
+}
+
+var _ Prober = (*Not)(nil)
+
+// Probe runs the probe against the given object and returns the inverted result.
+func (p *Not) Probe(obj client.Object) Result {
+	r := p.Prober.Probe(obj)
+	switch r.Status {
+	case StatusTrue:
+		if len(r.Messages) == 0 {
+			return FalseResult("negated probe succeeded")
+		}
+
+		return FalseResult(r.Messages...)
+	case StatusFalse:
+		return TrueResult(r.Messages...)
+	default:
+		return r
+	}
+}
+
 // TrueResult is a helper returning a True ProbeResult with the given messages.
 func TrueResult(msgs ...string) Result {
 	return Result{Status: StatusTrue, Messages: msgs}
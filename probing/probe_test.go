@@ -64,6 +64,80 @@ func TestAnd(t *testing.T) {
 	})
 }
 
+func TestOr(t *testing.T) {
+	t.Parallel()
+
+	results := map[Status]Result{
+		StatusTrue:    TrueResult("true"),
+		StatusFalse:   FalseResult("false"),
+		StatusUnknown: UnknownResult("unknown"),
+	}
+
+	tests := []struct {
+		name     string
+		statuses []Status
+		expected Result
+	}{
+		{
+			name:     "one true",
+			statuses: []Status{StatusFalse, StatusTrue, StatusUnknown},
+			expected: TrueResult("true"),
+		},
+		{
+			name:     "unknown",
+			statuses: []Status{StatusFalse, StatusUnknown},
+			expected: UnknownResult("unknown"),
+		},
+		{
+			name:     "all false",
+			statuses: []Status{StatusFalse, StatusFalse},
+			expected: FalseResult("false", "false"),
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			var o Or
+
+			for _, s := range test.statuses {
+				p := &proberMock{}
+				p.On("Probe", mock.Anything).Return(results[s])
+				o = append(o, p)
+			}
+
+			assert.Equal(t, test.expected, o.Probe(&unstructured.Unstructured{}))
+		})
+	}
+}
+
+func TestNot(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		result   Result
+		expected Result
+	}{
+		{name: "true", result: TrueResult(), expected: FalseResult("negated probe succeeded")},
+		{name: "true with message", result: TrueResult("ready"), expected: FalseResult("ready")},
+		{name: "false", result: FalseResult("not ready"), expected: TrueResult("not ready")},
+		{name: "unknown", result: UnknownResult("?"), expected: UnknownResult("?")},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			p := &proberMock{}
+			p.On("Probe", mock.Anything).Return(test.result)
+
+			assert.Equal(t, test.expected, (&Not{Prober: p}).Probe(&unstructured.Unstructured{}))
+		})
+	}
+}
+
 func TestResultHelpers(t *testing.T) {
 	t.Parallel()
 
@@ -0,0 +1,451 @@
+package probing
+
+import (
+	"errors"
+	"fmt"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+// Spec is a serializable description of a Prober,
+// intended to be embedded into API types.
+// All probes are combined using And and are only executed
+// for objects matching the optional selector.
+type Spec struct {
+	// Selector limits the objects the probes are executed against.
+	// Objects not matching the selector pass the probe.
+	// +optional
+	Selector *SelectorSpec `json:"selector,omitempty"`
+	// ObservedGeneration ensures that .status.observedGeneration
+	// equals .metadata.generation before running the probes.
+	// +optional
+	ObservedGeneration bool `json:"observedGeneration,omitempty"`
+	// Probes to execute, all of them have to succeed.
+	// +kubebuilder:validation:MinItems=1
+	Probes []ProbeSpec `json:"probes"`
+}
+
+// SelectorSpec selects objects by GroupKind and labels.
+// When both are set, objects have to match both.
+type SelectorSpec struct {
+	// Kind selects objects by API group and kind.
+	// +optional
+	Kind *KindSelectorSpec `json:"kind,omitempty"`
+	// Labels selects objects by their labels.
+	// +optional
+	Labels *metav1.LabelSelector `json:"labels,omitempty"`
+}
+
+// KindSelectorSpec selects objects by API group and kind.
+type KindSelectorSpec struct {
+	// Group of the object, empty for the core API group.
+	// +optional
+	Group string `json:"group,omitempty"`
+	// Kind of the object.
+	// +kubebuilder:validation:MinLength=1
+	Kind string `json:"kind"`
+}
+
+// MaxProbeSpecNesting is the number of levels allOf, anyOf and not can be nested.
+// Structural schemas of CustomResourceDefinitions can't be recursive,
+// so SpecOpenAPISchema spells out this many levels.
+const MaxProbeSpecNesting = 3
+
+// ProbeSpec describes a single probe.
+// Exactly one of the fields has to be set.
+// allOf, anyOf and not combine nested probes up to MaxProbeSpecNesting levels deep.
+// +kubebuilder:validation:MinProperties=1
+// +kubebuilder:validation:MaxProperties=1
+type ProbeSpec struct {
+	// Condition checks the status of a condition in .status.conditions.
+	// +optional
+	Condition *ConditionSpec `json:"condition,omitempty"`
+	// FieldsEqual checks that two fields have the same value.
+	// +optional
+	FieldsEqual *FieldsEqualSpec `json:"fieldsEqual,omitempty"`
+	// FieldValue checks that a field has the given value.
+	// +optional
+	FieldValue *FieldValueSpec `json:"fieldValue,omitempty"`
+	// Field evaluates values found under a JSONPath using an operator.
+	// +optional
+	Field *FieldSpec `json:"field,omitempty"`
+	// CEL evaluates a CEL expression against the object.
+	// +optional
+	CEL *CELSpec `json:"cel,omitempty"`
+	// AllOf succeeds when all nested probes succeed.
+	// +kubebuilder:validation:MinItems=1
+	// +optional
+	AllOf []ProbeSpec `json:"allOf,omitempty"`
+	// AnyOf succeeds when at least one of the nested probes succeeds.
+	// +kubebuilder:validation:MinItems=1
+	// +optional
+	AnyOf []ProbeSpec `json:"anyOf,omitempty"`
+	// Not succeeds when the nested probe fails.
+	// +optional
+	Not *ProbeSpec `json:"not,omitempty"`
+}
+
+// ConditionSpec is the serializable form of ConditionProbe.
+type ConditionSpec struct {
+	// Type of the condition to check.
+	// +kubebuilder:validation:MinLength=1
+	Type string `json:"type"`
+	// Status the condition is expected to have.
+	// +kubebuilder:validation:MinLength=1
+	Status string `json:"status"`
+}
+
+// FieldsEqualSpec is the serializable form of FieldsEqualProbe.
+type FieldsEqualSpec struct {
+	// FieldA is a dot-separated path to the first field.
+	// +kubebuilder:validation:MinLength=1
+	FieldA string `json:"fieldA"`
+	// FieldB is a dot-separated path to the second field.
+	// +kubebuilder:validation:MinLength=1
+	FieldB string `json:"fieldB"`
+}
+
+// FieldValueSpec is the serializable form of FieldValueProbe.
+type FieldValueSpec struct {
+	// FieldPath is a dot-separated path to the field.
+	// +kubebuilder:validation:MinLength=1
+	FieldPath string `json:"fieldPath"`
+	// Value the field is expected to have.
+	Value string `json:"value"`
+}
+
+// FieldSpec is the serializable form of FieldProbe.
+type FieldSpec struct {
+	// Path is a JSONPath expression, e.g. .status.conditions[?(@.type=="Ready")].status.
+	// +kubebuilder:validation:MinLength=1
+	Path string `json:"path"`
+	// Operator to evaluate the values found under path with.
+	// +kubebuilder:validation:Enum="==";">=";"<=";in;matches;exists;notExists
+	Operator FieldOperator `json:"operator"`
+	// Values to compare against, depending on the operator.
+	// matches takes an unanchored regular expression, use ^ and $ to match whole values.
+	// +optional
+	Values []string `json:"values,omitempty"`
+}
+
+// CELSpec is the serializable form of CELProbe.
+type CELSpec struct {
+	// Rule is a CEL expression evaluating to a bool.
+	// The object is available as "self".
+	// +kubebuilder:validation:MinLength=1
+	Rule string `json:"rule"`
+	// Message is reported when the rule evaluates to false.
+	// Required when no messageExpression is given.
+	// +optional
+	Message string `json:"message,omitempty"`
+	// MessageExpression is a CEL expression evaluating to a string,
+	// reported instead of message when the rule evaluates to false.
+	// +optional
+	MessageExpression string `json:"messageExpression,omitempty"`
+}
+
+// Compile validates the given Spec and turns it into a Prober.
+// Validation errors are reported with the field path of the offending field.
+// The given CELOptions are passed on to all CEL probes.
+func Compile(spec Spec, opts ...CELOption) (Prober, error) {
+	p, errs := compileSpec(nil, spec, opts)
+	if len(errs) > 0 {
+		return nil, errs.ToAggregate()
+	}
+
+	return p, nil
+}
+
+// CompileAll validates the given Specs and combines them into a single Prober using And.
+// Validation errors are reported with the index and field path of the offending field.
+// The given CELOptions are passed on to all CEL probes.
+func CompileAll(specs []Spec, opts ...CELOption) (Prober, error) {
+	var (
+		errs field.ErrorList
+		root *field.Path
+	)
+
+	probers := make(And, 0, len(specs))
+
+	for i, spec := range specs {
+		p, serrs := compileSpec(root.Index(i), spec, opts)
+		errs = append(errs, serrs...)
+
+		probers = append(probers, p)
+	}
+
+	if len(errs) > 0 {
+		return nil, errs.ToAggregate()
+	}
+
+	return probers, nil
+}
+
+func compileSpec(fldPath *field.Path, spec Spec, opts []CELOption) (Prober, field.ErrorList) {
+	var errs field.ErrorList
+
+	probesPath := fldPath.Child("probes")
+	if len(spec.Probes) == 0 {
+		errs = append(errs, field.Required(probesPath, "at least one probe must be specified"))
+	}
+
+	probers := make(And, 0, len(spec.Probes))
+
+	for i, ps := range spec.Probes {
+		p, perrs := compileProbeSpec(probesPath.Index(i), ps, opts, 0)
+		errs = append(errs, perrs...)
+
+		probers = append(probers, p)
+	}
+
+	var prober Prober = probers
+	if spec.ObservedGeneration {
+		prober = &ObservedGenerationProbe{Prober: prober}
+	}
+
+	if spec.Selector != nil {
+		var serrs field.ErrorList
+
+		prober, serrs = compileSelectorSpec(fldPath.Child("selector"), *spec.Selector, prober)
+		errs = append(errs, serrs...)
+	}
+
+	if len(errs) > 0 {
+		return nil, errs
+	}
+
+	return prober, nil
+}
+
+func compileSelectorSpec(fldPath *field.Path, spec SelectorSpec, prober Prober) (Prober, field.ErrorList) {
+	var errs field.ErrorList
+
+	if spec.Kind == nil && spec.Labels == nil {
+		errs = append(errs, field.Required(fldPath, "kind or labels must be specified"))
+	}
+
+	if spec.Labels != nil {
+		sel, err := metav1.LabelSelectorAsSelector(spec.Labels)
+		if err != nil {
+			errs = append(errs, field.Invalid(fldPath.Child("labels"), spec.Labels, err.Error()))
+		} else {
+			prober = &LabelSelector{Prober: prober, Selector: sel}
+		}
+	}
+
+	if spec.Kind != nil {
+		if len(spec.Kind.Kind) == 0 {
+			errs = append(errs, field.Required(fldPath.Child("kind", "kind"), "must not be empty"))
+		}
+
+		prober = &GroupKindSelector{
+			Prober:    prober,
+			GroupKind: schema.GroupKind{Group: spec.Kind.Group, Kind: spec.Kind.Kind},
+		}
+	}
+
+	return prober, errs
+}
+
+func compileProbeSpec(
+	fldPath *field.Path, spec ProbeSpec, opts []CELOption, depth int,
+) (Prober, field.ErrorList) {
+	var (
+		set    []string
+		prober Prober
+		errs   field.ErrorList
+	)
+
+	if spec.Condition != nil {
+		set = append(set, "condition")
+		prober, errs = compileConditionSpec(fldPath.Child("condition"), *spec.Condition)
+	}
+
+	if spec.FieldsEqual != nil {
+		set = append(set, "fieldsEqual")
+		prober, errs = compileFieldsEqualSpec(fldPath.Child("fieldsEqual"), *spec.FieldsEqual)
+	}
+
+	if spec.FieldValue != nil {
+		set = append(set, "fieldValue")
+		prober, errs = compileFieldValueSpec(fldPath.Child("fieldValue"), *spec.FieldValue)
+	}
+
+	if spec.Field != nil {
+		set = append(set, "field")
+		prober, errs = compileFieldSpec(fldPath.Child("field"), *spec.Field)
+	}
+
+	if spec.CEL != nil {
+		set = append(set, "cel")
+		prober, errs = compileCELSpec(fldPath.Child("cel"), *spec.CEL, opts)
+	}
+
+	if spec.AllOf != nil {
+		set = append(set, "allOf")
+		prober, errs = compileCombinatorSpec(fldPath.Child("allOf"), spec.AllOf, opts, depth,
+			func(probers []Prober) Prober { return And(probers) })
+	}
+
+	if spec.AnyOf != nil {
+		set = append(set, "anyOf")
+		prober, errs = compileCombinatorSpec(fldPath.Child("anyOf"), spec.AnyOf, opts, depth,
+			func(probers []Prober) Prober { return Or(probers) })
+	}
+
+	if spec.Not != nil {
+		set = append(set, "not")
+		prober, errs = compileNotSpec(fldPath.Child("not"), *spec.Not, opts, depth)
+	}
+
+	switch len(set) {
+	case 0:
+		return nil, field.ErrorList{field.Required(fldPath, "exactly one probe type must be specified")}
+	case 1:
+		return prober, errs
+	default:
+		return nil, field.ErrorList{field.Forbidden(
+			fldPath, fmt.Sprintf("exactly one probe type must be specified, got %v", set))}
+	}
+}
+
+// compileCombinatorSpec compiles nested probes at the given depth and combines them.
+func compileCombinatorSpec(
+	fldPath *field.Path, specs []ProbeSpec, opts []CELOption, depth int,
+	combine func([]Prober) Prober,
+) (Prober, field.ErrorList) {
+	if depth >= MaxProbeSpecNesting {
+		return nil, field.ErrorList{nestingForbidden(fldPath)}
+	}
+
+	if len(specs) == 0 {
+		return nil, field.ErrorList{field.Required(fldPath, "at least one probe must be specified")}
+	}
+
+	var errs field.ErrorList
+
+	probers := make([]Prober, 0, len(specs))
+
+	for i, spec := range specs {
+		p, perrs := compileProbeSpec(fldPath.Index(i), spec, opts, depth+1)
+		errs = append(errs, perrs...)
+
+		probers = append(probers, p)
+	}
+
+	if len(errs) > 0 {
+		return nil, errs
+	}
+
+	return combine(probers), nil
+}
+
+func compileNotSpec(fldPath *field.Path, spec ProbeSpec, opts []CELOption, depth int) (Prober, field.ErrorList) {
+	if depth >= MaxProbeSpecNesting {
+		return nil, field.ErrorList{nestingForbidden(fldPath)}
+	}
+
+	p, errs := compileProbeSpec(fldPath, spec, opts, depth+1)
+	if len(errs) > 0 {
+		return nil, errs
+	}
+
+	return &Not{Prober: p}, nil
+}
+
+func nestingForbidden(fldPath *field.Path) *field.Error {
+	return field.Forbidden(fldPath, fmt.Sprintf("probes can't be nested more than %d levels deep", MaxProbeSpecNesting))
+}
+
+func compileConditionSpec(fldPath *field.Path, spec ConditionSpec) (Prober, field.ErrorList) {
+	var errs field.ErrorList
+	if len(spec.Type) == 0 {
+		errs = append(errs, field.Required(fldPath.Child("type"), "must not be empty"))
+	}
+
+	if len(spec.Status) == 0 {
+		errs = append(errs, field.Required(fldPath.Child("status"), "must not be empty"))
+	}
+
+	return &ConditionProbe{Type: spec.Type, Status: spec.Status}, errs
+}
+
+func compileFieldsEqualSpec(fldPath *field.Path, spec FieldsEqualSpec) (Prober, field.ErrorList) {
+	var errs field.ErrorList
+	if len(spec.FieldA) == 0 {
+		errs = append(errs, field.Required(fldPath.Child("fieldA"), "must not be empty"))
+	}
+
+	if len(spec.FieldB) == 0 {
+		errs = append(errs, field.Required(fldPath.Child("fieldB"), "must not be empty"))
+	}
+
+	return &FieldsEqualProbe{FieldA: spec.FieldA, FieldB: spec.FieldB}, errs
+}
+
+func compileFieldValueSpec(fldPath *field.Path, spec FieldValueSpec) (Prober, field.ErrorList) {
+	var errs field.ErrorList
+	if len(spec.FieldPath) == 0 {
+		errs = append(errs, field.Required(fldPath.Child("fieldPath"), "must not be empty"))
+	}
+
+	return &FieldValueProbe{FieldPath: spec.FieldPath, Value: spec.Value}, errs
+}
+
+func compileFieldSpec(fldPath *field.Path, spec FieldSpec) (Prober, field.ErrorList) {
+	if len(spec.Path) == 0 {
+		return nil, field.ErrorList{field.Required(fldPath.Child("path"), "must not be empty")}
+	}
+
+	p, err := NewFieldProbe(spec.Path, spec.Operator, spec.Values...)
+
+	switch {
+	case errors.Is(err, ErrFieldProbeUnknownOperator):
+		return nil, field.ErrorList{field.NotSupported(fldPath.Child("operator"), spec.Operator, []FieldOperator{
+			FieldOperatorEqual, FieldOperatorGreaterOrEqual, FieldOperatorLessOrEqual,
+			FieldOperatorIn, FieldOperatorMatches, FieldOperatorExists, FieldOperatorNotExists,
+		})}
+	case errors.Is(err, ErrFieldProbeInvalidValues):
+		return nil, field.ErrorList{field.Invalid(fldPath.Child("values"), spec.Values, err.Error())}
+	case err != nil:
+		return nil, field.ErrorList{field.Invalid(fldPath.Child("path"), spec.Path, err.Error())}
+	}
+
+	return p, nil
+}
+
+func compileCELSpec(fldPath *field.Path, spec CELSpec, opts []CELOption) (Prober, field.ErrorList) {
+	var errs field.ErrorList
+	if len(spec.Message) == 0 && len(spec.MessageExpression) == 0 {
+		errs = append(errs, field.Required(fldPath.Child("message"), "message or messageExpression must be specified"))
+	}
+
+	if len(spec.Rule) == 0 {
+		return nil, append(errs, field.Required(fldPath.Child("rule"), "must not be empty"))
+	}
+
+	if len(errs) > 0 {
+		return nil, errs
+	}
+
+	p, err := NewCELProbe(spec.Rule, spec.Message, opts...)
+	if errors.Is(err, ErrCELNilOwner) {
+		return nil, field.ErrorList{field.InternalError(fldPath, err)}
+	}
+
+	if err != nil {
+		return nil, field.ErrorList{field.Invalid(fldPath.Child("rule"), spec.Rule, err.Error())}
+	}
+
+	if len(spec.MessageExpression) > 0 {
+		p.MessageProgram, err = NewCELMessageProgram(spec.MessageExpression, opts...)
+		if err != nil {
+			return nil, field.ErrorList{field.Invalid(
+				fldPath.Child("messageExpression"), spec.MessageExpression, err.Error())}
+		}
+	}
+
+	return p, nil
+}
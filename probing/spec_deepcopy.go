@@ -0,0 +1,127 @@
+package probing
+
+// DeepCopyInto copies the receiver into out.
+func (in *Spec) DeepCopyInto(out *Spec) {
+	*out = *in
+	if in.Selector != nil {
+		out.Selector = in.Selector.DeepCopy()
+	}
+
+	if in.Probes != nil {
+		out.Probes = make([]ProbeSpec, len(in.Probes))
+		for i := range in.Probes {
+			in.Probes[i].DeepCopyInto(&out.Probes[i])
+		}
+	}
+}
+
+// DeepCopy returns a deep copy of the receiver.
+func (in *Spec) DeepCopy() *Spec {
+	if in == nil {
+		return nil
+	}
+
+	out := new(Spec)
+	in.DeepCopyInto(out)
+
+	return out
+}
+
+// DeepCopyInto copies the receiver into out.
+func (in *SelectorSpec) DeepCopyInto(out *SelectorSpec) {
+	*out = *in
+	if in.Kind != nil {
+		out.Kind = new(*in.Kind)
+	}
+
+	if in.Labels != nil {
+		out.Labels = in.Labels.DeepCopy()
+	}
+}
+
+// DeepCopy returns a deep copy of the receiver.
+func (in *SelectorSpec) DeepCopy() *SelectorSpec {
+	if in == nil {
+		return nil
+	}
+
+	out := new(SelectorSpec)
+	in.DeepCopyInto(out)
+
+	return out
+}
+
+// DeepCopyInto copies the receiver into out.
+func (in *ProbeSpec) DeepCopyInto(out *ProbeSpec) {
+	*out = *in
+	if in.Condition != nil {
+		out.Condition = new(*in.Condition)
+	}
+
+	if in.FieldsEqual != nil {
+		out.FieldsEqual = new(*in.FieldsEqual)
+	}
+
+	if in.FieldValue != nil {
+		out.FieldValue = new(*in.FieldValue)
+	}
+
+	if in.Field != nil {
+		out.Field = in.Field.DeepCopy()
+	}
+
+	if in.CEL != nil {
+		out.CEL = new(*in.CEL)
+	}
+
+	if in.AllOf != nil {
+		out.AllOf = make([]ProbeSpec, len(in.AllOf))
+		for i := range in.AllOf {
+			in.AllOf[i].DeepCopyInto(&out.AllOf[i])
+		}
+	}
+
+	if in.AnyOf != nil {
+		out.AnyOf = make([]ProbeSpec, len(in.AnyOf))
+		for i := range in.AnyOf {
+			in.AnyOf[i].DeepCopyInto(&out.AnyOf[i])
+		}
+	}
+
+	if in.Not != nil {
+		out.Not = in.Not.DeepCopy()
+	}
+}
+
+// DeepCopy returns a deep copy of the receiver.
+func (in *ProbeSpec) DeepCopy() *ProbeSpec {
+	if in == nil {
+		return nil
+	}
+
+	out := new(ProbeSpec)
+	in.DeepCopyInto(out)
+
+	return out
+}
+
+// DeepCopyInto copies the receiver into out.
+func (in *FieldSpec) DeepCopyInto(out *FieldSpec) {
+	*out = *in
+	if in.Values != nil {
+		out.Values = make([]string, len(in.Values))
+		copy(out.Values, in.Values)
+	}
+}
+
+// DeepCopy returns a deep copy of the receiver.
+func (in *FieldSpec) DeepCopy() *FieldSpec {
+	if in == nil {
+		return nil
+	}
+
+	out := new(FieldSpec)
+	in.DeepCopyInto(out)
+
+	return out
+}
@@ -0,0 +1,192 @@
+package probing
+
+import (
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+)
+
+// SpecOpenAPISchema returns the structural OpenAPI v3 schema of Spec,
+// ready to be embedded into a CustomResourceDefinition.
+// The schema follows the JSON tags and kubebuilder markers of the Spec types,
+// which is verified by tests. Structural schemas can't be recursive, so nested
+// probes are spelled out up to MaxProbeSpecNesting levels. Prefer this schema over
+// generating one with controller-gen, which can't express the recursive ProbeSpec.
+func SpecOpenAPISchema() apiextensionsv1.JSONSchemaProps {
+	return apiextensionsv1.JSONSchemaProps{
+		Description: "Probe specification. All probes are combined using And " +
+			"and are only executed for objects matching the optional selector.",
+		Type:     "object",
+		Required: []string{"probes"},
+		Properties: map[string]apiextensionsv1.JSONSchemaProps{
+			"selector": selectorSpecOpenAPISchema(),
+			"observedGeneration": {
+				Description: "Ensures that .status.observedGeneration equals " +
+					".metadata.generation before running the probes.",
+				Type: "boolean",
+			},
+			"probes": {
+				Description: "Probes to execute, all of them have to succeed.",
+				Type:        "array",
+				MinItems:    new(int64(1)),
+				Items: &apiextensionsv1.JSONSchemaPropsOrArray{
+					Schema: new(probeSpecOpenAPISchema(0)),
+				},
+			},
+		},
+	}
+}
+
+func selectorSpecOpenAPISchema() apiextensionsv1.JSONSchemaProps {
+	return apiextensionsv1.JSONSchemaProps{
+		Description: "Limits the objects the probes are executed against.",
+		Type:        "object",
+		Properties: map[string]apiextensionsv1.JSONSchemaProps{
+			"kind": {
+				Description: "Selects objects by API group and kind.",
+				Type:        "object",
+				Required:    []string{"kind"},
+				Properties: map[string]apiextensionsv1.JSONSchemaProps{
+					"group": {Type: "string"},
+					"kind":  {Type: "string", MinLength: new(int64(1))},
+				},
+			},
+			"labels": labelSelectorOpenAPISchema(),
+		},
+	}
+}
+
+func labelSelectorOpenAPISchema() apiextensionsv1.JSONSchemaProps {
+	return apiextensionsv1.JSONSchemaProps{
+		Description: "Selects objects by their labels.",
+		Type:        "object",
+		XMapType:    new("atomic"),
+		Properties: map[string]apiextensionsv1.JSONSchemaProps{
+			"matchLabels": {
+				Type: "object",
+				AdditionalProperties: &apiextensionsv1.JSONSchemaPropsOrBool{
+					Allows: true,
+					Schema: &apiextensionsv1.JSONSchemaProps{Type: "string"},
+				},
+			},
+			"matchExpressions": {
+				Type:      "array",
+				XListType: new("atomic"),
+				Items: &apiextensionsv1.JSONSchemaPropsOrArray{
+					Schema: &apiextensionsv1.JSONSchemaProps{
+						Type:     "object",
+						Required: []string{"key", "operator"},
+						Properties: map[string]apiextensionsv1.JSONSchemaProps{
+							"key":      {Type: "string"},
+							"operator": {Type: "string"},
+							"values": {
+								Type:      "array",
+								XListType: new("atomic"),
+								Items: &apiextensionsv1.JSONSchemaPropsOrArray{
+									Schema: &apiextensionsv1.JSONSchemaProps{Type: "string"},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// probeSpecOpenAPISchema returns the schema of a ProbeSpec nested depth levels deep.
+// Combinators are left out at MaxProbeSpecNesting, as structural schemas can't be recursive.
+func probeSpecOpenAPISchema(depth int) apiextensionsv1.JSONSchemaProps {
+	nonEmptyString := apiextensionsv1.JSONSchemaProps{Type: "string", MinLength: new(int64(1))}
+
+	s := apiextensionsv1.JSONSchemaProps{
+		Description:   "A single probe, exactly one of the fields has to be set.",
+		Type:          "object",
+		MinProperties: new(int64(1)),
+		MaxProperties: new(int64(1)),
+		Properties: map[string]apiextensionsv1.JSONSchemaProps{
+			"condition": {
+				Description: "Checks the status of a condition in .status.conditions.",
+				Type:        "object",
+				Required:    []string{"type", "status"},
+				Properties: map[string]apiextensionsv1.JSONSchemaProps{
+					"type":   nonEmptyString,
+					"status": nonEmptyString,
+				},
+			},
+			"fieldsEqual": {
+				Description: "Checks that two fields have the same value.",
+				Type:        "object",
+				Required:    []string{"fieldA", "fieldB"},
+				Properties: map[string]apiextensionsv1.JSONSchemaProps{
+					"fieldA": nonEmptyString,
+					"fieldB": nonEmptyString,
+				},
+			},
+			"fieldValue": {
+				Description: "Checks that a field has the given value.",
+				Type:        "object",
+				Required:    []string{"fieldPath", "value"},
+				Properties: map[string]apiextensionsv1.JSONSchemaProps{
+					"fieldPath": nonEmptyString,
+					"value":     {Type: "string"},
+				},
+			},
+			"field": {
+				Description: "Evaluates values found under a JSONPath using an operator.",
+				Type:        "object",
+				Required:    []string{"path", "operator"},
+				Properties: map[string]apiextensionsv1.JSONSchemaProps{
+					"path": nonEmptyString,
+					"operator": {
+						Type: "string",
+						Enum: []apiextensionsv1.JSON{
+							{Raw: []byte(`"=="`)}, {Raw: []byte(`">="`)}, {Raw: []byte(`"<="`)},
+							{Raw: []byte(`"in"`)}, {Raw: []byte(`"matches"`)},
+							{Raw: []byte(`"exists"`)}, {Raw: []byte(`"notExists"`)},
+						},
+					},
+					"values": {
+						Type:      "array",
+						XListType: new("atomic"),
+						Items: &apiextensionsv1.JSONSchemaPropsOrArray{
+							Schema: &apiextensionsv1.JSONSchemaProps{Type: "string"},
+						},
+					},
+				},
+			},
+			"cel": {
+				Description: "Evaluates a CEL expression against the object.",
+				Type:        "object",
+				Required:    []string{"rule"},
+				Properties: map[string]apiextensionsv1.JSONSchemaProps{
+					"rule":              nonEmptyString,
+					"message":           {Type: "string"},
+					"messageExpression": {Type: "string"},
+				},
+			},
+		},
+	}
+
+	if depth >= MaxProbeSpecNesting {
+		return s
+	}
+
+	nested := probeSpecOpenAPISchema(depth + 1)
+	s.Properties["allOf"] = apiextensionsv1.JSONSchemaProps{
+		Description: "Succeeds when all nested probes succeed.",
+		Type:        "array",
+		MinItems:    new(int64(1)),
+		Items:       &apiextensionsv1.JSONSchemaPropsOrArray{Schema: &nested},
+	}
+	s.Properties["anyOf"] = apiextensionsv1.JSONSchemaProps{
+		Description: "Succeeds when at least one of the nested probes succeeds.",
+		Type:        "array",
+		MinItems:    new(int64(1)),
+		Items:       &apiextensionsv1.JSONSchemaPropsOrArray{Schema: &nested},
+	}
+
+	not := nested
+	not.Description = "Succeeds when the nested probe fails."
+	s.Properties["not"] = not
+
+	return s
+}
@@ -0,0 +1,229 @@
+package probing
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+)
+
+// TestSpecOpenAPISchema_Markers ensures the hand-written schema matches
+// the JSON tags and kubebuilder markers of the Spec types,
+// so embedding Spec into controller-gen managed APIs yields the same validation.
+func TestSpecOpenAPISchema_Markers(t *testing.T) {
+	t.Parallel()
+
+	f, err := parser.ParseFile(token.NewFileSet(), "spec.go", nil, parser.ParseComments)
+	require.NoError(t, err)
+
+	types := map[string]*ast.TypeSpec{}
+	docs := map[string]*ast.CommentGroup{}
+
+	for _, decl := range f.Decls {
+		gd, ok := decl.(*ast.GenDecl)
+		if !ok || gd.Tok != token.TYPE {
+			continue
+		}
+
+		for _, spec := range gd.Specs {
+			ts := spec.(*ast.TypeSpec)
+			types[ts.Name.Name] = ts
+			docs[ts.Name.Name] = gd.Doc
+		}
+	}
+
+	c := &markerChecker{types: types, docs: docs}
+	c.checkStruct(t, "Spec", "Spec", SpecOpenAPISchema(), 0)
+}
+
+type markerChecker struct {
+	types map[string]*ast.TypeSpec
+	docs  map[string]*ast.CommentGroup
+}
+
+func (c *markerChecker) checkStruct(
+	t *testing.T, path, typeName string, schema apiextensionsv1.JSONSchemaProps, depth int,
+) {
+	t.Helper()
+
+	st, ok := c.types[typeName].Type.(*ast.StructType)
+	require.True(t, ok, "%s: %s is not a struct", path, typeName)
+
+	assert.Equal(t, "object", schema.Type, path)
+	c.checkMarkers(t, path, markers(c.docs[typeName]), schema)
+
+	var (
+		required   []string
+		properties []string
+	)
+
+	for _, fld := range st.Fields.List {
+		tag := reflect.StructTag(strings.Trim(fld.Tag.Value, "`")).Get("json")
+		name, opts, _ := strings.Cut(tag, ",")
+		fieldPath := path + "." + name
+		fieldMarkers := markers(fld.Doc)
+
+		_, optional := fieldMarkers["optional"]
+		delete(fieldMarkers, "optional")
+
+		if !optional && !strings.Contains(opts, "omitempty") {
+			required = append(required, name)
+		}
+
+		// Nested ProbeSpecs end at MaxProbeSpecNesting.
+		if typeName == "ProbeSpec" && elemTypeName(fld.Type) == "ProbeSpec" && depth >= MaxProbeSpecNesting {
+			assert.NotContains(t, schema.Properties, name, fieldPath)
+
+			continue
+		}
+
+		properties = append(properties, name)
+
+		fieldSchema, ok := schema.Properties[name]
+		if !assert.True(t, ok, "%s: missing in schema", fieldPath) {
+			continue
+		}
+
+		c.checkMarkers(t, fieldPath, fieldMarkers, fieldSchema)
+		c.checkType(t, fieldPath, typeName, fld.Type, fieldSchema, depth)
+	}
+
+	assert.ElementsMatch(t, required, schema.Required, "%s: required", path)
+
+	schemaProperties := make([]string, 0, len(schema.Properties))
+	for name := range schema.Properties {
+		schemaProperties = append(schemaProperties, name)
+	}
+
+	assert.ElementsMatch(t, properties, schemaProperties, "%s: properties", path)
+}
+
+func (c *markerChecker) checkType(
+	t *testing.T, path, parentType string, expr ast.Expr,
+	schema apiextensionsv1.JSONSchemaProps, depth int,
+) {
+	t.Helper()
+
+	switch e := expr.(type) {
+	case *ast.StarExpr:
+		c.checkType(t, path, parentType, e.X, schema, depth)
+
+	case *ast.ArrayType:
+		assert.Equal(t, "array", schema.Type, path)
+
+		if assert.NotNil(t, schema.Items, path) {
+			c.checkType(t, path+"[]", parentType, e.Elt, *schema.Items.Schema, depth)
+		}
+
+	case *ast.SelectorExpr:
+		// Types of other packages, e.g. metav1.LabelSelector.
+		assert.Equal(t, "object", schema.Type, path)
+
+	case *ast.Ident:
+		switch e.Name {
+		case "string":
+			assert.Equal(t, "string", schema.Type, path)
+		case "bool":
+			assert.Equal(t, "boolean", schema.Type, path)
+		default:
+			if _, ok := c.types[e.Name]; ok {
+				if e.Name == "ProbeSpec" && parentType == "ProbeSpec" {
+					depth++
+				}
+
+				c.checkStruct(t, path, e.Name, schema, depth)
+
+				return
+			}
+
+			// Named types of this package, e.g. FieldOperator.
+			assert.Equal(t, "string", schema.Type, path)
+		}
+
+	default:
+		t.Errorf("%s: unsupported type %T", path, expr)
+	}
+}
+
+func (c *markerChecker) checkMarkers(
+	t *testing.T, path string, m map[string]string, schema apiextensionsv1.JSONSchemaProps,
+) {
+	t.Helper()
+
+	for name, value := range m {
+		switch name {
+		case "validation:MinLength":
+			assert.Equal(t, parseInt64(t, value), schema.MinLength, path)
+		case "validation:MinItems":
+			assert.Equal(t, parseInt64(t, value), schema.MinItems, path)
+		case "validation:MinProperties":
+			assert.Equal(t, parseInt64(t, value), schema.MinProperties, path)
+		case "validation:MaxProperties":
+			assert.Equal(t, parseInt64(t, value), schema.MaxProperties, path)
+		case "validation:Enum":
+			var enum []apiextensionsv1.JSON
+			for v := range strings.SplitSeq(value, ";") {
+				if !strings.HasPrefix(v, `"`) {
+					v = strconv.Quote(v)
+				}
+
+				enum = append(enum, apiextensionsv1.JSON{Raw: []byte(v)})
+			}
+
+			assert.Equal(t, enum, schema.Enum, path)
+		default:
+			t.Errorf("%s: marker %q not checked", path, name)
+		}
+	}
+}
+
+// markers returns kubebuilder markers and +optional of the given comment.
+func markers(doc *ast.CommentGroup) map[string]string {
+	m := map[string]string{}
+	if doc == nil {
+		return m
+	}
+
+	for _, c := range doc.List {
+		text := strings.TrimSpace(strings.TrimPrefix(c.Text, "//"))
+
+		switch {
+		case text == "+optional":
+			m["optional"] = ""
+		case strings.HasPrefix(text, "+kubebuilder:"):
+			name, value, _ := strings.Cut(strings.TrimPrefix(text, "+kubebuilder:"), "=")
+			m[name] = value
+		}
+	}
+
+	return m
+}
+
+func elemTypeName(expr ast.Expr) string {
+	switch e := expr.(type) {
+	case *ast.StarExpr:
+		return elemTypeName(e.X)
+	case *ast.ArrayType:
+		return elemTypeName(e.Elt)
+	case *ast.Ident:
+		return e.Name
+	default:
+		return ""
+	}
+}
+
+func parseInt64(t *testing.T, s string) *int64 {
+	t.Helper()
+
+	i, err := strconv.ParseInt(s, 10, 64)
+	require.NoError(t, err)
+
+	return &i
+}
@@ -0,0 +1,389 @@
+package probing
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apiextensions-apiserver/pkg/apis/apiextensions"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	structuralschema "k8s.io/apiextensions-apiserver/pkg/apiserver/schema"
+	apiextensionsvalidation "k8s.io/apiextensions-apiserver/pkg/apiserver/validation"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"sigs.k8s.io/yaml"
+)
+
+const testSpecYAML = `
+selector:
+  kind:
+    group: apps
+    kind: Deployment
+  labels:
+    matchLabels:
+      app: test
+observedGeneration: true
+probes:
+- condition:
+    type: Available
+    status: "True"
+- fieldsEqual:
+    fieldA: .status.updatedReplicas
+    fieldB: .status.replicas
+- cel:
+    rule: self.status.replicas > 0
+    message: no replicas
+- field:
+    path: .status.conditions[?(@.type=="Available")].status
+    operator: in
+    values: ["True"]
+`
+
+func TestCompile(t *testing.T) {
+	t.Parallel()
+
+	var spec Spec
+	require.NoError(t, yaml.Unmarshal([]byte(testSpecYAML), &spec))
+
+	p, err := Compile(spec)
+	require.NoError(t, err)
+
+	deploy := func(labels map[string]any, status map[string]any) *unstructured.Unstructured {
+		return &unstructured.Unstructured{Object: map[string]any{
+			"apiVersion": "apps/v1",
+			"kind":       "Deployment",
+			"metadata": map[string]any{
+				"generation": int64(2),
+				"labels":     labels,
+			},
+			"status": status,
+		}}
+	}
+
+	t.Run("success", func(t *testing.T) {
+		t.Parallel()
+
+		r := p.Probe(deploy(map[string]any{"app": "test"}, map[string]any{
+			"observedGeneration": int64(2),
+			"replicas":           int64(1),
+			"updatedReplicas":    int64(1),
+			"conditions": []any{
+				map[string]any{"type": "Available", "status": "True"},
+			},
+		}))
+		assert.Equal(t, StatusTrue, r.Status)
+	})
+
+	t.Run("failure", func(t *testing.T) {
+		t.Parallel()
+
+		r := p.Probe(deploy(map[string]any{"app": "test"}, map[string]any{
+			"observedGeneration": int64(2),
+			"replicas":           int64(0),
+			"updatedReplicas":    int64(0),
+			"conditions": []any{
+				map[string]any{"type": "Available", "status": "True"},
+			},
+		}))
+		assert.Equal(t, StatusFalse, r.Status)
+		assert.Equal(t, []string{"no replicas"}, r.Messages)
+	})
+
+	t.Run("outdated", func(t *testing.T) {
+		t.Parallel()
+
+		r := p.Probe(deploy(map[string]any{"app": "test"}, map[string]any{
+			"observedGeneration": int64(1),
+		}))
+		assert.Equal(t, StatusUnknown, r.Status)
+	})
+
+	t.Run("not selected", func(t *testing.T) {
+		t.Parallel()
+
+		r := p.Probe(deploy(map[string]any{"app": "other"}, map[string]any{}))
+		assert.Equal(t, StatusTrue, r.Status)
+	})
+}
+
+func TestCompile_Errors(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		spec   Spec
+		fields []string
+	}{
+		{
+			name:   "no probes",
+			spec:   Spec{},
+			fields: []string{"probes"},
+		},
+		{
+			name:   "empty probe",
+			spec:   Spec{Probes: []ProbeSpec{{}}},
+			fields: []string{"probes[0]"},
+		},
+		{
+			name: "multiple probe types",
+			spec: Spec{Probes: []ProbeSpec{{
+				Condition:  &ConditionSpec{Type: "Available", Status: "True"},
+				FieldValue: &FieldValueSpec{FieldPath: "spec.x", Value: "y"},
+			}}},
+			fields: []string{"probes[0]"},
+		},
+		{
+			name: "invalid fields",
+			spec: Spec{
+				Selector: &SelectorSpec{Kind: &KindSelectorSpec{}},
+				Probes: []ProbeSpec{
+					{Condition: &ConditionSpec{}},
+					{CEL: &CELSpec{Rule: "self.metadata.name", Message: "x"}},
+				},
+			},
+			fields: []string{
+				"probes[0].condition.type",
+				"probes[0].condition.status",
+				"probes[1].cel.rule",
+				"selector.kind.kind",
+			},
+		},
+		{
+			name: "invalid field probes",
+			spec: Spec{Probes: []ProbeSpec{
+				{Field: &FieldSpec{Path: ".spec", Operator: "!="}},
+				{Field: &FieldSpec{Path: ".spec[", Operator: FieldOperatorExists}},
+				{Field: &FieldSpec{Path: ".spec", Operator: FieldOperatorIn}},
+			}},
+			fields: []string{
+				"probes[0].field.operator",
+				"probes[1].field.path",
+				"probes[2].field.values",
+			},
+		},
+		{
+			name: "invalid combinators",
+			spec: Spec{Probes: []ProbeSpec{
+				{AnyOf: []ProbeSpec{}},
+				{Not: &ProbeSpec{AllOf: []ProbeSpec{{Condition: &ConditionSpec{}}}}},
+				{Not: &ProbeSpec{Not: &ProbeSpec{Not: &ProbeSpec{Not: &ProbeSpec{
+					Condition: &ConditionSpec{Type: "A", Status: "B"},
+				}}}}},
+			}},
+			fields: []string{
+				"probes[0].anyOf",
+				"probes[1].not.allOf[0].condition.type",
+				"probes[1].not.allOf[0].condition.status",
+				"probes[2].not.not.not.not",
+			},
+		},
+		{
+			name:   "empty selector",
+			spec:   Spec{Selector: &SelectorSpec{}, Probes: []ProbeSpec{{Condition: &ConditionSpec{Type: "A", Status: "B"}}}},
+			fields: []string{"selector"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := Compile(test.spec)
+			require.Error(t, err)
+
+			fields := fieldsFromErr(t, err)
+			assert.Equal(t, test.fields, fields)
+		})
+	}
+}
+
+const testCombinatorSpecYAML = `
+probes:
+- anyOf:
+  - condition:
+      type: Available
+      status: "True"
+  - allOf:
+    - fieldValue:
+        fieldPath: .spec.strategy
+        value: Recreate
+    - not:
+        condition:
+          type: Progressing
+          status: "True"
+`
+
+func TestCompile_Combinators(t *testing.T) {
+	t.Parallel()
+
+	var spec Spec
+	require.NoError(t, yaml.Unmarshal([]byte(testCombinatorSpecYAML), &spec))
+
+	p, err := Compile(spec)
+	require.NoError(t, err)
+
+	obj := func(strategy, available, progressing string) *unstructured.Unstructured {
+		return &unstructured.Unstructured{Object: map[string]any{
+			"spec": map[string]any{"strategy": strategy},
+			"status": map[string]any{"conditions": []any{
+				map[string]any{"type": "Available", "status": available},
+				map[string]any{"type": "Progressing", "status": progressing},
+			}},
+		}}
+	}
+
+	tests := []struct {
+		name   string
+		obj    *unstructured.Unstructured
+		status Status
+	}{
+		{name: "available", obj: obj("RollingUpdate", "True", "True"), status: StatusTrue},
+		{name: "recreate", obj: obj("Recreate", "False", "False"), status: StatusTrue},
+		{name: "recreate progressing", obj: obj("Recreate", "False", "True"), status: StatusFalse},
+		{name: "unavailable", obj: obj("RollingUpdate", "False", "False"), status: StatusFalse},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, test.status, p.Probe(test.obj).Status)
+		})
+	}
+}
+
+func TestCompile_CEL(t *testing.T) {
+	t.Parallel()
+
+	p, err := Compile(Spec{Probes: []ProbeSpec{{CEL: &CELSpec{
+		Rule:              `self.status.ready == phase.name`,
+		MessageExpression: `"expected " + phase.name`,
+	}}}}, WithCELPhase("deploy"))
+	require.NoError(t, err)
+
+	r := p.Probe(&unstructured.Unstructured{Object: map[string]any{
+		"status": map[string]any{"ready": "setup"},
+	}})
+	assert.Equal(t, StatusFalse, r.Status)
+	assert.Equal(t, []string{"expected deploy"}, r.Messages)
+
+	_, err = Compile(Spec{Probes: []ProbeSpec{{CEL: &CELSpec{
+		Rule:              `true`,
+		MessageExpression: `1`,
+	}}}})
+	require.Error(t, err)
+	assert.Equal(t, []string{"probes[0].cel.messageExpression"}, fieldsFromErr(t, err))
+
+	_, err = Compile(Spec{Probes: []ProbeSpec{{CEL: &CELSpec{Rule: `true`}}}})
+	require.Error(t, err)
+	assert.Equal(t, []string{"probes[0].cel.message"}, fieldsFromErr(t, err))
+}
+
+func TestCompileAll(t *testing.T) {
+	t.Parallel()
+
+	_, err := CompileAll([]Spec{
+		{Probes: []ProbeSpec{{Condition: &ConditionSpec{Type: "A", Status: "True"}}}},
+		{Probes: []ProbeSpec{{FieldValue: &FieldValueSpec{}}}},
+	})
+	require.Error(t, err)
+	assert.Equal(t, []string{"[1].probes[0].fieldValue.fieldPath"}, fieldsFromErr(t, err))
+
+	p, err := CompileAll([]Spec{
+		{Probes: []ProbeSpec{{Condition: &ConditionSpec{Type: "A", Status: "True"}}}},
+		{Probes: []ProbeSpec{{FieldValue: &FieldValueSpec{FieldPath: "spec.x", Value: "y"}}}},
+	})
+	require.NoError(t, err)
+	assert.Len(t, p, 2)
+}
+
+func TestSpec_DeepCopy(t *testing.T) {
+	t.Parallel()
+
+	var spec Spec
+	require.NoError(t, yaml.Unmarshal([]byte(testSpecYAML), &spec))
+
+	c := spec.DeepCopy()
+	assert.Equal(t, &spec, c)
+
+	c.Selector.Kind.Kind = "StatefulSet"
+	c.Probes[0].Condition.Type = "Ready"
+	assert.Equal(t, "Deployment", spec.Selector.Kind.Kind)
+	assert.Equal(t, "Available", spec.Probes[0].Condition.Type)
+}
+
+func TestSpecOpenAPISchema(t *testing.T) {
+	t.Parallel()
+
+	v1Schema := SpecOpenAPISchema()
+
+	var internalSchema apiextensions.JSONSchemaProps
+	require.NoError(t, apiextensionsv1.Convert_v1_JSONSchemaProps_To_apiextensions_JSONSchemaProps(
+		&v1Schema, &internalSchema, nil))
+
+	ss, err := structuralschema.NewStructural(&internalSchema)
+	require.NoError(t, err)
+	require.Empty(t, structuralschema.ValidateStructural(nil, ss))
+
+	validator, _, err := apiextensionsvalidation.NewSchemaValidator(&internalSchema)
+	require.NoError(t, err)
+
+	t.Run("valid", func(t *testing.T) {
+		t.Parallel()
+
+		var obj map[string]any
+		require.NoError(t, yaml.Unmarshal([]byte(testSpecYAML), &obj))
+
+		errs := apiextensionsvalidation.ValidateCustomResource(nil, obj, validator)
+		assert.Empty(t, errs)
+	})
+
+	t.Run("valid combinators", func(t *testing.T) {
+		t.Parallel()
+
+		var obj map[string]any
+		require.NoError(t, yaml.Unmarshal([]byte(testCombinatorSpecYAML), &obj))
+
+		errs := apiextensionsvalidation.ValidateCustomResource(nil, obj, validator)
+		assert.Empty(t, errs)
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		t.Parallel()
+
+		obj := map[string]any{
+			"probes": []any{
+				map[string]any{
+					"cel":       map[string]any{"rule": "true", "message": "x"},
+					"condition": map[string]any{"type": "A", "status": "True"},
+				},
+			},
+		}
+
+		errs := apiextensionsvalidation.ValidateCustomResource(nil, obj, validator)
+		assert.NotEmpty(t, errs)
+	})
+}
+
+func fieldsFromErr(t *testing.T, err error) []string {
+	t.Helper()
+
+	var fields []string
+
+	for _, e := range flattenErrs(err) {
+		var ferr *field.Error
+		require.ErrorAs(t, e, &ferr)
+
+		fields = append(fields, ferr.Field)
+	}
+
+	return fields
+}
+
+func flattenErrs(err error) []error {
+	if agg, ok := err.(interface{ Errors() []error }); ok {
+		return agg.Errors()
+	}
+
+	return []error{err}
+}
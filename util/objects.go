@@ -0,0 +1,26 @@
+package util
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"pkg.package-operator.run/boxcutter/machinery"
+)
+
+// RevisionResultObjects returns the objects of all phases of a RevisionResult,
+// as last seen on the cluster after creation/update.
+// Use it to check ownerhandling.OwnerStrategyMigrating progress across a revision.
+func RevisionResultObjects(result machinery.RevisionResult) []metav1.Object {
+	if result == nil {
+		return nil
+	}
+
+	var objs []metav1.Object
+
+	for _, phase := range result.GetPhases() {
+		for _, obj := range phase.GetObjects() {
+			objs = append(objs, obj.Object())
+		}
+	}
+
+	return objs
+}
@@ -0,0 +1,34 @@
+package util
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"pkg.package-operator.run/boxcutter/machinery"
+)
+
+func TestRevisionResultObjects(t *testing.T) {
+	t.Parallel()
+
+	cm := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "cm"}}
+	secret := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "secret"}}
+
+	result := mockRevisionResult{
+		phases: []machinery.PhaseResult{
+			mockPhaseResult{
+				name:    "phase1",
+				objects: []machinery.ObjectResult{mockObjectResult{object: cm}},
+			},
+			mockPhaseResult{
+				name:    "phase2",
+				objects: []machinery.ObjectResult{mockObjectResult{object: secret}},
+			},
+		},
+	}
+
+	assert.Equal(t, []metav1.Object{cm, secret}, RevisionResultObjects(result))
+	assert.Nil(t, RevisionResultObjects(nil))
+}
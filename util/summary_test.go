@@ -63,6 +63,10 @@ func (m mockPhaseResult) GetValidationError() *validation.PhaseValidationError {
 	return m.validationError
 }
 
+func (m mockPhaseResult) GetWarnings() []validation.Warning {
+	return nil
+}
+
 func (m mockPhaseResult) GetObjects() []machinery.ObjectResult {
 	return m.objects
 }
@@ -98,6 +102,10 @@ func (m mockObjectResult) ProbeResults() machinerytypes.ProbeResultContainer {
 	return m.probeResults
 }
 
+func (m mockObjectResult) ProbeStates() machinerytypes.ProbeStateContainer {
+	return nil
+}
+
 func (m mockObjectResult) IsComplete() bool {
 	return m.complete
 }
@@ -177,6 +185,10 @@ func (m mockPhaseTeardownResult) Waiting() []machinerytypes.ObjectRef {
 	return nil
 }
 
+func (m mockPhaseTeardownResult) HandedOver() []machinerytypes.ObjectRef {
+	return nil
+}
+
 func (m mockPhaseTeardownResult) String() string {
 	return "verbose phase teardown report..."
 }
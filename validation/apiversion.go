@@ -0,0 +1,246 @@
+package validation
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	apimachineryerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/utils/clock"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	bctypes "pkg.package-operator.run/boxcutter/machinery/types"
+)
+
+type apiDiscoveryClient interface {
+	ServerGroups() (*metav1.APIGroupList, error)
+	ServerResourcesForGroupVersion(groupVersion string) (*metav1.APIResourceList, error)
+}
+
+// APIVersionValidator uses discovery to check that objects use
+// API versions served by the cluster, before a dry run is attempted.
+// Objects in versions that are not served are reported as APIVersionNotServedError.
+// Objects in served versions other than the preferred version serving their kind
+// are reported as Warning, naming the preferred version.
+//
+// Discovery carries no deprecation markers for builtin APIs, so the warning does not
+// claim deprecation. Install a DryRunWarningHandler to capture the deprecation
+// warnings returned by the kube-apiserver for dry runs.
+//
+// Discovery is queried for every object,
+// use a cached client e.g. from k8s.io/client-go/discovery/cached/memory.
+// Cached clients are invalidated when a version is not found,
+// to pick up APIs registered since the cache was filled,
+// but at most once per DefaultDiscoveryInvalidationInterval.
+type APIVersionValidator struct {
+	discovery apiDiscoveryClient
+	clock     clock.PassiveClock
+
+	invalidatedLock sync.Mutex
+	invalidatedAt   time.Time
+}
+
+// DefaultDiscoveryInvalidationInterval is the minimum interval
+// between invalidations of the cached discovery client.
+const DefaultDiscoveryInvalidationInterval = time.Minute
+
+// NewAPIVersionValidator returns a new APIVersionValidator instance.
+func NewAPIVersionValidator(discovery apiDiscoveryClient) *APIVersionValidator {
+	return &APIVersionValidator{
+		discovery: discovery,
+		clock:     clock.RealClock{},
+	}
+}
+
+// WithAPIVersionValidation checks API versions of all objects via discovery,
+// before a dry run is attempted.
+func WithAPIVersionValidation(apiVersionValidator *APIVersionValidator) PhaseValidatorOption {
+	return phaseValidatorOptionFn(func(v *PhaseValidator) {
+		v.apiVersionValidator = apiVersionValidator
+	})
+}
+
+// APIVersionNotServedError is returned when the API version
+// of an object is not served by the cluster.
+type APIVersionNotServedError struct {
+	GroupVersionKind schema.GroupVersionKind
+	// PreferredVersion of the same group serving the kind.
+	// Empty if the kind is not served in any version.
+	PreferredVersion string
+}
+
+// Error implements the error interface.
+func (e APIVersionNotServedError) Error() string {
+	msg := fmt.Sprintf("%s %s is not served by the cluster",
+		e.GroupVersionKind.GroupVersion().String(), e.GroupVersionKind.Kind)
+	if len(e.PreferredVersion) == 0 {
+		return msg
+	}
+
+	return msg + fmt.Sprintf(", use %s", schema.GroupVersion{
+		Group: e.GroupVersionKind.Group, Version: e.PreferredVersion,
+	}.String())
+}
+
+// Validate checks the API version of the given object.
+// Warnings are recorded into the WarningRecorder of the context.
+// The function returns nil, if the API version is served.
+// It returns an ObjectValidationError when it was successfully able to validate the Object.
+// It returns a different error when unable to validate the object.
+func (v *APIVersionValidator) Validate(ctx context.Context, obj client.Object) error {
+	errs, err := v.validate(ctx, obj)
+	if err != nil {
+		return err
+	}
+
+	return NewObjectValidationError(bctypes.ToObjectRef(obj), errs...)
+}
+
+func (v *APIVersionValidator) validate(ctx context.Context, obj client.Object) ([]error, error) {
+	gvk := obj.GetObjectKind().GroupVersionKind()
+	if len(gvk.Kind) == 0 || len(gvk.Version) == 0 {
+		// Reported by validateObjectMetadata.
+		return nil, nil
+	}
+
+	group, served, err := v.lookup(gvk)
+	if err != nil {
+		return nil, err
+	}
+
+	if !served && v.invalidate() {
+		group, served, err = v.lookup(gvk)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if served {
+		preferred, err := v.preferredVersion(group, gvk.Kind)
+		if err != nil {
+			return nil, err
+		}
+
+		if len(preferred) > 0 && preferred != gvk.Version {
+			RecordWarning(ctx, Warning{
+				ObjectRef: bctypes.ToObjectRef(obj),
+				Message: fmt.Sprintf("%s %s is not the preferred version, use %s",
+					gvk.GroupVersion().String(), gvk.Kind,
+					schema.GroupVersion{Group: gvk.Group, Version: preferred}.String()),
+			})
+		}
+
+		return nil, nil
+	}
+
+	notServed := APIVersionNotServedError{GroupVersionKind: gvk}
+	if group != nil {
+		notServed.PreferredVersion, err = v.preferredVersion(group, gvk.Kind)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return []error{notServed}, nil
+}
+
+// invalidate invalidates cached discovery clients,
+// unless already invalidated within DefaultDiscoveryInvalidationInterval.
+// Returns true if the client was invalidated.
+func (v *APIVersionValidator) invalidate() bool {
+	c, ok := v.discovery.(interface{ Invalidate() })
+	if !ok {
+		return false
+	}
+
+	v.invalidatedLock.Lock()
+	defer v.invalidatedLock.Unlock()
+
+	now := v.clock.Now()
+	if !v.invalidatedAt.IsZero() && now.Sub(v.invalidatedAt) < DefaultDiscoveryInvalidationInterval {
+		return false
+	}
+
+	v.invalidatedAt = now
+	c.Invalidate()
+
+	return true
+}
+
+// lookup returns the APIGroup of the given GVK, if it exists,
+// and whether the kind is served in the given version.
+func (v *APIVersionValidator) lookup(gvk schema.GroupVersionKind) (*metav1.APIGroup, bool, error) {
+	groups, err := v.discovery.ServerGroups()
+	if err != nil {
+		return nil, false, fmt.Errorf("discovering API groups: %w", err)
+	}
+
+	var group *metav1.APIGroup
+
+	for i := range groups.Groups {
+		if groups.Groups[i].Name == gvk.Group {
+			group = &groups.Groups[i]
+
+			break
+		}
+	}
+
+	if group == nil {
+		return nil, false, nil
+	}
+
+	served, err := v.servesKind(gvk.GroupVersion(), gvk.Kind)
+	if err != nil {
+		return nil, false, err
+	}
+
+	return group, served, nil
+}
+
+// preferredVersion returns the preferred version of the group serving the given kind.
+// Falls back to the highest priority version serving the kind, if not served by the preferred version.
+func (v *APIVersionValidator) preferredVersion(group *metav1.APIGroup, kind string) (string, error) {
+	// Versions are ordered by priority.
+	versions := make([]string, 0, len(group.Versions)+1)
+	versions = append(versions, group.PreferredVersion.Version)
+
+	for _, gv := range group.Versions {
+		versions = append(versions, gv.Version)
+	}
+
+	for _, ver := range versions {
+		served, err := v.servesKind(schema.GroupVersion{Group: group.Name, Version: ver}, kind)
+		if err != nil {
+			return "", err
+		}
+
+		if served {
+			return ver, nil
+		}
+	}
+
+	return "", nil
+}
+
+func (v *APIVersionValidator) servesKind(gv schema.GroupVersion, kind string) (bool, error) {
+	resources, err := v.discovery.ServerResourcesForGroupVersion(gv.String())
+	if apimachineryerrors.IsNotFound(err) {
+		return false, nil
+	}
+
+	if err != nil {
+		return false, fmt.Errorf("discovering resources of %s: %w", gv.String(), err)
+	}
+
+	for _, r := range resources.APIResources {
+		if r.Kind == kind && !strings.Contains(r.Name, "/") {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
@@ -0,0 +1,246 @@
+package validation
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	apimachineryerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	clocktesting "k8s.io/utils/clock/testing"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"pkg.package-operator.run/boxcutter/machinery/types"
+)
+
+type testDiscovery struct {
+	groups      []metav1.APIGroup
+	resources   map[string][]metav1.APIResource
+	invalidated int
+}
+
+func (d *testDiscovery) ServerGroups() (*metav1.APIGroupList, error) {
+	return &metav1.APIGroupList{Groups: d.groups}, nil
+}
+
+func (d *testDiscovery) ServerResourcesForGroupVersion(gv string) (*metav1.APIResourceList, error) {
+	resources, ok := d.resources[gv]
+	if !ok {
+		return nil, apimachineryerrors.NewNotFound(schema.GroupResource{}, gv)
+	}
+
+	return &metav1.APIResourceList{GroupVersion: gv, APIResources: resources}, nil
+}
+
+func (d *testDiscovery) Invalidate() {
+	d.invalidated++
+}
+
+func newTestAPIDiscovery() *testDiscovery {
+	return &testDiscovery{
+		groups: []metav1.APIGroup{{
+			Name: "autoscaling",
+			Versions: []metav1.GroupVersionForDiscovery{
+				{GroupVersion: "autoscaling/v2", Version: "v2"},
+				{GroupVersion: "autoscaling/v1", Version: "v1"},
+			},
+			PreferredVersion: metav1.GroupVersionForDiscovery{GroupVersion: "autoscaling/v2", Version: "v2"},
+		}},
+		resources: map[string][]metav1.APIResource{
+			"autoscaling/v2": {
+				{Name: "horizontalpodautoscalers", Kind: "HorizontalPodAutoscaler"},
+				{Name: "horizontalpodautoscalers/status", Kind: "HorizontalPodAutoscaler"},
+			},
+			"autoscaling/v1": {
+				{Name: "horizontalpodautoscalers", Kind: "HorizontalPodAutoscaler"},
+			},
+		},
+	}
+}
+
+func newTestObject(apiVersion, kind string) *unstructured.Unstructured {
+	obj := &unstructured.Unstructured{}
+	obj.SetAPIVersion(apiVersion)
+	obj.SetKind(kind)
+	obj.SetName("test")
+	obj.SetNamespace("test")
+
+	return obj
+}
+
+func TestAPIVersionValidator(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name             string
+		obj              client.Object
+		expectedErrors   []error
+		expectedWarnings []string
+	}{
+		{
+			name: "preferred version",
+			obj:  newTestObject("autoscaling/v2", "HorizontalPodAutoscaler"),
+		},
+		{
+			name:             "served version",
+			obj:              newTestObject("autoscaling/v1", "HorizontalPodAutoscaler"),
+			expectedWarnings: []string{"autoscaling/v1 HorizontalPodAutoscaler is not the preferred version, use autoscaling/v2"},
+		},
+		{
+			name: "removed version",
+			obj:  newTestObject("autoscaling/v2beta2", "HorizontalPodAutoscaler"),
+			expectedErrors: []error{APIVersionNotServedError{
+				GroupVersionKind: schema.GroupVersionKind{
+					Group: "autoscaling", Version: "v2beta2", Kind: "HorizontalPodAutoscaler",
+				},
+				PreferredVersion: "v2",
+			}},
+		},
+		{
+			name: "unknown group",
+			obj:  newTestObject("example.com/v1", "Example"),
+			expectedErrors: []error{APIVersionNotServedError{
+				GroupVersionKind: schema.GroupVersionKind{
+					Group: "example.com", Version: "v1", Kind: "Example",
+				},
+			}},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			discovery := newTestAPIDiscovery()
+			v := NewAPIVersionValidator(discovery)
+
+			recorder := &WarningRecorder{}
+			errs, err := v.validate(ContextWithWarningRecorder(t.Context(), recorder), test.obj)
+			require.NoError(t, err)
+			assert.Equal(t, test.expectedErrors, errs)
+
+			var warnings []string
+			for _, w := range recorder.Warnings() {
+				warnings = append(warnings, w.Message)
+			}
+
+			assert.Equal(t, test.expectedWarnings, warnings)
+
+			if len(test.expectedErrors) > 0 {
+				assert.Equal(t, 1, discovery.invalidated)
+			}
+		})
+	}
+}
+
+func TestAPIVersionValidator_InvalidationInterval(t *testing.T) {
+	t.Parallel()
+
+	clk := clocktesting.NewFakePassiveClock(time.Now())
+	discovery := newTestAPIDiscovery()
+	v := NewAPIVersionValidator(discovery)
+	v.clock = clk
+
+	removed := newTestObject("autoscaling/v2beta2", "HorizontalPodAutoscaler")
+
+	for range 3 {
+		errs, err := v.validate(t.Context(), removed)
+		require.NoError(t, err)
+		require.Len(t, errs, 1)
+	}
+
+	assert.Equal(t, 1, discovery.invalidated)
+
+	clk.SetTime(clk.Now().Add(DefaultDiscoveryInvalidationInterval))
+
+	_, err := v.validate(t.Context(), removed)
+	require.NoError(t, err)
+	assert.Equal(t, 2, discovery.invalidated)
+}
+
+func TestPhaseValidator_APIVersion(t *testing.T) {
+	t.Parallel()
+
+	const deprecation = "batch/v1beta1 CronJob is deprecated in v1.21+, unavailable in v1.25+; use batch/v1 CronJob"
+
+	removed := newTestObject("autoscaling/v2beta2", "HorizontalPodAutoscaler")
+	deprecated := newTestObject("autoscaling/v1", "HorizontalPodAutoscaler")
+	deprecated.SetName("deprecated")
+
+	ov := &mockObjectValidator{}
+	ov.
+		On("Validate", mock.Anything, deprecated, mock.Anything).
+		Run(func(args mock.Arguments) {
+			// Deprecation warning header of the dry run.
+			DryRunWarningHandler{}.HandleWarningHeaderWithContext(
+				contextWithObjectWarningSink(args.Get(0).(context.Context), types.ToObjectRef(deprecated)),
+				299, "-", deprecation)
+		}).
+		Return(nil)
+
+	v := newPhaseValidator(ov, WithAPIVersionValidation(NewAPIVersionValidator(newTestAPIDiscovery())))
+
+	parent := &WarningRecorder{}
+	err := v.Validate(ContextWithWarningRecorder(t.Context(), parent),
+		types.NewPhase("phase1", []client.Object{removed, deprecated}))
+
+	var perr *PhaseValidationError
+	require.ErrorAs(t, err, &perr)
+	require.Len(t, perr.Objects, 1)
+	assert.Equal(t, types.ToObjectRef(removed), perr.Objects[0].ObjectRef)
+
+	expectedWarnings := []Warning{
+		{
+			ObjectRef: types.ToObjectRef(deprecated),
+			Message:   "autoscaling/v1 HorizontalPodAutoscaler is not the preferred version, use autoscaling/v2",
+		},
+		{
+			ObjectRef: types.ToObjectRef(deprecated),
+			Message:   deprecation,
+		},
+	}
+	assert.Equal(t, expectedWarnings, perr.Warnings)
+	assert.Equal(t, expectedWarnings, parent.Warnings())
+
+	// Dry run is skipped for objects with unserved API versions.
+	ov.AssertNumberOfCalls(t, "Validate", 1)
+
+	b, err := perr.MarshalJSON()
+	require.NoError(t, err)
+	assert.Contains(t, string(b), `"warnings":[{"object":{"apiVersion":"autoscaling/v1"`)
+	assert.Contains(t, string(b), `"code":"APIVersionNotServed"`)
+}
+
+type testWarningHandler struct {
+	texts []string
+}
+
+func (h *testWarningHandler) HandleWarningHeaderWithContext(_ context.Context, _ int, _ string, text string) {
+	h.texts = append(h.texts, text)
+}
+
+func TestDryRunWarningHandler(t *testing.T) {
+	t.Parallel()
+
+	next := &testWarningHandler{}
+	h := DryRunWarningHandler{Next: next}
+
+	obj := newTestObject("v1", "ConfigMap")
+	recorder := &WarningRecorder{}
+	ctx := ContextWithWarningRecorder(t.Context(), recorder)
+
+	h.HandleWarningHeaderWithContext(
+		contextWithObjectWarningSink(ctx, types.ToObjectRef(obj)), 299, "-", "dry run warning")
+	h.HandleWarningHeaderWithContext(ctx, 299, "-", "other warning")
+
+	assert.Equal(t, []Warning{{
+		ObjectRef: types.ToObjectRef(obj),
+		Message:   "dry run warning",
+	}}, recorder.Warnings())
+	assert.Equal(t, []string{"other warning"}, next.texts)
+}
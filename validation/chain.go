@@ -0,0 +1,180 @@
+package validation
+
+import (
+	"context"
+	"errors"
+	"slices"
+
+	"pkg.package-operator.run/boxcutter/machinery/types"
+)
+
+// PhaseCheck validates a phase before it is reconciled.
+// Implemented by PhaseValidator.
+//
+// Validate returns a PhaseValidationError when it was successfully able to validate the Phase.
+// It returns a different error when unable to validate the Phase.
+type PhaseCheck interface {
+	Validate(ctx context.Context, phase types.Phase, opts ...types.PhaseReconcileOption) error
+}
+
+// PhaseCheckFunc wraps the given function to work with the PhaseCheck interface.
+type PhaseCheckFunc func(ctx context.Context, phase types.Phase, opts ...types.PhaseReconcileOption) error
+
+// Validate implements PhaseCheck.
+func (fn PhaseCheckFunc) Validate(
+	ctx context.Context, phase types.Phase, opts ...types.PhaseReconcileOption,
+) error {
+	return fn(ctx, phase, opts...)
+}
+
+// RevisionCheck validates a revision before any of its phases are reconciled.
+// Implemented by RevisionValidator and CollisionValidator.
+//
+// Validate returns a RevisionValidationError when it was successfully able to validate the Revision.
+// It returns a different error when unable to validate the Revision.
+type RevisionCheck interface {
+	Validate(ctx context.Context, rev types.Revision) error
+}
+
+// RevisionCheckFunc wraps the given function to work with the RevisionCheck interface.
+type RevisionCheckFunc func(ctx context.Context, rev types.Revision) error
+
+// Validate implements RevisionCheck.
+func (fn RevisionCheckFunc) Validate(ctx context.Context, rev types.Revision) error {
+	return fn(ctx, rev)
+}
+
+// ChainPhaseChecks returns a PhaseCheck running all given checks in order.
+// Validation errors of all checks are merged into a single PhaseValidationError.
+// Stops on the first error that is not a PhaseValidationError.
+func ChainPhaseChecks(checks ...PhaseCheck) PhaseCheck {
+	return phaseCheckChain(checks)
+}
+
+type phaseCheckChain []PhaseCheck
+
+func (c phaseCheckChain) Validate(
+	ctx context.Context, phase types.Phase, opts ...types.PhaseReconcileOption,
+) error {
+	var merged *PhaseValidationError
+
+	for _, check := range c {
+		err := check.Validate(ctx, phase, opts...)
+		if err == nil {
+			continue
+		}
+
+		var perr *PhaseValidationError
+		if !errors.As(err, &perr) {
+			return err
+		}
+
+		if merged == nil {
+			merged = &PhaseValidationError{PhaseName: perr.PhaseName}
+		}
+
+		merged.merge(*perr)
+	}
+
+	if merged == nil {
+		return nil
+	}
+
+	return merged
+}
+
+// ChainRevisionChecks returns a RevisionCheck running all given checks in order.
+// Validation errors of all checks are merged into a single RevisionValidationError.
+// Stops on the first error that is not a RevisionValidationError.
+func ChainRevisionChecks(checks ...RevisionCheck) RevisionCheck {
+	return revisionCheckChain(checks)
+}
+
+type revisionCheckChain []RevisionCheck
+
+func (c revisionCheckChain) Validate(ctx context.Context, rev types.Revision) error {
+	var merged *RevisionValidationError
+
+	for _, check := range c {
+		err := check.Validate(ctx, rev)
+		if err == nil {
+			continue
+		}
+
+		var rerr *RevisionValidationError
+		if !errors.As(err, &rerr) {
+			return err
+		}
+
+		if merged == nil {
+			merged = &RevisionValidationError{
+				RevisionName:   rerr.RevisionName,
+				RevisionNumber: rerr.RevisionNumber,
+			}
+		}
+
+		merged.merge(*rerr)
+	}
+
+	if merged == nil {
+		return nil
+	}
+
+	return merged
+}
+
+// merge adds all errors and warnings of other, merging errors of the same object.
+func (e *PhaseValidationError) merge(other PhaseValidationError) {
+	e.PhaseError = joinErrors(e.PhaseError, other.PhaseError)
+
+	for _, oErr := range other.Objects {
+		i := slices.IndexFunc(e.Objects, func(o ObjectValidationError) bool {
+			return o.ObjectRef == oErr.ObjectRef
+		})
+		if i == -1 {
+			e.Objects = append(e.Objects, oErr)
+
+			continue
+		}
+
+		e.Objects[i].Errors = append(e.Objects[i].Errors, oErr.Errors...)
+	}
+
+	e.Warnings = append(e.Warnings, other.Warnings...)
+}
+
+// merge adds all errors of other, merging errors of the same phase.
+func (e *RevisionValidationError) merge(other RevisionValidationError) {
+	e.RevisionError = joinErrors(e.RevisionError, other.RevisionError)
+
+	for _, pErr := range other.Phases {
+		e.Phases = mergePhaseValidationError(e.Phases, pErr)
+	}
+}
+
+// mergePhaseValidationError adds the given PhaseValidationError to the
+// PhaseValidationError of the same phase or appends it.
+func mergePhaseValidationError(pvs []PhaseValidationError, pv PhaseValidationError) []PhaseValidationError {
+	i := slices.IndexFunc(pvs, func(p PhaseValidationError) bool {
+		return p.PhaseName == pv.PhaseName
+	})
+	if i == -1 {
+		return append(pvs, pv)
+	}
+
+	pvs[i].merge(pv)
+
+	return pvs
+}
+
+// joinErrors joins a and b, without wrapping if one of them is nil.
+func joinErrors(a, b error) error {
+	switch {
+	case a == nil:
+		return b
+	case b == nil:
+		return a
+	}
+
+	return errors.Join(a, b)
+}
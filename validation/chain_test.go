@@ -0,0 +1,127 @@
+package validation
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"pkg.package-operator.run/boxcutter/machinery/types"
+)
+
+func TestChainPhaseChecks(t *testing.T) {
+	t.Parallel()
+
+	obj := newTestObject("v1", "ConfigMap")
+	ref := types.ToObjectRef(obj)
+	phase := types.NewPhase("phase1", []client.Object{obj})
+
+	errA := errors.New("a")
+	errB := errors.New("b")
+	errPhase := errors.New("phase")
+
+	check := func(phaseErr error, errs ...error) PhaseCheck {
+		return PhaseCheckFunc(func(_ context.Context, phase types.Phase, _ ...types.PhaseReconcileOption) error {
+			var oErrs []ObjectValidationError
+			if len(errs) > 0 {
+				oErrs = append(oErrs, ObjectValidationError{ObjectRef: ref, Errors: errs})
+			}
+
+			return NewPhaseValidationError(phase.GetName(), phaseErr, oErrs...)
+		})
+	}
+
+	t.Run("merges validation errors", func(t *testing.T) {
+		t.Parallel()
+
+		err := ChainPhaseChecks(check(nil), check(nil, errA), check(errPhase, errB)).
+			Validate(t.Context(), phase)
+
+		var perr *PhaseValidationError
+		require.ErrorAs(t, err, &perr)
+		assert.Equal(t, &PhaseValidationError{
+			PhaseName:  "phase1",
+			PhaseError: errPhase,
+			Objects: []ObjectValidationError{{
+				ObjectRef: ref,
+				Errors:    []error{errA, errB},
+			}},
+		}, perr)
+	})
+
+	t.Run("no errors", func(t *testing.T) {
+		t.Parallel()
+
+		err := ChainPhaseChecks(check(nil), check(nil)).Validate(t.Context(), phase)
+		require.NoError(t, err)
+	})
+
+	t.Run("stops on other errors", func(t *testing.T) {
+		t.Parallel()
+
+		var called bool
+
+		err := ChainPhaseChecks(
+			PhaseCheckFunc(func(context.Context, types.Phase, ...types.PhaseReconcileOption) error {
+				return errTest
+			}),
+			PhaseCheckFunc(func(context.Context, types.Phase, ...types.PhaseReconcileOption) error {
+				called = true
+
+				return nil
+			}),
+		).Validate(t.Context(), phase)
+		require.ErrorIs(t, err, errTest)
+		assert.False(t, called)
+	})
+}
+
+func TestChainRevisionChecks(t *testing.T) {
+	t.Parallel()
+
+	obj := newTestObject("v1", "ConfigMap")
+	ref := types.ToObjectRef(obj)
+	rev := types.NewRevision("rev", 3, []types.Phase{
+		types.NewPhase("phase1", []client.Object{obj}),
+	})
+
+	errA := errors.New("a")
+	errB := errors.New("b")
+	errRev := errors.New("revision")
+
+	err := ChainRevisionChecks(
+		NewRevisionValidator(),
+		RevisionCheckFunc(func(_ context.Context, rev types.Revision) error {
+			return &RevisionValidationError{
+				RevisionName:   rev.GetName(),
+				RevisionNumber: rev.GetRevisionNumber(),
+				RevisionError:  errRev,
+				Phases: []PhaseValidationError{{
+					PhaseName: "phase1",
+					Objects:   []ObjectValidationError{{ObjectRef: ref, Errors: []error{errA}}},
+				}},
+			}
+		}),
+		RevisionCheckFunc(func(_ context.Context, rev types.Revision) error {
+			return NewRevisionValidationError(rev.GetName(), rev.GetRevisionNumber(), PhaseValidationError{
+				PhaseName: "phase1",
+				Objects:   []ObjectValidationError{{ObjectRef: ref, Errors: []error{errB}}},
+			})
+		}),
+	).Validate(t.Context(), rev)
+
+	var rerr *RevisionValidationError
+	require.ErrorAs(t, err, &rerr)
+	assert.Equal(t, &RevisionValidationError{
+		RevisionName:   "rev",
+		RevisionNumber: 3,
+		RevisionError:  errRev,
+		Phases: []PhaseValidationError{{
+			PhaseName: "phase1",
+			Objects:   []ObjectValidationError{{ObjectRef: ref, Errors: []error{errA, errB}}},
+		}},
+	}, rerr)
+}
@@ -0,0 +1,343 @@
+package validation
+
+import (
+	"encoding/json"
+	"errors"
+
+	apimachineryerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	"pkg.package-operator.run/boxcutter/machinery/types"
+)
+
+// ErrorCode is a stable, machine-readable identifier of a validation error.
+type ErrorCode string
+
+const (
+	// ErrorCodeUnknown is used for errors without a more specific code.
+	ErrorCodeUnknown ErrorCode = "Unknown"
+	// ErrorCodeMetadataRequiredField is used when a required metadata field is empty.
+	ErrorCodeMetadataRequiredField ErrorCode = "MetadataRequiredField"
+	// ErrorCodeMetadataForbiddenField is used when a metadata field must not be set.
+	ErrorCodeMetadataForbiddenField ErrorCode = "MetadataForbiddenField"
+	// ErrorCodeSchemaInvalid is used when an object does not match its OpenAPI schema.
+	ErrorCodeSchemaInvalid ErrorCode = "SchemaInvalid"
+	// ErrorCodeDuplicateObject is used when an object is part of a revision multiple times.
+	ErrorCodeDuplicateObject ErrorCode = "DuplicateObject"
+	// ErrorCodeWrongNamespace is used when an object is placed in a namespace it is not allowed in.
+	ErrorCodeWrongNamespace ErrorCode = "WrongNamespace"
+	// ErrorCodeClusterScoped is used when a cluster-scoped object is not allowed.
+	ErrorCodeClusterScoped ErrorCode = "ClusterScoped"
+	// ErrorCodeAPINotFound is used when the API of an object is not served by the cluster.
+	ErrorCodeAPINotFound ErrorCode = "APINotFound"
+	// ErrorCodeAPIVersionNotServed is used when the API version of an object is not served by the cluster.
+	ErrorCodeAPIVersionNotServed ErrorCode = "APIVersionNotServed"
+	// ErrorCodeObjectCollision is used when an object is already controlled by another owner.
+	ErrorCodeObjectCollision ErrorCode = "ObjectCollision"
+	// ErrorCodeDryRunInvalid is used when the kube-apiserver rejected an object as invalid.
+	ErrorCodeDryRunInvalid ErrorCode = "DryRunInvalid"
+	// ErrorCodeDryRunForbidden is used when the kube-apiserver rejected a dry run as unauthorized.
+	ErrorCodeDryRunForbidden ErrorCode = "DryRunForbidden"
+	// ErrorCodeDryRunFailed is used for all other dry run rejections.
+	ErrorCodeDryRunFailed ErrorCode = "DryRunFailed"
+	// ErrorCodePhaseNameInvalid is used when a phase name is not a valid DNS1035 label.
+	ErrorCodePhaseNameInvalid ErrorCode = "PhaseNameInvalid"
+	// ErrorCodeMissingPermissions is used when RBAC permissions for an object are missing.
+	ErrorCodeMissingPermissions ErrorCode = "MissingPermissions"
+	// ErrorCodePolicyViolation is used when an object violates a Policy.
+	ErrorCodePolicyViolation ErrorCode = "PolicyViolation"
+	// ErrorCodeSizeLimitExceeded is used when an object or revision exceeds a size limit.
+	ErrorCodeSizeLimitExceeded ErrorCode = "SizeLimitExceeded"
+	// ErrorCodeQuotaExceeded is used when new objects would exceed a ResourceQuota.
+	ErrorCodeQuotaExceeded ErrorCode = "QuotaExceeded"
+	// ErrorCodeLimitRangeViolation is used when a container violates a LimitRange.
+	ErrorCodeLimitRangeViolation ErrorCode = "LimitRangeViolation"
+)
+
+// ErrorDetail is a machine-readable representation of a single validation error.
+type ErrorDetail struct {
+	// Code identifies the kind of error.
+	Code ErrorCode `json:"code"`
+	// Field path within the object manifest, if known.
+	Field string `json:"field,omitempty"`
+	// Message is a human readable description of the error.
+	Message string `json:"message"`
+}
+
+// ErrorDetails returns machine-readable details of the given error.
+// Errors may expand into multiple details, e.g. a dry run reporting multiple invalid fields.
+// Errors implementing `ErrorCode() ErrorCode` are reported with their own code.
+func ErrorDetails(err error) []ErrorDetail {
+	var (
+		coded      interface{ ErrorCode() ErrorCode }
+		schemaErr  SchemaValidationError
+		fieldErr   *field.Error
+		dryRunErr  DryRunValidationError
+		phaseNmErr PhaseNameInvalidError
+	)
+
+	if joined, ok := err.(interface{ Unwrap() []error }); ok { //nolint:errorlint
+		// e.g. multiple phase errors joined via errors.Join.
+		return errorDetails(joined.Unwrap()...)
+	}
+
+	switch {
+	case errors.As(err, &coded):
+		return []ErrorDetail{{Code: coded.ErrorCode(), Message: err.Error()}}
+
+	case errors.As(err, &schemaErr):
+		return []ErrorDetail{{
+			Code:    ErrorCodeSchemaInvalid,
+			Field:   schemaErr.FieldError.Field,
+			Message: schemaErr.FieldError.ErrorBody(),
+		}}
+
+	case errors.As(err, &fieldErr):
+		code := ErrorCodeMetadataForbiddenField
+		if fieldErr.Type == field.ErrorTypeRequired {
+			code = ErrorCodeMetadataRequiredField
+		}
+
+		return []ErrorDetail{{Code: code, Field: fieldErr.Field, Message: fieldErr.ErrorBody()}}
+
+	case errors.As(err, &dryRunErr):
+		return dryRunErrorDetails(dryRunErr)
+
+	case errors.As(err, &phaseNmErr):
+		return []ErrorDetail{{Code: ErrorCodePhaseNameInvalid, Message: err.Error()}}
+	}
+
+	code := ErrorCodeUnknown
+
+	switch {
+	case errors.As(err, &PhaseObjectDuplicationError{}):
+		code = ErrorCodeDuplicateObject
+	case errors.As(err, &MustBeInNamespaceError{}),
+		errors.As(err, &NamespaceNotAllowedError{}):
+		code = ErrorCodeWrongNamespace
+	case errors.As(err, &MustBeNamespaceScopedResourceError{}):
+		code = ErrorCodeClusterScoped
+	case errors.As(err, &MissingPermissionsError{}):
+		code = ErrorCodeMissingPermissions
+	case errors.As(err, &PolicyViolationError{}):
+		code = ErrorCodePolicyViolation
+	case errors.As(err, &SizeLimitExceededError{}):
+		code = ErrorCodeSizeLimitExceeded
+	case errors.As(err, &QuotaExceededError{}):
+		code = ErrorCodeQuotaExceeded
+	case errors.As(err, &LimitRangeViolationError{}):
+		code = ErrorCodeLimitRangeViolation
+	case errors.As(err, &APIVersionNotServedError{}):
+		code = ErrorCodeAPIVersionNotServed
+	case errors.As(err, &ObjectCollisionError{}):
+		code = ErrorCodeObjectCollision
+	case meta.IsNoMatchError(err):
+		code = ErrorCodeAPINotFound
+	}
+
+	return []ErrorDetail{{Code: code, Message: err.Error()}}
+}
+
+func dryRunErrorDetails(err DryRunValidationError) []ErrorDetail {
+	if meta.IsNoMatchError(err) {
+		return []ErrorDetail{{Code: ErrorCodeAPINotFound, Message: err.Error()}}
+	}
+
+	var apiErr apimachineryerrors.APIStatus
+	if !errors.As(err, &apiErr) {
+		return []ErrorDetail{{Code: ErrorCodeDryRunFailed, Message: err.Error()}}
+	}
+
+	status := apiErr.Status()
+
+	var code ErrorCode
+
+	switch status.Reason {
+	case metav1.StatusReasonInvalid:
+		code = ErrorCodeDryRunInvalid
+	case metav1.StatusReasonForbidden, metav1.StatusReasonUnauthorized:
+		code = ErrorCodeDryRunForbidden
+	default:
+		code = ErrorCodeDryRunFailed
+	}
+
+	if status.Details == nil || len(status.Details.Causes) == 0 {
+		return []ErrorDetail{{Code: code, Message: err.Error()}}
+	}
+
+	details := make([]ErrorDetail, 0, len(status.Details.Causes))
+	for _, cause := range status.Details.Causes {
+		details = append(details, ErrorDetail{
+			Code:    code,
+			Field:   cause.Field,
+			Message: cause.Message,
+		})
+	}
+
+	return details
+}
+
+func errorDetails(errs ...error) []ErrorDetail {
+	details := make([]ErrorDetail, 0, len(errs))
+	for _, err := range errs {
+		details = append(details, ErrorDetails(err)...)
+	}
+
+	return details
+}
+
+func statusCauses(prefix string, details []ErrorDetail) []metav1.StatusCause {
+	causes := make([]metav1.StatusCause, 0, len(details))
+	for _, d := range details {
+		causes = append(causes, metav1.StatusCause{
+			Type:    metav1.CauseType(d.Code),
+			Message: prefix + d.Message,
+			Field:   d.Field,
+		})
+	}
+
+	return causes
+}
+
+// objectRefJSON is the JSON representation of an ObjectRef.
+type objectRefJSON struct {
+	APIVersion string `json:"apiVersion"`
+	Kind       string `json:"kind"`
+	Namespace  string `json:"namespace,omitempty"`
+	Name       string `json:"name"`
+}
+
+func newObjectRefJSON(ref types.ObjectRef) objectRefJSON {
+	return objectRefJSON{
+		APIVersion: ref.GroupVersionKind.GroupVersion().String(),
+		Kind:       ref.Kind,
+		Namespace:  ref.Namespace,
+		Name:       ref.Name,
+	}
+}
+
+type objectValidationErrorJSON struct {
+	Object objectRefJSON `json:"object"`
+	Errors []ErrorDetail `json:"errors"`
+}
+
+type warningJSON struct {
+	Object  objectRefJSON `json:"object"`
+	Message string        `json:"message"`
+}
+
+type phaseValidationErrorJSON struct {
+	Phase    string                      `json:"phase"`
+	Errors   []ErrorDetail               `json:"errors,omitempty"`
+	Objects  []objectValidationErrorJSON `json:"objects,omitempty"`
+	Warnings []warningJSON               `json:"warnings,omitempty"`
+}
+
+type revisionValidationErrorJSON struct {
+	Revision       string                     `json:"revision"`
+	RevisionNumber int64                      `json:"revisionNumber"`
+	Errors         []ErrorDetail              `json:"errors,omitempty"`
+	Phases         []phaseValidationErrorJSON `json:"phases"`
+}
+
+func (e ObjectValidationError) toJSON() objectValidationErrorJSON {
+	return objectValidationErrorJSON{
+		Object: newObjectRefJSON(e.ObjectRef),
+		Errors: errorDetails(e.Errors...),
+	}
+}
+
+func (e PhaseValidationError) toJSON() phaseValidationErrorJSON {
+	out := phaseValidationErrorJSON{Phase: e.PhaseName}
+	if e.PhaseError != nil {
+		out.Errors = ErrorDetails(e.PhaseError)
+	}
+
+	for _, o := range e.Objects {
+		out.Objects = append(out.Objects, o.toJSON())
+	}
+
+	for _, w := range e.Warnings {
+		out.Warnings = append(out.Warnings, warningJSON{
+			Object: newObjectRefJSON(w.ObjectRef), Message: w.Message,
+		})
+	}
+
+	return out
+}
+
+func (e RevisionValidationError) toJSON() revisionValidationErrorJSON {
+	out := revisionValidationErrorJSON{
+		Revision:       e.RevisionName,
+		RevisionNumber: e.RevisionNumber,
+		Phases:         make([]phaseValidationErrorJSON, 0, len(e.Phases)),
+	}
+	if e.RevisionError != nil {
+		out.Errors = ErrorDetails(e.RevisionError)
+	}
+
+	for _, p := range e.Phases {
+		out.Phases = append(out.Phases, p.toJSON())
+	}
+
+	return out
+}
+
+// MarshalJSON implements json.Marshaler.
+func (e ObjectValidationError) MarshalJSON() ([]byte, error) {
+	return json.Marshal(e.toJSON())
+}
+
+// MarshalJSON implements json.Marshaler.
+func (e PhaseValidationError) MarshalJSON() ([]byte, error) {
+	return json.Marshal(e.toJSON())
+}
+
+// MarshalJSON implements json.Marshaler.
+func (e RevisionValidationError) MarshalJSON() ([]byte, error) {
+	return json.Marshal(e.toJSON())
+}
+
+// ToStatusCauses converts all errors into StatusCauses.
+// Messages are prefixed with the object reference,
+// Field is the path within the object manifest.
+func (e ObjectValidationError) ToStatusCauses() []metav1.StatusCause {
+	return statusCauses(e.ObjectRef.String()+": ", errorDetails(e.Errors...))
+}
+
+// ToStatusCauses converts all errors into StatusCauses.
+// Messages are prefixed with the phase name and object reference,
+// Field is the path within the object manifest.
+func (e PhaseValidationError) ToStatusCauses() []metav1.StatusCause {
+	prefix := "phase " + e.PhaseName + ": "
+
+	var causes []metav1.StatusCause
+	if e.PhaseError != nil {
+		causes = append(causes, statusCauses(prefix, ErrorDetails(e.PhaseError))...)
+	}
+
+	for _, o := range e.Objects {
+		causes = append(causes, statusCauses(
+			prefix+o.ObjectRef.String()+": ", errorDetails(o.Errors...))...)
+	}
+
+	return causes
+}
+
+// ToStatusCauses converts all errors into StatusCauses.
+// Messages are prefixed with the phase name and object reference,
+// Field is the path within the object manifest.
+func (e RevisionValidationError) ToStatusCauses() []metav1.StatusCause {
+	var causes []metav1.StatusCause
+	if e.RevisionError != nil {
+		causes = append(causes, statusCauses("", ErrorDetails(e.RevisionError))...)
+	}
+
+	for _, p := range e.Phases {
+		causes = append(causes, p.ToStatusCauses()...)
+	}
+
+	return causes
+}
@@ -0,0 +1,178 @@
+package validation
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	apimachineryerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+type codedTestError struct{}
+
+func (codedTestError) Error() string { return "coded" }
+
+func (codedTestError) ErrorCode() ErrorCode { return "Custom" }
+
+func TestErrorDetails(t *testing.T) {
+	t.Parallel()
+
+	invalid := apimachineryerrors.NewInvalid(
+		schema.GroupKind{Kind: "ConfigMap"}, "test", field.ErrorList{
+			field.Invalid(field.NewPath("data"), "banana", "must be a map"),
+			field.Required(field.NewPath("metadata", "name"), ""),
+		})
+
+	tests := []struct {
+		name     string
+		err      error
+		expected []ErrorDetail
+	}{
+		{
+			name: "metadata forbidden",
+			err:  field.Forbidden(field.NewPath("metadata", "uid"), "must be empty"),
+			expected: []ErrorDetail{{
+				Code: ErrorCodeMetadataForbiddenField, Field: "metadata.uid", Message: "Forbidden: must be empty",
+			}},
+		},
+		{
+			name: "metadata required",
+			err:  field.Required(field.NewPath("kind"), "must not be empty"),
+			expected: []ErrorDetail{{
+				Code: ErrorCodeMetadataRequiredField, Field: "kind", Message: "Required value: must not be empty",
+			}},
+		},
+		{
+			name: "schema",
+			err: SchemaValidationError{FieldError: &field.Error{
+				Type: field.ErrorTypeForbidden, Field: "spec.color", Detail: "unknown field",
+			}},
+			expected: []ErrorDetail{{
+				Code: ErrorCodeSchemaInvalid, Field: "spec.color", Message: "Forbidden: unknown field",
+			}},
+		},
+		{
+			name: "duplicate",
+			err:  PhaseObjectDuplicationError{PhaseNames: []string{"a", "b"}},
+			expected: []ErrorDetail{{
+				Code: ErrorCodeDuplicateObject, Message: "duplicate object found in phases: a, b",
+			}},
+		},
+		{
+			name: "wrong namespace",
+			err:  MustBeInNamespaceError{ExpectedNamespace: "a", ActualNamespace: "b"},
+			expected: []ErrorDetail{{
+				Code: ErrorCodeWrongNamespace, Message: `object must be in namespace "a", actual "b"`,
+			}},
+		},
+		{
+			name: "phase name",
+			err:  PhaseNameInvalidError{PhaseName: "A", ErrorMessages: []string{"lowercase"}},
+			expected: []ErrorDetail{{
+				Code: ErrorCodePhaseNameInvalid, Message: "phase name invalid: lowercase",
+			}},
+		},
+		{
+			name: "dry run invalid",
+			err:  DryRunValidationError{err: invalid},
+			expected: []ErrorDetail{
+				{Code: ErrorCodeDryRunInvalid, Field: "data", Message: `Invalid value: "banana": must be a map`},
+				{Code: ErrorCodeDryRunInvalid, Field: "metadata.name", Message: "Required value"},
+			},
+		},
+		{
+			name: "dry run forbidden",
+			err: DryRunValidationError{err: apimachineryerrors.NewForbidden(
+				schema.GroupResource{Resource: "configmaps"}, "test", errors.New("nope"))},
+			expected: []ErrorDetail{{
+				Code:    ErrorCodeDryRunForbidden,
+				Message: `configmaps "test" is forbidden: nope`,
+			}},
+		},
+		{
+			name: "dry run no match",
+			err:  DryRunValidationError{err: &meta.NoKindMatchError{}},
+			expected: []ErrorDetail{{
+				Code: ErrorCodeAPINotFound, Message: (&meta.NoKindMatchError{}).Error(),
+			}},
+		},
+		{
+			name:     "custom code",
+			err:      codedTestError{},
+			expected: []ErrorDetail{{Code: "Custom", Message: "coded"}},
+		},
+		{
+			name:     "unknown",
+			err:      errTest,
+			expected: []ErrorDetail{{Code: ErrorCodeUnknown, Message: errTest.Error()}},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, test.expected, ErrorDetails(test.err))
+		})
+	}
+}
+
+func TestRevisionValidationError_MarshalJSON(t *testing.T) {
+	t.Parallel()
+
+	b, err := json.Marshal(errRevisionValidation)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{
+		"revision": "testRev",
+		"revisionNumber": 123,
+		"phases": [{
+			"phase": "testPhase",
+			"errors": [{"code": "Unknown", "message": "AAAAAAh"}],
+			"objects": [{
+				"object": {"apiVersion": "banana/v1", "kind": "Cavendish", "namespace": "bananas", "name": "b-1"},
+				"errors": [{"code": "Unknown", "message": "different AAh"}]
+			}]
+		}]
+	}`, string(b))
+
+	// Pointers as returned by NewRevisionValidationError marshal the same.
+	pb, err := json.Marshal(&errRevisionValidation)
+	require.NoError(t, err)
+	assert.JSONEq(t, string(b), string(pb))
+}
+
+func TestRevisionValidationError_ToStatusCauses(t *testing.T) {
+	t.Parallel()
+
+	e := RevisionValidationError{
+		RevisionName: "testRev",
+		Phases: []PhaseValidationError{{
+			PhaseName:  "testPhase",
+			PhaseError: PhaseNameInvalidError{ErrorMessages: []string{"lowercase"}},
+			Objects: []ObjectValidationError{{
+				ObjectRef: testObjRef,
+				Errors: []error{
+					field.Forbidden(field.NewPath("metadata", "uid"), "must be empty"),
+				},
+			}},
+		}},
+	}
+
+	assert.Equal(t, []metav1.StatusCause{
+		{
+			Type:    "PhaseNameInvalid",
+			Message: "phase testPhase: phase name invalid: lowercase",
+		},
+		{
+			Type:    "MetadataForbiddenField",
+			Message: "phase testPhase: banana/v1, Kind=Cavendish bananas/b-1: Forbidden: must be empty",
+			Field:   "metadata.uid",
+		},
+	}, e.ToStatusCauses())
+}
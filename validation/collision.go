@@ -0,0 +1,193 @@
+package validation
+
+import (
+	"context"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"pkg.package-operator.run/boxcutter/machinery/types"
+)
+
+// CollisionValidator checks all objects of a revision against objects
+// already present in the cluster, that the ObjectEngine would refuse to adopt.
+// Such objects would otherwise only be reported as collision when the
+// phase containing them is reconciled.
+//
+// Owner, OwnerStrategy, CollisionProtection and SiblingOwnerClassifier
+// are taken from the reconcile options of the revision,
+// so results match the behavior of the ObjectEngine:
+//   - objects controlled by other owners collide,
+//     unless handed over to the owner or CollisionProtection is None.
+//   - objects without controller collide with CollisionProtection Prevent,
+//     or when they still carry the revision annotation of another revision.
+//   - objects controlled by a sibling collide, when the sibling has the same revision number.
+//   - shared objects collide, when they have a controller
+//     or were not created as shared object and CollisionProtection is Prevent.
+//
+// Existing objects are indexed by listing each GVK and namespace once.
+// reader should be a cache, e.g. a TrackingCache from the managedcache package,
+// so lists are served from the informers instead of the kube-apiserver.
+type CollisionValidator struct {
+	reader       client.Reader
+	systemPrefix string
+}
+
+// NewCollisionValidator returns a new CollisionValidator instance.
+// systemPrefix must match the system prefix of the RevisionEngine.
+func NewCollisionValidator(reader client.Reader, systemPrefix string) *CollisionValidator {
+	return &CollisionValidator{
+		reader:       reader,
+		systemPrefix: systemPrefix,
+	}
+}
+
+// WithCollisionValidation checks all objects of a revision
+// for collisions with existing objects.
+func WithCollisionValidation(collisionValidator *CollisionValidator) RevisionValidatorOption {
+	return revisionValidatorOptionFn(func(v *RevisionValidator) {
+		v.collisionValidator = collisionValidator
+	})
+}
+
+// ObjectCollisionError is returned when an object
+// already exists and can't be adopted.
+type ObjectCollisionError struct {
+	// Controller is the reference to the current controller of the object.
+	// Nil if the object has no controller.
+	Controller *metav1.OwnerReference
+}
+
+// Error implements the error interface.
+func (e ObjectCollisionError) Error() string {
+	if e.Controller == nil {
+		return "object already exists without controller"
+	}
+
+	return fmt.Sprintf("object already controlled by %s %s (%s)",
+		e.Controller.Kind, e.Controller.Name, e.Controller.UID)
+}
+
+// Validate checks all objects of the given revision for collisions.
+// The function returns nil, if no collisions were found.
+// It returns a RevisionValidationError when it was successfully able to validate the Revision.
+// It returns a different error when unable to validate the Revision.
+func (v *CollisionValidator) Validate(ctx context.Context, rev types.Revision) error {
+	pvs, err := v.validate(ctx, rev)
+	if err != nil {
+		return err
+	}
+
+	return NewRevisionValidationError(rev.GetName(), rev.GetRevisionNumber(), pvs...)
+}
+
+func (v *CollisionValidator) validate(
+	ctx context.Context, rev types.Revision,
+) ([]PhaseValidationError, error) {
+	var revOptions types.RevisionReconcileOptions
+	for _, opt := range rev.GetReconcileOptions() {
+		opt.ApplyToRevisionReconcileOptions(&revOptions)
+	}
+
+	index := collisionIndex{}
+
+	var pvs []PhaseValidationError
+
+	for _, phase := range rev.GetPhases() {
+		var phaseOptions types.PhaseReconcileOptions
+		for _, opt := range append(revOptions.ForPhase(phase.GetName()), phase.GetReconcileOptions()...) {
+			opt.ApplyToPhaseReconcileOptions(&phaseOptions)
+		}
+
+		var objectErrors []ObjectValidationError
+
+		for _, obj := range phase.GetObjects() {
+			var options types.ObjectReconcileOptions
+			for _, opt := range phaseOptions.ForObject(obj) {
+				opt.ApplyToObjectReconcileOptions(&options)
+			}
+
+			options.Default()
+
+			actual, err := index.get(ctx, v.reader, obj)
+			if err != nil {
+				return nil, fmt.Errorf("checking collision of %s: %w", types.ToObjectRef(obj), err)
+			}
+
+			if actual == nil {
+				// Nothing to collide with.
+				continue
+			}
+
+			if err := v.validateObject(actual, rev.GetRevisionNumber(), options); err != nil {
+				objectErrors = append(objectErrors, ObjectValidationError{
+					ObjectRef: types.ToObjectRef(obj),
+					Errors:    []error{err},
+				})
+			}
+		}
+
+		if len(objectErrors) > 0 {
+			pvs = append(pvs, PhaseValidationError{
+				PhaseName: phase.GetName(),
+				Objects:   objectErrors,
+			})
+		}
+	}
+
+	return pvs, nil
+}
+
+// validateObject returns an ObjectCollisionError if the ObjectEngine would report a collision.
+func (v *CollisionValidator) validateObject(
+	actual *unstructured.Unstructured, revision int64, options types.ObjectReconcileOptions,
+) error {
+	if collision, ctrl := types.CheckCollision(actual, revision, v.systemPrefix, options); collision {
+		return ObjectCollisionError{Controller: ctrl}
+	}
+
+	return nil
+}
+
+type collisionIndexKey struct {
+	gvk       schema.GroupVersionKind
+	namespace string
+}
+
+// collisionIndex contains existing objects by GVK and namespace.
+type collisionIndex map[collisionIndexKey]map[string]*unstructured.Unstructured
+
+// get returns the existing object for obj or nil, if it does not exist.
+// Objects of the same GVK and namespace are listed once.
+func (i collisionIndex) get(
+	ctx context.Context, reader client.Reader, obj client.Object,
+) (*unstructured.Unstructured, error) {
+	key := collisionIndexKey{
+		gvk:       obj.GetObjectKind().GroupVersionKind(),
+		namespace: obj.GetNamespace(),
+	}
+
+	objects, ok := i[key]
+	if !ok {
+		list := &unstructured.UnstructuredList{}
+		list.SetGroupVersionKind(key.gvk.GroupVersion().WithKind(key.gvk.Kind + "List"))
+
+		err := reader.List(ctx, list, client.InNamespace(key.namespace))
+		if err != nil && !meta.IsNoMatchError(err) {
+			return nil, err
+		}
+
+		objects = make(map[string]*unstructured.Unstructured, len(list.Items))
+		for j := range list.Items {
+			objects[list.Items[j].GetName()] = &list.Items[j]
+		}
+
+		i[key] = objects
+	}
+
+	return objects[obj.GetName()], nil
+}
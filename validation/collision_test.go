@@ -0,0 +1,223 @@
+package validation
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	k8stypes "k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"pkg.package-operator.run/boxcutter/internal/testutil"
+	"pkg.package-operator.run/boxcutter/machinery/types"
+	"pkg.package-operator.run/boxcutter/ownerhandling"
+)
+
+func TestCollisionValidator(t *testing.T) {
+	t.Parallel()
+
+	ownerStrategy := ownerhandling.NewNative(scheme.Scheme)
+
+	newOwner := func(name string, uid k8stypes.UID) *corev1.ConfigMap {
+		return &corev1.ConfigMap{
+			TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: "ConfigMap"},
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "test", UID: uid},
+		}
+	}
+
+	owner := newOwner("owner", "1")
+	sibling := newOwner("sibling", "2")
+	other := newOwner("other", "3")
+
+	controllerRef := func(name string, uid k8stypes.UID) *metav1.OwnerReference {
+		return &metav1.OwnerReference{
+			APIVersion:         "v1",
+			Kind:               "ConfigMap",
+			Name:               name,
+			UID:                uid,
+			Controller:         ptr.To(true),
+			BlockOwnerDeletion: ptr.To(true),
+		}
+	}
+
+	secret := func(name string) *unstructured.Unstructured {
+		obj := newTestObject("v1", "Secret")
+		obj.SetName(name)
+
+		return obj
+	}
+
+	existing := func(name string, ctrl client.Object, annotations map[string]string) *unstructured.Unstructured {
+		obj := secret(name)
+		obj.SetAnnotations(annotations)
+
+		if ctrl != nil {
+			require.NoError(t, ownerStrategy.SetControllerReference(ctrl, obj))
+		}
+
+		return obj
+	}
+
+	reader := testutil.NewClient()
+	reader.
+		On("List", mock.Anything, mock.Anything, mock.Anything).
+		Run(func(args mock.Arguments) {
+			list := args.Get(1).(*unstructured.UnstructuredList)
+			list.Items = []unstructured.Unstructured{
+				*existing("own", owner, nil),
+				*existing("sibling-older", sibling, map[string]string{"test.boxcutter.io/revision": "1"}),
+				*existing("sibling-same", sibling, map[string]string{"test.boxcutter.io/revision": "2"}),
+				*existing("other", other, nil),
+				*existing("handover", other, map[string]string{"test.boxcutter.io/handover-to": "1"}),
+				*existing("unowned", nil, nil),
+				*existing("orphaned", nil, map[string]string{"test.boxcutter.io/revision": "1"}),
+				*existing("shared", nil, map[string]string{"test.boxcutter.io/shared": "True"}),
+			}
+		}).
+		Return(nil)
+
+	tests := []struct {
+		name   string
+		object string
+		// noOwner validates the object in a revision without owner.
+		noOwner bool
+		// shared validates the object as shared object.
+		shared              bool
+		collisionProtection types.CollisionProtection
+		err                 error
+	}{
+		{name: "new", object: "new", collisionProtection: types.CollisionProtectionPrevent},
+		{name: "own", object: "own", collisionProtection: types.CollisionProtectionPrevent},
+		{name: "older sibling", object: "sibling-older", collisionProtection: types.CollisionProtectionPrevent},
+		{
+			name: "same revision sibling", object: "sibling-same",
+			collisionProtection: types.CollisionProtectionNone,
+			err:                 ObjectCollisionError{Controller: controllerRef("sibling", "2")},
+		},
+		{name: "handover", object: "handover", collisionProtection: types.CollisionProtectionPrevent},
+		{
+			name: "other controller/Prevent", object: "other",
+			collisionProtection: types.CollisionProtectionPrevent,
+			err:                 ObjectCollisionError{Controller: controllerRef("other", "3")},
+		},
+		{
+			name: "other controller/IfNoController", object: "other",
+			collisionProtection: types.CollisionProtectionIfNoController,
+			err:                 ObjectCollisionError{Controller: controllerRef("other", "3")},
+		},
+		{name: "other controller/None", object: "other", collisionProtection: types.CollisionProtectionNone},
+		{
+			name: "unowned/Prevent", object: "unowned",
+			collisionProtection: types.CollisionProtectionPrevent,
+			err:                 ObjectCollisionError{},
+		},
+		{name: "unowned/IfNoController", object: "unowned", collisionProtection: types.CollisionProtectionIfNoController},
+		{name: "unowned/None", object: "unowned", collisionProtection: types.CollisionProtectionNone},
+		{
+			name: "orphaned/None", object: "orphaned",
+			collisionProtection: types.CollisionProtectionNone,
+			err:                 ObjectCollisionError{},
+		},
+		{
+			name: "no owner/Prevent", object: "unowned", noOwner: true,
+			collisionProtection: types.CollisionProtectionPrevent,
+			err:                 ObjectCollisionError{},
+		},
+		{
+			name: "no owner/IfNoController", object: "unowned", noOwner: true,
+			collisionProtection: types.CollisionProtectionIfNoController,
+		},
+		{name: "no owner/managed", object: "orphaned", noOwner: true, collisionProtection: types.CollisionProtectionPrevent},
+		{name: "shared/Prevent", object: "shared", shared: true, collisionProtection: types.CollisionProtectionPrevent},
+		{
+			name: "shared/unowned/Prevent", object: "unowned", shared: true,
+			collisionProtection: types.CollisionProtectionPrevent,
+			err:                 ObjectCollisionError{},
+		},
+		{name: "shared/unowned/IfNoController", object: "unowned", shared: true, collisionProtection: types.CollisionProtectionIfNoController},
+		{
+			name: "shared/other controller/IfNoController", object: "other", shared: true,
+			collisionProtection: types.CollisionProtectionIfNoController,
+			err:                 ObjectCollisionError{Controller: controllerRef("other", "3")},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			opts := []types.PhaseReconcileOption{types.WithCollisionProtection(test.collisionProtection)}
+			if test.shared {
+				opts = append(opts, types.WithShared())
+			}
+
+			phases := []types.Phase{
+				types.NewPhase("phase", []client.Object{secret(test.object)}).
+					WithReconcileOptions(opts...),
+			}
+
+			rev := types.NewRevisionWithOwnerAndSiblings("test", 2, phases, owner, ownerStrategy, []client.Object{sibling})
+			if test.noOwner {
+				rev = types.NewRevision("test", 2, phases)
+			}
+
+			err := NewRevisionValidator(
+				WithCollisionValidation(NewCollisionValidator(reader, "test.boxcutter.io")),
+			).Validate(t.Context(), rev)
+			if test.err == nil {
+				require.NoError(t, err)
+
+				return
+			}
+
+			var rerr *RevisionValidationError
+			require.ErrorAs(t, err, &rerr)
+			assert.Equal(t, []PhaseValidationError{{
+				PhaseName: "phase",
+				Objects: []ObjectValidationError{{
+					ObjectRef: types.ToObjectRef(secret(test.object)),
+					Errors:    []error{test.err},
+				}},
+			}}, rerr.Phases)
+		})
+	}
+
+	assert.Equal(t, []ErrorDetail{{
+		Code:    ErrorCodeObjectCollision,
+		Message: "object already controlled by ConfigMap other (3)",
+	}}, ErrorDetails(ObjectCollisionError{Controller: controllerRef("other", "3")}))
+	assert.Equal(t, []ErrorDetail{{
+		Code:    ErrorCodeObjectCollision,
+		Message: "object already exists without controller",
+	}}, ErrorDetails(ObjectCollisionError{}))
+}
+
+func TestCollisionValidator_ListsOnce(t *testing.T) {
+	t.Parallel()
+
+	reader := testutil.NewClient()
+	reader.
+		On("List", mock.Anything, mock.Anything, mock.Anything).
+		Return(nil)
+
+	secret := func(name string) client.Object {
+		obj := newTestObject("v1", "Secret")
+		obj.SetName(name)
+
+		return obj
+	}
+
+	rev := types.NewRevision("test", 1, []types.Phase{
+		types.NewPhase("phase1", []client.Object{secret("a"), secret("b")}),
+		types.NewPhase("phase2", []client.Object{secret("c"), newTestObject("v1", "ConfigMap")}),
+	})
+
+	require.NoError(t, NewCollisionValidator(reader, "test.boxcutter.io").Validate(t.Context(), rev))
+	reader.AssertNumberOfCalls(t, "List", 2)
+}
@@ -0,0 +1,242 @@
+package validation
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	apimachineryerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/utils/clock"
+	"k8s.io/utils/lru"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// DefaultDryRunCacheTTL is the duration a successful dry run is
+	// remembered, before an unchanged object is dry run again.
+	DefaultDryRunCacheTTL = 10 * time.Minute
+	// DefaultDryRunCacheSize is the number of successful dry runs remembered.
+	DefaultDryRunCacheSize = 4096
+)
+
+// SchemaFingerprintFunc returns a fingerprint of the API schema serving the given GVK.
+// A changing fingerprint invalidates all cached dry runs of the GVK.
+type SchemaFingerprintFunc func(ctx context.Context, gvk schema.GroupVersionKind) (string, error)
+
+// CRDGenerationFingerprint returns a SchemaFingerprintFunc combining the REST mapping
+// of a GVK with the UID and generation of the CustomResourceDefinition serving it.
+// APIs not served by a CustomResourceDefinition, e.g. builtin or aggregated APIs,
+// are fingerprinted by their REST mapping alone.
+// Resources without CustomResourceDefinition are remembered, to look them up only once.
+// reader should be cached, to not trade dry runs for CRD lookups.
+func CRDGenerationFingerprint(restMapper restMapper, reader client.Reader) SchemaFingerprintFunc {
+	var (
+		noCRDLock sync.RWMutex
+		noCRD     = sets.New[schema.GroupResource]()
+	)
+
+	return func(ctx context.Context, gvk schema.GroupVersionKind) (string, error) {
+		mapping, err := restMapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+		if meta.IsNoMatchError(err) {
+			// API does not exist in the cluster, dry run will fail.
+			return "", nil
+		}
+
+		if err != nil {
+			return "", err
+		}
+
+		builtin := fmt.Sprintf("%s/%s", mapping.Resource, mapping.Scope.Name())
+		gr := mapping.Resource.GroupResource()
+
+		noCRDLock.RLock()
+		known := noCRD.Has(gr)
+		noCRDLock.RUnlock()
+
+		if known || !strings.Contains(gvk.Group, ".") {
+			// CustomResourceDefinitions require a group containing a dot,
+			// but builtin groups like networking.k8s.io may contain one too.
+			return builtin, nil
+		}
+
+		crd := &metav1.PartialObjectMetadata{}
+		crd.SetGroupVersionKind(schema.GroupVersionKind{
+			Group:   "apiextensions.k8s.io",
+			Version: "v1",
+			Kind:    "CustomResourceDefinition",
+		})
+
+		err = reader.Get(ctx, client.ObjectKey{Name: gr.String()}, crd)
+		if apimachineryerrors.IsNotFound(err) {
+			noCRDLock.Lock()
+			noCRD.Insert(gr)
+			noCRDLock.Unlock()
+
+			return builtin, nil
+		}
+
+		if err != nil {
+			return "", fmt.Errorf("getting CustomResourceDefinition: %w", err)
+		}
+
+		return fmt.Sprintf("%s/%s/%s/%d",
+			mapping.Resource, mapping.Scope.Name(), crd.UID, crd.Generation), nil
+	}
+}
+
+// DryRunCache remembers successful dry runs of objects,
+// so unchanged objects are not dry run on every reconcile.
+// Entries are keyed by object content, GVK and schema fingerprint.
+// Failed dry runs are never cached.
+// Warnings of cached dry runs are replayed on every hit.
+//
+// Dry run results depend on the permissions of the identity running them,
+// share a DryRunCache only between validators using the same identity.
+// DryRunCache is safe for concurrent use.
+type DryRunCache struct {
+	fingerprint SchemaFingerprintFunc
+	ttl         time.Duration
+	clock       clock.PassiveClock
+	cache       *lru.Cache
+}
+
+// NewDryRunCache returns a new DryRunCache remembering at most
+// maxEntries successful dry runs for the given ttl.
+// fingerprint may be nil to key entries by object content and GVK only.
+func NewDryRunCache(
+	fingerprint SchemaFingerprintFunc,
+	ttl time.Duration,
+	maxEntries int,
+) *DryRunCache {
+	return &DryRunCache{
+		fingerprint: fingerprint,
+		ttl:         ttl,
+		clock:       clock.RealClock{},
+		cache:       lru.New(maxEntries),
+	}
+}
+
+type dryRunCacheKey struct {
+	gvk         schema.GroupVersionKind
+	hash        string
+	fingerprint string
+}
+
+func (c *DryRunCache) key(ctx context.Context, obj client.Object) (dryRunCacheKey, error) {
+	gvk := obj.GetObjectKind().GroupVersionKind()
+
+	b, err := json.Marshal(obj)
+	if err != nil {
+		return dryRunCacheKey{}, err
+	}
+
+	sum := sha256.Sum256(b)
+	key := dryRunCacheKey{
+		gvk:  gvk,
+		hash: hex.EncodeToString(sum[:]),
+	}
+
+	if c.fingerprint != nil {
+		key.fingerprint, err = c.fingerprint(ctx, gvk)
+		if err != nil {
+			return dryRunCacheKey{}, fmt.Errorf("schema fingerprint: %w", err)
+		}
+	}
+
+	return key, nil
+}
+
+type dryRunCacheEntry struct {
+	added time.Time
+	// warnings returned by the kube-apiserver for the dry run.
+	warnings []Warning
+}
+
+func (c *DryRunCache) hit(key dryRunCacheKey) (dryRunCacheEntry, bool) {
+	v, ok := c.cache.Get(key)
+	if !ok {
+		return dryRunCacheEntry{}, false
+	}
+
+	entry := v.(dryRunCacheEntry)
+	if c.clock.Since(entry.added) > c.ttl {
+		c.cache.Remove(key)
+
+		return dryRunCacheEntry{}, false
+	}
+
+	return entry, true
+}
+
+func (c *DryRunCache) add(key dryRunCacheKey, warnings []Warning) {
+	c.cache.Add(key, dryRunCacheEntry{added: c.clock.Now(), warnings: warnings})
+}
+
+// WithDryRunCache skips dry runs of objects that
+// recently passed a dry run without changes.
+// Applies to ObjectValidator and PhaseValidator.
+type WithDryRunCache struct {
+	*DryRunCache
+}
+
+// ApplyToObjectValidator implements ObjectValidatorOption.
+func (w WithDryRunCache) ApplyToObjectValidator(v *ObjectValidator) {
+	v.dryRunCache = w.DryRunCache
+}
+
+// ApplyToPhaseValidator implements PhaseValidatorOption.
+func (w WithDryRunCache) ApplyToPhaseValidator(v *PhaseValidator) {
+	if ov, ok := v.objectValidator.(*ObjectValidator); ok {
+		w.ApplyToObjectValidator(ov)
+	}
+}
+
+// validateDryRun runs a dry run, unless a previous dry run of the same object succeeded.
+// Warnings of a previous dry run are replayed into the objectWarningSink of the context.
+func (d *ObjectValidator) validateDryRun(ctx context.Context, obj client.Object) error {
+	if d.dryRunCache == nil {
+		return validateDryRun(ctx, d.writer, obj)
+	}
+
+	key, err := d.dryRunCache.key(ctx, obj)
+	if err != nil {
+		return err
+	}
+
+	sink, hasSink := ctx.Value(objectWarningSinkKey{}).(objectWarningSink)
+
+	if entry, ok := d.dryRunCache.hit(key); ok {
+		if hasSink {
+			sink.recorder.Record(entry.warnings...)
+		}
+
+		return nil
+	}
+
+	// Capture warnings of the dry run to cache them.
+	captured := &WarningRecorder{}
+	if hasSink {
+		ctx = context.WithValue(ctx, objectWarningSinkKey{}, objectWarningSink{
+			ref: sink.ref, recorder: captured,
+		})
+
+		defer func() { sink.recorder.Record(captured.Warnings()...) }()
+	}
+
+	if err := validateDryRun(ctx, d.writer, obj); err != nil {
+		return err
+	}
+
+	d.dryRunCache.add(key, captured.Warnings())
+
+	return nil
+}
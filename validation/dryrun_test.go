@@ -0,0 +1,204 @@
+package validation
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	apimachineryerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	clocktesting "k8s.io/utils/clock/testing"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"pkg.package-operator.run/boxcutter/internal/testutil"
+	"pkg.package-operator.run/boxcutter/machinery/types"
+)
+
+func TestObjectValidator_DryRunCache(t *testing.T) {
+	t.Parallel()
+
+	writer := &mockWriter{}
+	writer.
+		On("Patch", mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+		Return(nil)
+
+	fingerprint := "1"
+	clock := clocktesting.NewFakePassiveClock(time.Now())
+	cache := NewDryRunCache(func(context.Context, schema.GroupVersionKind) (string, error) {
+		return fingerprint, nil
+	}, time.Minute, 10)
+	cache.clock = clock
+
+	v := NewClusterObjectValidator(&mockRestMapper{}, writer, WithDryRunCache{cache})
+
+	obj := &unstructured.Unstructured{}
+	obj.SetAPIVersion("v1")
+	obj.SetKind("ConfigMap")
+	obj.SetName("test")
+	obj.SetNamespace("test")
+
+	require.NoError(t, v.Validate(t.Context(), obj))
+	writer.AssertNumberOfCalls(t, "Patch", 1)
+
+	// Unchanged.
+	require.NoError(t, v.Validate(t.Context(), obj))
+	writer.AssertNumberOfCalls(t, "Patch", 1)
+
+	// Object changed.
+	obj.SetLabels(map[string]string{"a": "b"})
+	require.NoError(t, v.Validate(t.Context(), obj))
+	writer.AssertNumberOfCalls(t, "Patch", 2)
+
+	// Schema changed.
+	fingerprint = "2"
+	require.NoError(t, v.Validate(t.Context(), obj))
+	writer.AssertNumberOfCalls(t, "Patch", 3)
+
+	// Expired.
+	clock.SetTime(clock.Now().Add(2 * time.Minute))
+	require.NoError(t, v.Validate(t.Context(), obj))
+	writer.AssertNumberOfCalls(t, "Patch", 4)
+}
+
+func TestObjectValidator_DryRunCache_Warnings(t *testing.T) {
+	t.Parallel()
+
+	writer := &mockWriter{}
+	writer.
+		On("Patch", mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+		Run(func(args mock.Arguments) {
+			// Warning header of the dry run.
+			DryRunWarningHandler{}.HandleWarningHeaderWithContext(
+				args.Get(0).(context.Context), 299, "-", "dry run warning")
+		}).
+		Return(nil)
+
+	v := NewClusterObjectValidator(&mockRestMapper{}, writer,
+		WithDryRunCache{NewDryRunCache(nil, time.Minute, 10)})
+
+	obj := &unstructured.Unstructured{}
+	obj.SetAPIVersion("v1")
+	obj.SetKind("ConfigMap")
+	obj.SetName("test")
+
+	for range 2 {
+		recorder := &WarningRecorder{}
+		require.NoError(t, v.Validate(ContextWithWarningRecorder(t.Context(), recorder), obj))
+		assert.Equal(t, []Warning{{
+			ObjectRef: types.ToObjectRef(obj),
+			Message:   "dry run warning",
+		}}, recorder.Warnings())
+	}
+
+	writer.AssertNumberOfCalls(t, "Patch", 1)
+}
+
+func TestObjectValidator_DryRunCache_Failures(t *testing.T) {
+	t.Parallel()
+
+	writer := &mockWriter{}
+	writer.
+		On("Patch", mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+		Return(apimachineryerrors.NewInvalid(schema.GroupKind{Kind: "ConfigMap"}, "test", nil))
+
+	// Option applies to the ObjectValidator of a PhaseValidator.
+	pv := NewClusterPhaseValidator(&mockRestMapper{}, writer,
+		WithDryRunCache{NewDryRunCache(nil, time.Minute, 10)})
+	v := pv.objectValidator.(*ObjectValidator)
+	require.NotNil(t, v.dryRunCache)
+
+	obj := &unstructured.Unstructured{}
+	obj.SetAPIVersion("v1")
+	obj.SetKind("ConfigMap")
+	obj.SetName("test")
+
+	for range 2 {
+		var oerr *ObjectValidationError
+		require.ErrorAs(t, v.Validate(t.Context(), obj), &oerr)
+	}
+
+	writer.AssertNumberOfCalls(t, "Patch", 2)
+}
+
+func TestCRDGenerationFingerprint(t *testing.T) {
+	t.Parallel()
+
+	restMapper := &mockRestMapper{}
+	restMapper.
+		On("RESTMapping", schema.GroupKind{Group: "example.com", Kind: "Widget"}, []string{"v1"}).
+		Return(&meta.RESTMapping{
+			Resource: schema.GroupVersionResource{Group: "example.com", Version: "v1", Resource: "widgets"},
+			Scope:    meta.RESTScopeNamespace,
+		}, nil)
+	restMapper.
+		On("RESTMapping", schema.GroupKind{Kind: "ConfigMap"}, []string{"v1"}).
+		Return(&meta.RESTMapping{
+			Resource: schema.GroupVersionResource{Version: "v1", Resource: "configmaps"},
+			Scope:    meta.RESTScopeNamespace,
+		}, nil)
+	restMapper.
+		On("RESTMapping", schema.GroupKind{Group: "example.com", Kind: "Gizmo"}, []string{"v1"}).
+		Return(&meta.RESTMapping{
+			Resource: schema.GroupVersionResource{Group: "example.com", Version: "v1", Resource: "gizmos"},
+			Scope:    meta.RESTScopeRoot,
+		}, nil)
+	restMapper.
+		On("RESTMapping", schema.GroupKind{Group: "networking.k8s.io", Kind: "Ingress"}, []string{"v1"}).
+		Return(&meta.RESTMapping{
+			Resource: schema.GroupVersionResource{Group: "networking.k8s.io", Version: "v1", Resource: "ingresses"},
+			Scope:    meta.RESTScopeNamespace,
+		}, nil)
+	restMapper.
+		On("RESTMapping", schema.GroupKind{Group: "example.com", Kind: "Gadget"}, []string{"v1"}).
+		Return(nil, &meta.NoKindMatchError{})
+
+	reader := testutil.NewClient()
+	reader.
+		On("Get", mock.Anything, client.ObjectKey{Name: "widgets.example.com"}, mock.Anything, mock.Anything).
+		Run(func(args mock.Arguments) {
+			crd := args.Get(2).(*metav1.PartialObjectMetadata)
+			crd.UID = "uid"
+			crd.Generation = 3
+		}).
+		Return(nil)
+	reader.
+		On("Get", mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+		Return(apimachineryerrors.NewNotFound(schema.GroupResource{}, ""))
+
+	fingerprint := CRDGenerationFingerprint(restMapper, reader)
+
+	fp, err := fingerprint(t.Context(), schema.GroupVersionKind{Group: "example.com", Version: "v1", Kind: "Widget"})
+	require.NoError(t, err)
+	assert.Equal(t, "example.com/v1, Resource=widgets/namespace/uid/3", fp)
+
+	fp, err = fingerprint(t.Context(), schema.GroupVersionKind{Version: "v1", Kind: "ConfigMap"})
+	require.NoError(t, err)
+	assert.Equal(t, "/v1, Resource=configmaps/namespace", fp)
+	reader.AssertNumberOfCalls(t, "Get", 1)
+
+	// Served by an aggregated API server.
+	fp, err = fingerprint(t.Context(), schema.GroupVersionKind{Group: "example.com", Version: "v1", Kind: "Gizmo"})
+	require.NoError(t, err)
+	assert.Equal(t, "example.com/v1, Resource=gizmos/root", fp)
+
+	// Builtin API with a dotted group, looked up once.
+	for range 2 {
+		fp, err = fingerprint(t.Context(), schema.GroupVersionKind{
+			Group: "networking.k8s.io", Version: "v1", Kind: "Ingress",
+		})
+		require.NoError(t, err)
+		assert.Equal(t, "networking.k8s.io/v1, Resource=ingresses/namespace", fp)
+	}
+
+	reader.AssertNumberOfCalls(t, "Get", 3)
+
+	fp, err = fingerprint(t.Context(), schema.GroupVersionKind{Group: "example.com", Version: "v1", Kind: "Gadget"})
+	require.NoError(t, err)
+	assert.Empty(t, fp)
+}
@@ -75,6 +75,8 @@ type PhaseValidationError struct {
 	PhaseError error
 	// Object-scoped errors
 	Objects []ObjectValidationError
+	// Warnings encountered while validating the phase.
+	Warnings []Warning
 }
 
 // NewPhaseValidationError returns a new PhaseValidationError.
@@ -167,7 +169,9 @@ func (e PhaseValidationError) Unwrap() []error {
 type RevisionValidationError struct {
 	RevisionName   string
 	RevisionNumber int64
-	Phases         []PhaseValidationError
+	// Validation error relating to the revision itself.
+	RevisionError error
+	Phases        []PhaseValidationError
 }
 
 // NewRevisionValidationError returns a new RevisionValidationError.
@@ -188,7 +192,11 @@ func NewRevisionValidationError(
 }
 
 func (e RevisionValidationError) stringStructure() any {
-	msgs := make([]any, 0, len(e.Phases))
+	msgs := make([]any, 0, len(e.Phases)+1)
+	if e.RevisionError != nil {
+		msgs = append(msgs, e.RevisionError.Error())
+	}
+
 	for _, obj := range e.Phases {
 		msgs = append(msgs, obj.stringStructure())
 	}
@@ -210,7 +218,11 @@ func (e RevisionValidationError) String() string {
 func (e RevisionValidationError) Error() string {
 	msg := fmt.Sprintf("revision %q (%d): ", e.RevisionName, e.RevisionNumber)
 
-	pmsgs := make([]string, 0, len(e.Phases))
+	pmsgs := make([]string, 0, len(e.Phases)+1)
+	if e.RevisionError != nil {
+		pmsgs = append(pmsgs, e.RevisionError.Error())
+	}
+
 	for _, e := range e.Phases {
 		pmsgs = append(pmsgs, e.Error())
 	}
@@ -220,7 +232,11 @@ func (e RevisionValidationError) Error() string {
 
 // Unwrap implements the errors unwrap interface for errors.Is and errors.As.
 func (e RevisionValidationError) Unwrap() []error {
-	errs := make([]error, 0, len(e.Phases))
+	errs := make([]error, 0, len(e.Phases)+1)
+	if e.RevisionError != nil {
+		errs = append(errs, e.RevisionError)
+	}
+
 	for _, p := range e.Phases {
 		errs = append(errs, p)
 	}
@@ -31,30 +31,76 @@ type ObjectValidator struct {
 
 	// Allows creating objects in namespaces different to Owner.
 	allowNamespaceEscalation bool
+	// Optional, allows selected namespaces and cluster-scoped objects
+	// without allowNamespaceEscalation.
+	scopePolicyFn ScopePolicyFunc
+	// Used to lookup namespace labels for ScopePolicy.NamespaceSelector.
+	reader client.Reader
+	// Optional, remembers successful dry runs.
+	dryRunCache *DryRunCache
+}
+
+// ObjectValidatorOption configures an ObjectValidator.
+type ObjectValidatorOption interface {
+	ApplyToObjectValidator(v *ObjectValidator)
+}
+
+func (d *ObjectValidator) applyOptions(opts ...ObjectValidatorOption) *ObjectValidator {
+	for _, opt := range opts {
+		opt.ApplyToObjectValidator(d)
+	}
+
+	return d
 }
 
 // NewClusterObjectValidator returns an ObjectValidator for cross-cluster deployments.
 func NewClusterObjectValidator(
 	restMapper restMapper,
 	writer client.Writer,
+	opts ...ObjectValidatorOption,
 ) *ObjectValidator {
-	return &ObjectValidator{
+	d := &ObjectValidator{
 		restMapper: restMapper,
 		writer:     writer,
 
 		allowNamespaceEscalation: true,
 	}
+
+	return d.applyOptions(opts...)
 }
 
 // NewNamespacedObjectValidator returns an ObjecctValidator for single-namespace deployments.
 func NewNamespacedObjectValidator(
 	restMapper restMapper,
 	writer client.Writer,
+	opts ...ObjectValidatorOption,
 ) *ObjectValidator {
-	return &ObjectValidator{
+	d := &ObjectValidator{
 		restMapper: restMapper,
 		writer:     writer,
 	}
+
+	return d.applyOptions(opts...)
+}
+
+// NewScopedObjectValidator returns an ObjectValidator for namespaced deployments,
+// allowing objects outside of the owner's namespace as permitted by the ScopePolicy
+// returned for each owner. reader is used to lookup namespace labels.
+func NewScopedObjectValidator(
+	restMapper restMapper,
+	writer client.Writer,
+	reader client.Reader,
+	scopePolicyFn ScopePolicyFunc,
+	opts ...ObjectValidatorOption,
+) *ObjectValidator {
+	d := &ObjectValidator{
+		restMapper:    restMapper,
+		writer:        writer,
+		reader:        reader,
+		scopePolicyFn: scopePolicyFn,
+	}
+
+	return d.applyOptions(opts...)
 }
 
 // Validate validates the given object.
@@ -75,10 +121,25 @@ func (d *ObjectValidator) Validate(
 	errs := validateObjectMetadata(obj)
 
 	if options.Owner != nil && !d.allowNamespaceEscalation {
+		var policy *ScopePolicy
+
+		if d.scopePolicyFn != nil {
+			var err error
+
+			policy, err = d.scopePolicyFn(ctx, options.Owner)
+			if err != nil {
+				return fmt.Errorf("getting scope policy: %w", err)
+			}
+		}
+
 		// Ensure we are not leaving the namespace we are operating in.
 		if err := validateNamespace(
-			d.restMapper, options.Owner.GetNamespace(), obj,
+			ctx, d.restMapper, d.reader, policy, options.Owner.GetNamespace(), obj,
 		); err != nil {
+			if !isScopeViolation(err) {
+				return err
+			}
+
 			errs = append(errs, err)
 			// we don't want to do a dry-run when this already fails.
 			return NewObjectValidationError(bctypes.ToObjectRef(obj), errs...)
@@ -86,7 +147,7 @@ func (d *ObjectValidator) Validate(
 	}
 
 	// Dry run against API server to catch any other surprises.
-	err := validateDryRun(ctx, d.writer, obj)
+	err := d.validateDryRun(contextWithObjectWarningSink(ctx, bctypes.ToObjectRef(obj)), obj)
 	drve := DryRunValidationError{}
 
 	if errors.As(err, &drve) {
@@ -117,9 +178,13 @@ func (e MustBeInNamespaceError) Error() string {
 	return fmt.Sprintf("object must be in namespace %q, actual %q", e.ExpectedNamespace, e.ActualNamespace)
 }
 
-// validates the given object is placed in the given namespace.
+// validates the given object is placed in the given namespace
+// or another scope allowed by the given policy.
 func validateNamespace(
+	ctx context.Context,
 	restMapper restMapper,
+	reader client.Reader,
+	policy *ScopePolicy,
 	namespace string,
 	obj client.Object,
 ) error {
@@ -131,13 +196,17 @@ func validateNamespace(
 	gvk := obj.GetObjectKind().GroupVersionKind()
 
 	mapping, err := restMapper.RESTMapping(gvk.GroupKind(), gvk.Version)
-	if meta.IsNoMatchError(err) {
-		// API does not exist in the cluster.
+	if err != nil {
+		// e.g. API does not exist in the cluster.
 		return err
 	}
 
 	switch mapping.Scope {
 	case meta.RESTScopeRoot:
+		if policy.allowsClusterScoped(obj) {
+			return nil
+		}
+
 		return MustBeNamespaceScopedResourceError{}
 
 	case meta.RESTScopeNamespace:
@@ -145,15 +214,35 @@ func validateNamespace(
 			return nil
 		}
 
-		return MustBeInNamespaceError{
-			ExpectedNamespace: namespace,
-			ActualNamespace:   obj.GetNamespace(),
+		if policy == nil {
+			return MustBeInNamespaceError{
+				ExpectedNamespace: namespace,
+				ActualNamespace:   obj.GetNamespace(),
+			}
+		}
+
+		allowed, err := policy.allowsNamespace(ctx, reader, obj.GetNamespace())
+		if err != nil {
+			return err
 		}
+
+		if allowed {
+			return nil
+		}
+
+		return NamespaceNotAllowedError{Namespace: obj.GetNamespace()}
 	}
 
 	panic(fmt.Sprintf("unexpected REST Mapping Scope %q", mapping.Scope))
 }
 
+func isScopeViolation(err error) bool {
+	return meta.IsNoMatchError(err) ||
+		errors.As(err, &MustBeNamespaceScopedResourceError{}) ||
+		errors.As(err, &MustBeInNamespaceError{}) ||
+		errors.As(err, &NamespaceNotAllowedError{})
+}
+
 // DryRunValidationError is returned for APIStatus codes indicating an issue with the object.
 type DryRunValidationError struct {
 	err error
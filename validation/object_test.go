@@ -395,7 +395,7 @@ func TestValidateNamespace(t *testing.T) {
 			restMapper := &mockRestMapper{}
 			test.mockSetup(restMapper)
 
-			err := validateNamespace(restMapper, test.namespace, test.obj)
+			err := validateNamespace(t.Context(), restMapper, nil, nil, test.namespace, test.obj)
 
 			if test.expectNoError {
 				require.NoError(t, err)
@@ -3,6 +3,7 @@ package validation
 import (
 	"context"
 	"errors"
+	"fmt"
 	"slices"
 	"strings"
 
@@ -20,27 +21,74 @@ type objectValidator interface {
 // Intended as a preflight check be ensure a higher success chance when
 // rolling out the phase and prevent partial application of phases.
 type PhaseValidator struct {
-	objectValidator objectValidator
+	objectValidator     objectValidator
+	apiVersionValidator *APIVersionValidator // may be nil
+	permissionValidator *PermissionValidator // may be nil
+	quotaValidator      *QuotaValidator      // may be nil
+	policies            []Policy
+}
+
+// PhaseValidatorOption configures a PhaseValidator.
+type PhaseValidatorOption interface {
+	ApplyToPhaseValidator(v *PhaseValidator)
+}
+
+type phaseValidatorOptionFn func(v *PhaseValidator)
+
+// ApplyToPhaseValidator implements PhaseValidatorOption.
+func (fn phaseValidatorOptionFn) ApplyToPhaseValidator(v *PhaseValidator) {
+	fn(v)
+}
+
+// WithPermissionValidation checks RBAC permissions of all objects
+// via SelfSubjectAccessReviews, before a dry run is attempted.
+func WithPermissionValidation(permissionValidator *PermissionValidator) PhaseValidatorOption {
+	return phaseValidatorOptionFn(func(v *PhaseValidator) {
+		v.permissionValidator = permissionValidator
+	})
 }
 
 // NewClusterPhaseValidator returns an PhaseValidator for cross-cluster deployments.
 func NewClusterPhaseValidator(
 	restMapper restMapper,
 	writer client.Writer,
+	opts ...PhaseValidatorOption,
 ) *PhaseValidator {
-	return &PhaseValidator{
-		objectValidator: NewClusterObjectValidator(restMapper, writer),
-	}
+	return newPhaseValidator(NewClusterObjectValidator(restMapper, writer), opts...)
 }
 
 // NewNamespacedPhaseValidator returns an ObjecctValidator for single-namespace deployments.
 func NewNamespacedPhaseValidator(
 	restMapper restMapper,
 	writer client.Writer,
+	opts ...PhaseValidatorOption,
+) *PhaseValidator {
+	return newPhaseValidator(NewNamespacedObjectValidator(restMapper, writer), opts...)
+}
+
+// NewScopedPhaseValidator returns a PhaseValidator for namespaced deployments,
+// allowing objects outside of the owner's namespace as permitted by the ScopePolicy
+// returned for each owner. reader is used to lookup namespace labels.
+func NewScopedPhaseValidator(
+	restMapper restMapper,
+	writer client.Writer,
+	reader client.Reader,
+	scopePolicyFn ScopePolicyFunc,
+	opts ...PhaseValidatorOption,
 ) *PhaseValidator {
-	return &PhaseValidator{
-		objectValidator: NewNamespacedObjectValidator(restMapper, writer),
+	return newPhaseValidator(
+		NewScopedObjectValidator(restMapper, writer, reader, scopePolicyFn), opts...)
+}
+
+func newPhaseValidator(ov objectValidator, opts ...PhaseValidatorOption) *PhaseValidator {
+	v := &PhaseValidator{
+		objectValidator: ov,
 	}
+	for _, opt := range opts {
+		opt.ApplyToPhaseValidator(v)
+	}
+
+	return v
 }
 
 // Validate runs validation of the phase and its objects.
@@ -52,6 +100,15 @@ func (v *PhaseValidator) Validate(
 		opt.ApplyToPhaseReconcileOptions(&options)
 	}
 
+	// Collect warnings of this phase separately,
+	// but also pass them on to the caller.
+	warnings := &WarningRecorder{}
+	if parent := types.WarningRecorderFromContext(ctx); parent != nil {
+		defer func() { parent.Record(warnings.Warnings()...) }()
+	}
+
+	ctx = ContextWithWarningRecorder(ctx, warnings)
+
 	phaseError := validatePhaseName(phase)
 
 	var (
@@ -60,7 +117,52 @@ func (v *PhaseValidator) Validate(
 	)
 
 	for _, obj := range phase.GetObjects() {
-		err := v.objectValidator.Validate(ctx, obj, options.ForObject(obj)...)
+		if v.apiVersionValidator != nil {
+			if err := v.apiVersionValidator.Validate(ctx, obj); err != nil {
+				var oerr *ObjectValidationError
+				if !errors.As(err, &oerr) {
+					return fmt.Errorf("validating API version of %s: %w", types.ToObjectRef(obj), err)
+				}
+
+				// Further checks would only fail on the missing API.
+				objectErrors = append(objectErrors, *oerr)
+
+				continue
+			}
+		}
+
+		var permissionDenied bool
+
+		if v.permissionValidator != nil {
+			if err := v.permissionValidator.Validate(ctx, obj); err != nil {
+				var oerr *ObjectValidationError
+				if !errors.As(err, &oerr) {
+					return fmt.Errorf("validating permissions of %s: %w", types.ToObjectRef(obj), err)
+				}
+
+				objectErrors = append(objectErrors, *oerr)
+				permissionDenied = true
+			}
+		}
+
+		policyErrs, err := validatePolicies(ctx, v.policies, obj)
+		if err != nil {
+			return fmt.Errorf("validating policies of %s: %w", types.ToObjectRef(obj), err)
+		}
+
+		if len(policyErrs) > 0 {
+			objectErrors = append(objectErrors, ObjectValidationError{
+				ObjectRef: types.ToObjectRef(obj),
+				Errors:    policyErrs,
+			})
+		}
+
+		if permissionDenied {
+			// We don't want to do a dry run when permissions are already missing.
+			continue
+		}
+
+		err = v.objectValidator.Validate(ctx, obj, options.ForObject(obj)...)
 		if err == nil {
 			continue
 		}
@@ -80,8 +182,23 @@ func (v *PhaseValidator) Validate(
 
 	objectErrors = append(objectErrors, checkForObjectDuplicates(phase)...)
 
-	return NewPhaseValidationError(
+	if v.quotaValidator != nil {
+		quotaErrs, oErrs, err := v.quotaValidator.validate(ctx, phase)
+		if err != nil {
+			return fmt.Errorf("validating quota: %w", err)
+		}
+
+		objectErrors = append(objectErrors, oErrs...)
+		phaseError = errors.Join(append([]error{phaseError}, quotaErrs...)...)
+	}
+
+	err := NewPhaseValidationError(
 		phase.GetName(), phaseError, compactObjectViolations(objectErrors)...)
+	if perr, ok := err.(*PhaseValidationError); ok { //nolint:errorlint
+		perr.Warnings = warnings.Warnings()
+	}
+
+	return err
 }
 
 // PhaseNameInvalidError is returned when the phase name does not validate.
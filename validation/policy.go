@@ -0,0 +1,335 @@
+package validation
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+
+	"github.com/google/cel-go/cel"
+	celtypes "github.com/google/cel-go/common/types"
+	"github.com/google/cel-go/common/types/ref"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	celconfig "k8s.io/apiserver/pkg/apis/cel"
+	"k8s.io/apiserver/pkg/cel/lazy"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"pkg.package-operator.run/boxcutter/internal/celenv"
+)
+
+// Policy validates objects against organisation specific rules,
+// e.g. "images must come from our registry".
+type Policy interface {
+	// Validate returns all rules violated by the given object.
+	// It returns an error when unable to validate the object.
+	Validate(ctx context.Context, obj client.Object) ([]PolicyViolationError, error)
+}
+
+// PolicyViolationError is returned when an object violates a Policy.
+type PolicyViolationError struct {
+	// Name of the violated policy.
+	Policy string
+	// Message describing the violation.
+	Message string
+}
+
+// Error implements the error interface.
+func (e PolicyViolationError) Error() string {
+	return fmt.Sprintf("policy %q: %s", e.Policy, e.Message)
+}
+
+// WithPolicies validates all objects against the given policies.
+// Applies to PhaseValidator and RevisionValidator.
+type WithPolicies []Policy
+
+// ApplyToPhaseValidator implements PhaseValidatorOption.
+func (w WithPolicies) ApplyToPhaseValidator(v *PhaseValidator) {
+	v.policies = append(v.policies, w...)
+}
+
+// ApplyToRevisionValidator implements RevisionValidatorOption.
+func (w WithPolicies) ApplyToRevisionValidator(v *RevisionValidator) {
+	v.policies = append(v.policies, w...)
+}
+
+func validatePolicies(ctx context.Context, policies []Policy, obj client.Object) ([]error, error) {
+	var errs []error
+
+	for _, p := range policies {
+		violations, err := p.Validate(ctx, obj)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, v := range violations {
+			errs = append(errs, v)
+		}
+	}
+
+	return errs, nil
+}
+
+// CELPolicySpec describes a CELPolicy.
+// Semantics follow a ValidatingAdmissionPolicy with failurePolicy "Fail":
+// the object is available as "object" and variables as "variables.<name>".
+// Match conditions are evaluated first and can't reference variables.
+// Variables are evaluated lazily, when first referenced by a validation.
+type CELPolicySpec struct {
+	// MatchKinds restricts the policy to the given kinds.
+	// An empty list matches all kinds.
+	MatchKinds []schema.GroupKind
+	// MatchConditions must all evaluate to true for the policy to apply.
+	MatchConditions []CELPolicyMatchCondition
+	// Variables may reference variables defined before them.
+	Variables []CELPolicyVariable
+	// Validations must all evaluate to true, otherwise the policy is violated.
+	Validations []CELPolicyValidation
+}
+
+// CELPolicyMatchCondition is a CEL expression evaluating to a bool.
+type CELPolicyMatchCondition struct {
+	Name       string
+	Expression string
+}
+
+// CELPolicyVariable is a named CEL expression.
+type CELPolicyVariable struct {
+	Name       string
+	Expression string
+}
+
+// CELPolicyValidation is a CEL expression evaluating to a bool.
+type CELPolicyValidation struct {
+	Expression string
+	// Message reported when the expression evaluates to false.
+	// Defaults to "failed expression: <expression>".
+	Message string
+	// MessageExpression is a CEL expression evaluating to a string,
+	// used as message instead of the static message.
+	MessageExpression string
+}
+
+// CELPolicy is a Policy using the common expression language.
+type CELPolicy struct {
+	name            string
+	matchKinds      []schema.GroupKind
+	matchConditions []celPolicyProgram
+	variables       []celPolicyProgram
+	validations     []celPolicyValidation
+}
+
+type celPolicyProgram struct {
+	name       string
+	expression string
+	program    cel.Program
+}
+
+type celPolicyValidation struct {
+	celPolicyProgram
+
+	message        string
+	messageProgram cel.Program
+}
+
+var _ Policy = (*CELPolicy)(nil)
+
+// NewCELPolicy compiles all expressions of the given spec into a new CELPolicy.
+func NewCELPolicy(name string, spec CELPolicySpec) (*CELPolicy, error) {
+	p := &CELPolicy{
+		name:       name,
+		matchKinds: spec.MatchKinds,
+	}
+
+	var errs []error
+
+	for _, v := range spec.Variables {
+		prgm, err := compilePolicyCEL(policyCELEnv, v.Expression, cel.DynType)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("variable %q: %w", v.Name, err))
+		}
+
+		p.variables = append(p.variables, celPolicyProgram{
+			name: v.Name, expression: v.Expression, program: prgm,
+		})
+	}
+
+	for _, mc := range spec.MatchConditions {
+		prgm, err := compilePolicyCEL(matchConditionCELEnv, mc.Expression, cel.BoolType)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("match condition %q: %w", mc.Name, err))
+		}
+
+		p.matchConditions = append(p.matchConditions, celPolicyProgram{
+			name: mc.Name, expression: mc.Expression, program: prgm,
+		})
+	}
+
+	for i, val := range spec.Validations {
+		prgm, err := compilePolicyCEL(policyCELEnv, val.Expression, cel.BoolType)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("validation[%d]: %w", i, err))
+		}
+
+		cv := celPolicyValidation{
+			celPolicyProgram: celPolicyProgram{expression: val.Expression, program: prgm},
+			message:          val.Message,
+		}
+
+		if len(val.MessageExpression) > 0 {
+			cv.messageProgram, err = compilePolicyCEL(policyCELEnv, val.MessageExpression, cel.StringType)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("validation[%d] message expression: %w", i, err))
+			}
+		}
+
+		p.validations = append(p.validations, cv)
+	}
+
+	if len(errs) > 0 {
+		return nil, fmt.Errorf("policy %q: %w", name, errors.Join(errs...))
+	}
+
+	return p, nil
+}
+
+// Validate implements Policy.
+func (p *CELPolicy) Validate(_ context.Context, obj client.Object) ([]PolicyViolationError, error) {
+	gvk := obj.GetObjectKind().GroupVersionKind()
+	if len(p.matchKinds) > 0 && !slices.Contains(p.matchKinds, gvk.GroupKind()) {
+		return nil, nil
+	}
+
+	u, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, mc := range p.matchConditions {
+		val, _, err := mc.program.Eval(map[string]any{"object": u})
+		if err != nil {
+			return p.violation("match condition %q failed: %v", mc.name, err), nil
+		}
+
+		if matches, ok := val.Value().(bool); !ok || !matches {
+			return nil, nil
+		}
+	}
+
+	// Variables are only evaluated when referenced,
+	// errors fail the referencing validations.
+	variables := lazy.NewMapValue(celtypes.MapType)
+	activation := map[string]any{
+		"object":    u,
+		"variables": variables,
+	}
+
+	evaluating := map[string]bool{}
+
+	for _, v := range p.variables {
+		variables.Append(v.name, func(*lazy.MapValue) ref.Val {
+			if evaluating[v.name] {
+				return celtypes.NewErr("variable %q references itself", v.name)
+			}
+
+			evaluating[v.name] = true
+			defer delete(evaluating, v.name)
+
+			val, _, err := v.program.Eval(activation)
+			if err != nil {
+				return celtypes.NewErr("variable %q failed: %v", v.name, err)
+			}
+
+			return val
+		})
+	}
+
+	var violations []PolicyViolationError
+
+	for _, v := range p.validations {
+		val, _, err := v.program.Eval(activation)
+		if err != nil {
+			violations = append(violations, p.violation(
+				"expression %q failed: %v", v.expression, err)...)
+
+			continue
+		}
+
+		if valid, ok := val.Value().(bool); !ok || !valid {
+			violations = append(violations, PolicyViolationError{
+				Policy:  p.name,
+				Message: v.evalMessage(activation),
+			})
+		}
+	}
+
+	return violations, nil
+}
+
+func (p *CELPolicy) violation(format string, args ...any) []PolicyViolationError {
+	return []PolicyViolationError{{
+		Policy:  p.name,
+		Message: fmt.Sprintf(format, args...),
+	}}
+}
+
+func (v celPolicyValidation) evalMessage(activation map[string]any) string {
+	if v.messageProgram != nil {
+		val, _, err := v.messageProgram.Eval(activation)
+		if err == nil {
+			if msg, ok := val.Value().(string); ok && len(msg) > 0 {
+				return msg
+			}
+		}
+	}
+
+	if len(v.message) > 0 {
+		return v.message
+	}
+
+	return "failed expression: " + v.expression
+}
+
+var (
+	policyCELEnv = sync.OnceValues(func() (*cel.Env, error) {
+		return newPolicyCELEnv(cel.Variable("variables", cel.MapType(cel.StringType, cel.DynType)))
+	})
+	// Match conditions are evaluated before variables, so they can't reference them.
+	matchConditionCELEnv = sync.OnceValues(func() (*cel.Env, error) {
+		return newPolicyCELEnv()
+	})
+)
+
+func newPolicyCELEnv(opts ...cel.EnvOption) (*cel.Env, error) {
+	return celenv.New(append([]cel.EnvOption{
+		cel.Variable("object", cel.DynType),
+	}, opts...)...)
+}
+
+func compilePolicyCEL(
+	newEnv func() (*cel.Env, error), expression string, outputType *cel.Type,
+) (cel.Program, error) {
+	env, err := newEnv()
+	if err != nil {
+		return nil, fmt.Errorf("creating CEL env: %w", err)
+	}
+
+	ast, issues := env.Compile(expression)
+	if issues != nil && issues.Err() != nil {
+		return nil, fmt.Errorf("compiling CEL: %w", issues.Err())
+	}
+
+	if outputType != cel.DynType &&
+		!ast.OutputType().IsExactType(outputType) &&
+		!ast.OutputType().IsExactType(cel.DynType) {
+		return nil, fmt.Errorf("CEL expression must evaluate to %s, got %s", outputType, ast.OutputType())
+	}
+
+	prgm, err := celenv.Program(env, ast, celconfig.PerCallLimit)
+	if err != nil {
+		return nil, fmt.Errorf("CEL program failed: %w", err)
+	}
+
+	return prgm, nil
+}
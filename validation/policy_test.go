@@ -0,0 +1,284 @@
+package validation
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"pkg.package-operator.run/boxcutter/machinery/types"
+)
+
+func TestCELPolicy(t *testing.T) {
+	t.Parallel()
+
+	registry, err := NewCELPolicy("registry", CELPolicySpec{
+		MatchKinds: []schema.GroupKind{{Group: "apps", Kind: "Deployment"}},
+		MatchConditions: []CELPolicyMatchCondition{{
+			Name:       "not-exempt",
+			Expression: `!has(object.metadata.labels) || !("exempt" in object.metadata.labels)`,
+		}},
+		Variables: []CELPolicyVariable{{
+			Name:       "images",
+			Expression: `object.spec.template.spec.containers.map(c, c.image)`,
+		}},
+		Validations: []CELPolicyValidation{
+			{
+				Expression:        `variables.images.all(i, i.startsWith("registry.example.com/"))`,
+				MessageExpression: `"images must come from registry.example.com: " + variables.images.join(", ")`,
+			},
+			{
+				Expression: `!has(object.spec.template.spec.volumes) ||
+					object.spec.template.spec.volumes.all(v, !has(v.hostPath))`,
+			},
+		},
+	})
+	require.NoError(t, err)
+
+	deploy := func(image string, labels map[string]any, volumes ...any) client.Object {
+		podSpec := map[string]any{
+			"containers": []any{map[string]any{"name": "c", "image": image}},
+		}
+		if len(volumes) > 0 {
+			podSpec["volumes"] = volumes
+		}
+
+		return &unstructured.Unstructured{Object: map[string]any{
+			"apiVersion": "apps/v1",
+			"kind":       "Deployment",
+			"metadata":   map[string]any{"name": "test", "labels": labels},
+			"spec": map[string]any{
+				"template": map[string]any{"spec": podSpec},
+			},
+		}}
+	}
+
+	tests := []struct {
+		name     string
+		obj      client.Object
+		messages []string
+	}{
+		{
+			name: "valid",
+			obj:  deploy("registry.example.com/app", nil),
+		},
+		{
+			name:     "wrong registry",
+			obj:      deploy("docker.io/app", map[string]any{}),
+			messages: []string{"images must come from registry.example.com: docker.io/app"},
+		},
+		{
+			name: "multiple violations",
+			obj: deploy("docker.io/app", nil,
+				map[string]any{"name": "host", "hostPath": map[string]any{"path": "/"}}),
+			messages: []string{
+				"images must come from registry.example.com: docker.io/app",
+				"failed expression: !has(object.spec.template.spec.volumes) ||\n" +
+					"\t\t\t\t\tobject.spec.template.spec.volumes.all(v, !has(v.hostPath))",
+			},
+		},
+		{
+			name: "match condition",
+			obj:  deploy("docker.io/app", map[string]any{"exempt": "true"}),
+		},
+		{
+			name: "other kind",
+			obj: &unstructured.Unstructured{Object: map[string]any{
+				"apiVersion": "v1",
+				"kind":       "ConfigMap",
+			}},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			violations, err := registry.Validate(t.Context(), test.obj)
+			require.NoError(t, err)
+
+			messages := make([]string, 0, len(violations))
+			for _, v := range violations {
+				assert.Equal(t, "registry", v.Policy)
+				messages = append(messages, v.Message)
+			}
+
+			if len(test.messages) == 0 {
+				assert.Empty(t, messages)
+
+				return
+			}
+
+			assert.Equal(t, test.messages, messages)
+		})
+	}
+}
+
+func TestCELPolicy_LazyVariables(t *testing.T) {
+	t.Parallel()
+
+	replicas, err := NewCELPolicy("replicas", CELPolicySpec{
+		MatchConditions: []CELPolicyMatchCondition{{
+			Name:       "has-replicas",
+			Expression: `has(object.spec) && has(object.spec.replicas)`,
+		}},
+		Variables: []CELPolicyVariable{
+			{Name: "replicas", Expression: `object.spec.replicas`},
+			{Name: "unused", Expression: `object.spec.missing`},
+			{Name: "cycle", Expression: `variables.cycle`},
+		},
+		Validations: []CELPolicyValidation{{
+			Expression: `variables.replicas <= 3`,
+			Message:    "too many replicas",
+		}},
+	})
+	require.NoError(t, err)
+
+	cycle, err := NewCELPolicy("cycle", CELPolicySpec{
+		Variables: []CELPolicyVariable{{Name: "cycle", Expression: `variables.cycle`}},
+		Validations: []CELPolicyValidation{{
+			Expression: `variables.cycle == 1`,
+		}},
+	})
+	require.NoError(t, err)
+
+	obj := func(spec map[string]any) client.Object {
+		return &unstructured.Unstructured{Object: map[string]any{
+			"apiVersion": "apps/v1",
+			"kind":       "Deployment",
+			"metadata":   map[string]any{"name": "test"},
+			"spec":       spec,
+		}}
+	}
+
+	tests := []struct {
+		name     string
+		policy   *CELPolicy
+		obj      client.Object
+		messages []string
+	}{
+		{
+			name:   "valid",
+			policy: replicas,
+			obj:    obj(map[string]any{"replicas": int64(3)}),
+		},
+		{
+			name:     "invalid",
+			policy:   replicas,
+			obj:      obj(map[string]any{"replicas": int64(4)}),
+			messages: []string{"too many replicas"},
+		},
+		{
+			// The variable would fail, but is never evaluated.
+			name:   "not matched",
+			policy: replicas,
+			obj:    obj(map[string]any{}),
+		},
+		{
+			name:   "cycle",
+			policy: cycle,
+			obj:    obj(map[string]any{}),
+			messages: []string{
+				`expression "variables.cycle == 1" failed: variable "cycle" failed: variable "cycle" references itself`,
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			violations, err := test.policy.Validate(t.Context(), test.obj)
+			require.NoError(t, err)
+
+			messages := make([]string, 0, len(violations))
+			for _, v := range violations {
+				messages = append(messages, v.Message)
+			}
+
+			if len(test.messages) == 0 {
+				assert.Empty(t, messages)
+
+				return
+			}
+
+			assert.Equal(t, test.messages, messages)
+		})
+	}
+}
+
+func TestNewCELPolicy_Invalid(t *testing.T) {
+	t.Parallel()
+
+	_, err := NewCELPolicy("invalid", CELPolicySpec{
+		Validations: []CELPolicyValidation{
+			{Expression: `object.metadata.name + "x"`},
+			{Expression: `true`, MessageExpression: `1`},
+		},
+		MatchConditions: []CELPolicyMatchCondition{
+			// Match conditions can't reference variables.
+			{Name: "variables", Expression: `variables.a == 1`},
+		},
+	})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `match condition "variables"`)
+	assert.Contains(t, err.Error(), "validation[0]")
+	assert.Contains(t, err.Error(), "validation[1] message expression")
+}
+
+func TestPolicies_Validators(t *testing.T) {
+	t.Parallel()
+
+	noClusterAdmin, err := NewCELPolicy("no-cluster-admin", CELPolicySpec{
+		MatchKinds: []schema.GroupKind{{Group: "rbac.authorization.k8s.io", Kind: "ClusterRoleBinding"}},
+		Validations: []CELPolicyValidation{{
+			Expression: `object.roleRef.name != "cluster-admin"`,
+			Message:    "must not bind cluster-admin",
+		}},
+	})
+	require.NoError(t, err)
+
+	crb := &unstructured.Unstructured{Object: map[string]any{
+		"apiVersion": "rbac.authorization.k8s.io/v1",
+		"kind":       "ClusterRoleBinding",
+		"metadata":   map[string]any{"name": "test"},
+		"roleRef":    map[string]any{"name": "cluster-admin"},
+	}}
+	phase := types.NewPhase("phase", []client.Object{crb})
+	expected := []error{PolicyViolationError{
+		Policy: "no-cluster-admin", Message: "must not bind cluster-admin",
+	}}
+
+	t.Run("revision", func(t *testing.T) {
+		t.Parallel()
+
+		v := NewRevisionValidator(WithPolicies{noClusterAdmin})
+		err := v.Validate(t.Context(), types.NewRevision("test", 1, []types.Phase{phase}))
+
+		var rerr *RevisionValidationError
+		require.ErrorAs(t, err, &rerr)
+		require.Len(t, rerr.Phases, 1)
+		require.Len(t, rerr.Phases[0].Objects, 1)
+		assert.Equal(t, expected, rerr.Phases[0].Objects[0].Errors)
+	})
+
+	t.Run("phase", func(t *testing.T) {
+		t.Parallel()
+
+		ov := &mockObjectValidator{}
+		ov.On("Validate", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+		v := newPhaseValidator(ov, WithPolicies{noClusterAdmin})
+		err := v.Validate(t.Context(), phase)
+
+		var perr *PhaseValidationError
+		require.ErrorAs(t, err, &perr)
+		require.Len(t, perr.Objects, 1)
+		assert.Equal(t, types.ToObjectRef(crb), perr.Objects[0].ObjectRef)
+		assert.Equal(t, expected, perr.Objects[0].Errors)
+	})
+}
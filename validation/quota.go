@@ -0,0 +1,505 @@
+package validation
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	corev1 "k8s.io/api/core/v1"
+	apimachineryerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"pkg.package-operator.run/boxcutter/machinery/types"
+)
+
+// legacyCountResources may also be limited by their plain resource name in a ResourceQuota.
+var legacyCountResources = []string{
+	"pods", "services", "secrets", "configmaps",
+	"persistentvolumeclaims", "replicationcontrollers", "resourcequotas",
+}
+
+// QuotaValidator checks new objects of a phase against the ResourceQuotas
+// and LimitRanges of their namespace, before anything in the phase is written.
+//
+// Object counts, storage requests of PersistentVolumeClaims and
+// resource requests of Pods exceeding a quota are reported as QuotaExceededError.
+// Resource requests of pod templates of workloads are multiplied by spec.replicas if present.
+// Pods of workloads are only created later by their controller and
+// e.g. rolling updates or DaemonSets change the actual number of pods,
+// so quotas exceeded by them are only recorded as Warning on the workload.
+// ResourceQuotas with scopes are skipped, as they only apply to a subset of pods.
+type QuotaValidator struct {
+	restMapper restMapper
+	reader     client.Reader
+}
+
+// NewQuotaValidator returns a new QuotaValidator instance.
+// reader is used to lookup objects, ResourceQuotas and LimitRanges.
+func NewQuotaValidator(restMapper restMapper, reader client.Reader) *QuotaValidator {
+	return &QuotaValidator{
+		restMapper: restMapper,
+		reader:     reader,
+	}
+}
+
+// WithQuotaValidation checks new objects against ResourceQuotas and LimitRanges.
+func WithQuotaValidation(quotaValidator *QuotaValidator) PhaseValidatorOption {
+	return phaseValidatorOptionFn(func(v *PhaseValidator) {
+		v.quotaValidator = quotaValidator
+	})
+}
+
+// QuotaExceededError is returned when new objects would exceed a ResourceQuota.
+type QuotaExceededError struct {
+	Namespace string
+	// Name of the ResourceQuota.
+	Quota    string
+	Resource corev1.ResourceName
+	// Requested by new objects in the phase.
+	Requested resource.Quantity
+	Used      resource.Quantity
+	Hard      resource.Quantity
+}
+
+// Error implements the error interface.
+func (e QuotaExceededError) Error() string {
+	return fmt.Sprintf(
+		"exceeded quota %s/%s: requested %s=%s, used %s, limited %s",
+		e.Namespace, e.Quota, e.Resource, e.Requested.String(), e.Used.String(), e.Hard.String())
+}
+
+// LimitRangeViolationError is returned when a container violates a LimitRange.
+type LimitRangeViolationError struct {
+	// Name of the LimitRange.
+	LimitRange string
+	Container  string
+	Resource   corev1.ResourceName
+	Message    string
+}
+
+// Error implements the error interface.
+func (e LimitRangeViolationError) Error() string {
+	return fmt.Sprintf("container %q violates LimitRange %q: %s %s",
+		e.Container, e.LimitRange, e.Resource, e.Message)
+}
+
+// validate returns phase level quota errors and object level LimitRange errors.
+func (v *QuotaValidator) validate(
+	ctx context.Context, phase types.Phase,
+) ([]error, []ObjectValidationError, error) {
+	newObjects, err := v.newObjectsByNamespace(ctx, phase)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var (
+		phaseErrs    []error
+		objectErrors []ObjectValidationError
+	)
+
+	for _, ns := range sortedKeys(newObjects) {
+		quotas := &corev1.ResourceQuotaList{}
+		if err := v.reader.List(ctx, quotas, client.InNamespace(ns)); err != nil {
+			return nil, nil, fmt.Errorf("listing ResourceQuotas: %w", err)
+		}
+
+		limitRanges := &corev1.LimitRangeList{}
+		if err := v.reader.List(ctx, limitRanges, client.InNamespace(ns)); err != nil {
+			return nil, nil, fmt.Errorf("listing LimitRanges: %w", err)
+		}
+
+		var (
+			usage     = corev1.ResourceList{}
+			podUsage  = corev1.ResourceList{}
+			workloads []workloadUsage
+		)
+
+		for _, obj := range newObjects[ns] {
+			objUsage, objPodUsage, lrErrs, err := quotaUsage(obj.obj, obj.mapping, limitRanges.Items)
+			if err != nil {
+				return nil, nil, fmt.Errorf("computing usage of %s: %w", types.ToObjectRef(obj.obj), err)
+			}
+
+			addResources(usage, objUsage)
+			addResources(podUsage, objPodUsage)
+
+			if len(objPodUsage) > 0 {
+				workloads = append(workloads, workloadUsage{obj: obj.obj, podUsage: objPodUsage})
+			}
+
+			if len(lrErrs) > 0 {
+				objectErrors = append(objectErrors, ObjectValidationError{
+					ObjectRef: types.ToObjectRef(obj.obj),
+					Errors:    lrErrs,
+				})
+			}
+		}
+
+		total := usage.DeepCopy()
+		addResources(total, podUsage)
+
+		for _, quota := range quotas.Items {
+			exceeded := map[corev1.ResourceName]bool{}
+
+			for _, qerr := range checkQuota(quota, usage) {
+				exceeded[qerr.Resource] = true
+				phaseErrs = append(phaseErrs, qerr)
+			}
+
+			for _, qerr := range checkQuota(quota, total) {
+				if !exceeded[qerr.Resource] {
+					recordPodQuotaWarnings(ctx, workloads, qerr)
+				}
+			}
+		}
+	}
+
+	return phaseErrs, objectErrors, nil
+}
+
+// workloadUsage is the quota usage of pods created from a workload.
+type workloadUsage struct {
+	obj      client.Object
+	podUsage corev1.ResourceList
+}
+
+// recordPodQuotaWarnings records a Warning for all workloads
+// with pods contributing to the exceeded quota.
+func recordPodQuotaWarnings(ctx context.Context, workloads []workloadUsage, qerr QuotaExceededError) {
+	for _, w := range workloads {
+		if _, ok := w.podUsage[qerr.Resource]; !ok {
+			continue
+		}
+
+		RecordWarning(ctx, Warning{
+			ObjectRef: types.ToObjectRef(w.obj),
+			Message:   "pods may be rejected: " + qerr.Error(),
+		})
+	}
+}
+
+type newObject struct {
+	obj     client.Object
+	mapping *meta.RESTMapping
+}
+
+func (v *QuotaValidator) newObjectsByNamespace(
+	ctx context.Context, phase types.Phase,
+) (map[string][]newObject, error) {
+	out := map[string][]newObject{}
+
+	for _, obj := range phase.GetObjects() {
+		gvk := obj.GetObjectKind().GroupVersionKind()
+
+		mapping, err := v.restMapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+		if meta.IsNoMatchError(err) {
+			// API does not exist in the cluster, reported by dry run.
+			continue
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		if mapping.Scope.Name() != meta.RESTScopeNameNamespace || len(obj.GetNamespace()) == 0 {
+			continue
+		}
+
+		actual := &unstructured.Unstructured{}
+		actual.SetGroupVersionKind(gvk)
+
+		err = v.reader.Get(ctx, client.ObjectKeyFromObject(obj), actual)
+		if err == nil {
+			// Already exists.
+			continue
+		}
+
+		if !apimachineryerrors.IsNotFound(err) {
+			return nil, fmt.Errorf("getting %s: %w", types.ToObjectRef(obj), err)
+		}
+
+		out[obj.GetNamespace()] = append(out[obj.GetNamespace()], newObject{obj: obj, mapping: mapping})
+	}
+
+	return out, nil
+}
+
+// quotaUsage returns the quota usage of a new object
+// and of pods created later from its pod template.
+func quotaUsage(
+	obj client.Object, mapping *meta.RESTMapping, limitRanges []corev1.LimitRange,
+) (usage, podUsage corev1.ResourceList, lrErrs []error, err error) {
+	usage = corev1.ResourceList{}
+	addCount(usage, mapping.Resource.Resource, mapping.Resource.Group, 1)
+
+	u, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	if mapping.Resource.Group == "" && mapping.Resource.Resource == "persistentvolumeclaims" {
+		pvc := &corev1.PersistentVolumeClaim{}
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(u, pvc); err != nil {
+			return nil, nil, nil, err
+		}
+
+		if storage, ok := pvc.Spec.Resources.Requests[corev1.ResourceStorage]; ok {
+			usage[corev1.ResourceRequestsStorage] = storage
+		}
+
+		return usage, nil, nil, nil
+	}
+
+	podSpecMap, replicas, ok := podSpecOf(u, mapping)
+	if !ok {
+		return usage, nil, nil, nil
+	}
+
+	podSpec := &corev1.PodSpec{}
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(podSpecMap, podSpec); err != nil {
+		return nil, nil, nil, err
+	}
+
+	requests, limits, lrErrs := podResources(podSpec, limitRanges)
+
+	// Requests of Pods count towards quota directly,
+	// pods of workloads are created later.
+	isPod := mapping.Resource.Resource == "pods"
+	target := usage
+
+	if !isPod {
+		podUsage = corev1.ResourceList{}
+		target = podUsage
+
+		addCount(podUsage, "pods", "", replicas)
+	}
+
+	for name, q := range requests {
+		q = q.DeepCopy()
+		q.Mul(replicas)
+		addResources(target, corev1.ResourceList{
+			name: q, corev1.ResourceName("requests." + string(name)): q,
+		})
+	}
+
+	for name, q := range limits {
+		q = q.DeepCopy()
+		q.Mul(replicas)
+		addResources(target, corev1.ResourceList{
+			corev1.ResourceName("limits." + string(name)): q,
+		})
+	}
+
+	return usage, podUsage, lrErrs, nil
+}
+
+// podSpecOf returns the pod spec of Pods and workloads with a pod template
+// and the number of pods that will be created from it.
+func podSpecOf(u map[string]any, mapping *meta.RESTMapping) (map[string]any, int64, bool) {
+	if mapping.Resource.Group == "" && mapping.Resource.Resource == "pods" {
+		spec, ok, _ := unstructured.NestedMap(u, "spec")
+
+		return spec, 1, ok
+	}
+
+	spec, ok, _ := unstructured.NestedMap(u, "spec", "template", "spec")
+	if !ok {
+		return nil, 0, false
+	}
+
+	replicas, found, err := unstructured.NestedInt64(u, "spec", "replicas")
+	if err != nil || !found {
+		replicas = 1
+	}
+
+	return spec, replicas, true
+}
+
+// podResources returns the effective requests and limits of a pod,
+// after defaults of LimitRanges have been applied.
+func podResources(
+	podSpec *corev1.PodSpec, limitRanges []corev1.LimitRange,
+) (requests, limits corev1.ResourceList, errs []error) {
+	requests, limits = corev1.ResourceList{}, corev1.ResourceList{}
+
+	for _, c := range podSpec.Containers {
+		r, l, cErrs := containerResources(c, limitRanges)
+		addResources(requests, r)
+		addResources(limits, l)
+
+		errs = append(errs, cErrs...)
+	}
+
+	// Init containers run sequentially before other containers.
+	for _, c := range podSpec.InitContainers {
+		r, l, cErrs := containerResources(c, limitRanges)
+		maxResources(requests, r)
+		maxResources(limits, l)
+
+		errs = append(errs, cErrs...)
+	}
+
+	return requests, limits, errs
+}
+
+func containerResources(
+	c corev1.Container, limitRanges []corev1.LimitRange,
+) (requests, limits corev1.ResourceList, errs []error) {
+	requests, limits = c.Resources.Requests.DeepCopy(), c.Resources.Limits.DeepCopy()
+	if requests == nil {
+		requests = corev1.ResourceList{}
+	}
+
+	if limits == nil {
+		limits = corev1.ResourceList{}
+	}
+
+	// API defaulting sets requests to explicit limits
+	// before LimitRange defaults are applied.
+	for name, q := range limits {
+		if _, ok := requests[name]; !ok {
+			requests[name] = q
+		}
+	}
+
+	for _, lr := range limitRanges {
+		for _, item := range lr.Spec.Limits {
+			if item.Type != corev1.LimitTypeContainer {
+				continue
+			}
+
+			for name, q := range item.DefaultRequest {
+				if _, ok := requests[name]; !ok {
+					requests[name] = q
+				}
+			}
+
+			// DefaultRequest defaults to Default.
+			for name, q := range item.Default {
+				if _, ok := limits[name]; !ok {
+					limits[name] = q
+				}
+
+				if _, ok := requests[name]; !ok {
+					requests[name] = q
+				}
+			}
+		}
+	}
+
+	for _, lr := range limitRanges {
+		for _, item := range lr.Spec.Limits {
+			if item.Type != corev1.LimitTypeContainer {
+				continue
+			}
+
+			for name, minQ := range item.Min {
+				if q, ok := requests[name]; ok && q.Cmp(minQ) < 0 {
+					errs = append(errs, LimitRangeViolationError{
+						LimitRange: lr.Name, Container: c.Name, Resource: name,
+						Message: fmt.Sprintf("request %s below minimum %s", q.String(), minQ.String()),
+					})
+				}
+			}
+
+			for name, maxQ := range item.Max {
+				if q, ok := limits[name]; ok && q.Cmp(maxQ) > 0 {
+					errs = append(errs, LimitRangeViolationError{
+						LimitRange: lr.Name, Container: c.Name, Resource: name,
+						Message: fmt.Sprintf("limit %s above maximum %s", q.String(), maxQ.String()),
+					})
+				}
+			}
+		}
+	}
+
+	return requests, limits, errs
+}
+
+func checkQuota(quota corev1.ResourceQuota, usage corev1.ResourceList) []QuotaExceededError {
+	if len(quota.Spec.Scopes) > 0 || quota.Spec.ScopeSelector != nil {
+		// Scoped quotas only apply to a subset of objects.
+		return nil
+	}
+
+	var errs []QuotaExceededError
+
+	for _, name := range sortedKeys(quota.Status.Hard) {
+		hard := quota.Status.Hard[name]
+
+		requested, ok := usage[name]
+		if !ok {
+			continue
+		}
+
+		used := quota.Status.Used[name]
+
+		total := used.DeepCopy()
+		total.Add(requested)
+
+		if total.Cmp(hard) > 0 {
+			errs = append(errs, QuotaExceededError{
+				Namespace: quota.Namespace,
+				Quota:     quota.Name,
+				Resource:  name,
+				Requested: requested,
+				Used:      used,
+				Hard:      hard,
+			})
+		}
+	}
+
+	return errs
+}
+
+func addCount(usage corev1.ResourceList, resourceName, group string, count int64) {
+	key := "count/" + resourceName
+	if len(group) > 0 {
+		key += "." + group
+	}
+
+	names := []corev1.ResourceName{corev1.ResourceName(key)}
+	if len(group) == 0 && slices.Contains(legacyCountResources, resourceName) {
+		names = append(names, corev1.ResourceName(resourceName))
+	}
+
+	for _, name := range names {
+		addResources(usage, corev1.ResourceList{name: *resource.NewQuantity(count, resource.DecimalSI)})
+	}
+}
+
+func addResources(dst, src corev1.ResourceList) {
+	for name, q := range src {
+		if existing, ok := dst[name]; ok {
+			existing.Add(q)
+			dst[name] = existing
+
+			continue
+		}
+
+		dst[name] = q.DeepCopy()
+	}
+}
+
+func maxResources(dst, src corev1.ResourceList) {
+	for name, q := range src {
+		if existing, ok := dst[name]; !ok || q.Cmp(existing) > 0 {
+			dst[name] = q.DeepCopy()
+		}
+	}
+}
+
+func sortedKeys[K ~string, V any](m map[K]V) []K {
+	keys := make([]K, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+
+	slices.Sort(keys)
+
+	return keys
+}
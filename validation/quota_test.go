@@ -0,0 +1,272 @@
+package validation
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	apimachineryerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"pkg.package-operator.run/boxcutter/internal/testutil"
+	"pkg.package-operator.run/boxcutter/machinery/types"
+)
+
+var (
+	deploymentMapping = &meta.RESTMapping{
+		Resource: schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "deployments"},
+		Scope:    meta.RESTScopeNamespace,
+	}
+	configMapMapping = &meta.RESTMapping{
+		Resource: schema.GroupVersionResource{Version: "v1", Resource: "configmaps"},
+		Scope:    meta.RESTScopeNamespace,
+	}
+)
+
+func newTestDeployment(replicas int64, resources map[string]any) *unstructured.Unstructured {
+	return &unstructured.Unstructured{Object: map[string]any{
+		"apiVersion": "apps/v1",
+		"kind":       "Deployment",
+		"metadata": map[string]any{
+			"name":      "test",
+			"namespace": "test",
+		},
+		"spec": map[string]any{
+			"replicas": replicas,
+			"template": map[string]any{
+				"spec": map[string]any{
+					"containers": []any{
+						map[string]any{
+							"name":      "app",
+							"resources": resources,
+						},
+					},
+				},
+			},
+		},
+	}}
+}
+
+func TestQuotaUsage(t *testing.T) {
+	t.Parallel()
+
+	limitRanges := []corev1.LimitRange{{
+		ObjectMeta: metav1.ObjectMeta{Name: "defaults"},
+		Spec: corev1.LimitRangeSpec{Limits: []corev1.LimitRangeItem{{
+			Type: corev1.LimitTypeContainer,
+			Default: corev1.ResourceList{
+				corev1.ResourceMemory: resource.MustParse("256Mi"),
+			},
+			DefaultRequest: corev1.ResourceList{
+				corev1.ResourceCPU: resource.MustParse("100m"),
+			},
+			Max: corev1.ResourceList{
+				corev1.ResourceCPU: resource.MustParse("1"),
+			},
+		}}},
+	}}
+
+	tests := []struct {
+		name             string
+		obj              client.Object
+		mapping          *meta.RESTMapping
+		expectedUsage    map[corev1.ResourceName]string
+		expectedPodUsage map[corev1.ResourceName]string
+		expectedErrors   []error
+	}{
+		{
+			name:    "configmap",
+			obj:     &unstructured.Unstructured{Object: map[string]any{"apiVersion": "v1", "kind": "ConfigMap"}},
+			mapping: configMapMapping,
+			expectedUsage: map[corev1.ResourceName]string{
+				"count/configmaps": "1",
+				"configmaps":       "1",
+			},
+		},
+		{
+			name:    "deployment with defaults",
+			obj:     newTestDeployment(3, nil),
+			mapping: deploymentMapping,
+			expectedUsage: map[corev1.ResourceName]string{
+				"count/deployments.apps": "1",
+			},
+			expectedPodUsage: map[corev1.ResourceName]string{
+				"count/pods":      "3",
+				"pods":            "3",
+				"cpu":             "300m",
+				"requests.cpu":    "300m",
+				"memory":          "768Mi",
+				"requests.memory": "768Mi",
+				"limits.memory":   "768Mi",
+			},
+		},
+		{
+			name: "deployment above max",
+			obj: newTestDeployment(1, map[string]any{
+				"limits": map[string]any{"cpu": "2"},
+			}),
+			mapping: deploymentMapping,
+			expectedUsage: map[corev1.ResourceName]string{
+				"count/deployments.apps": "1",
+			},
+			expectedPodUsage: map[corev1.ResourceName]string{
+				"count/pods":      "1",
+				"pods":            "1",
+				"cpu":             "2",
+				"requests.cpu":    "2",
+				"limits.cpu":      "2",
+				"memory":          "256Mi",
+				"requests.memory": "256Mi",
+				"limits.memory":   "256Mi",
+			},
+			expectedErrors: []error{LimitRangeViolationError{
+				LimitRange: "defaults", Container: "app", Resource: corev1.ResourceCPU,
+				Message: "limit 2 above maximum 1",
+			}},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			usage, podUsage, errs, err := quotaUsage(test.obj, test.mapping, limitRanges)
+			require.NoError(t, err)
+			assert.Equal(t, test.expectedErrors, errs)
+
+			toStrings := func(l corev1.ResourceList) map[corev1.ResourceName]string {
+				if l == nil {
+					return nil
+				}
+
+				out := map[corev1.ResourceName]string{}
+				for name, q := range l {
+					out[name] = q.String()
+				}
+
+				return out
+			}
+
+			assert.Equal(t, test.expectedUsage, toStrings(usage))
+			assert.Equal(t, test.expectedPodUsage, toStrings(podUsage))
+		})
+	}
+}
+
+func TestCheckQuota(t *testing.T) {
+	t.Parallel()
+
+	quota := corev1.ResourceQuota{
+		ObjectMeta: metav1.ObjectMeta{Name: "compute", Namespace: "test"},
+		Status: corev1.ResourceQuotaStatus{
+			Hard: corev1.ResourceList{
+				corev1.ResourceRequestsCPU: resource.MustParse("1"),
+				corev1.ResourcePods:        resource.MustParse("10"),
+			},
+			Used: corev1.ResourceList{
+				corev1.ResourceRequestsCPU: resource.MustParse("800m"),
+				corev1.ResourcePods:        resource.MustParse("2"),
+			},
+		},
+	}
+
+	errs := checkQuota(quota, corev1.ResourceList{
+		corev1.ResourceRequestsCPU: resource.MustParse("300m"),
+		corev1.ResourcePods:        resource.MustParse("3"),
+	})
+	assert.Equal(t, []QuotaExceededError{{
+		Namespace: "test",
+		Quota:     "compute",
+		Resource:  corev1.ResourceRequestsCPU,
+		Requested: resource.MustParse("300m"),
+		Used:      resource.MustParse("800m"),
+		Hard:      resource.MustParse("1"),
+	}}, errs)
+
+	quota.Spec.Scopes = []corev1.ResourceQuotaScope{corev1.ResourceQuotaScopeBestEffort}
+	assert.Empty(t, checkQuota(quota, corev1.ResourceList{
+		corev1.ResourceRequestsCPU: resource.MustParse("300m"),
+	}))
+}
+
+func TestPhaseValidator_Quota(t *testing.T) {
+	t.Parallel()
+
+	restMapper := &mockRestMapper{}
+	restMapper.
+		On("RESTMapping", schema.GroupKind{Group: "apps", Kind: "Deployment"}, mock.Anything).
+		Return(deploymentMapping, nil)
+	restMapper.
+		On("RESTMapping", schema.GroupKind{Kind: "ConfigMap"}, mock.Anything).
+		Return(configMapMapping, nil)
+
+	existing := &unstructured.Unstructured{}
+	existing.SetAPIVersion("v1")
+	existing.SetKind("ConfigMap")
+	existing.SetName("existing")
+	existing.SetNamespace("test")
+
+	reader := testutil.NewClient()
+	reader.
+		On("Get", mock.Anything, client.ObjectKeyFromObject(existing), mock.Anything, mock.Anything).
+		Return(nil)
+	reader.
+		On("Get", mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+		Return(apimachineryerrors.NewNotFound(schema.GroupResource{}, ""))
+	reader.
+		On("List", mock.Anything, mock.AnythingOfType("*v1.ResourceQuotaList"), mock.Anything).
+		Run(func(args mock.Arguments) {
+			list := args.Get(1).(*corev1.ResourceQuotaList)
+			list.Items = []corev1.ResourceQuota{{
+				ObjectMeta: metav1.ObjectMeta{Name: "compute", Namespace: "test"},
+				Status: corev1.ResourceQuotaStatus{
+					Hard: corev1.ResourceList{
+						corev1.ResourcePods:       resource.MustParse("2"),
+						corev1.ResourceConfigMaps: resource.MustParse("1"),
+					},
+					Used: corev1.ResourceList{
+						corev1.ResourceConfigMaps: resource.MustParse("1"),
+					},
+				},
+			}}
+		}).
+		Return(nil)
+	reader.
+		On("List", mock.Anything, mock.AnythingOfType("*v1.LimitRangeList"), mock.Anything).
+		Return(nil)
+
+	ov := &mockObjectValidator{}
+	ov.On("Validate", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+	v := newPhaseValidator(ov, WithQuotaValidation(NewQuotaValidator(restMapper, reader)))
+
+	newConfigMap := existing.DeepCopy()
+	newConfigMap.SetName("new")
+	deploy := newTestDeployment(3, nil)
+
+	err := v.Validate(t.Context(), types.NewPhase("phase1", []client.Object{
+		deploy, existing, newConfigMap,
+	}))
+
+	var perr *PhaseValidationError
+	require.ErrorAs(t, err, &perr)
+	assert.Empty(t, perr.Objects)
+
+	var qerr QuotaExceededError
+	require.ErrorAs(t, perr.PhaseError, &qerr)
+	assert.Equal(t, corev1.ResourceConfigMaps, qerr.Resource)
+	assert.Equal(t, "1", qerr.Requested.String())
+
+	// Pods are created later by the Deployment controller.
+	assert.Equal(t, []Warning{{
+		ObjectRef: types.ToObjectRef(deploy),
+		Message:   "pods may be rejected: exceeded quota test/compute: requested pods=3, used 0, limited 2",
+	}}, perr.Warnings)
+}
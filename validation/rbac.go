@@ -0,0 +1,263 @@
+package validation
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	authorizationv1 "k8s.io/api/authorization/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/utils/clock"
+	"k8s.io/utils/lru"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	bctypes "pkg.package-operator.run/boxcutter/machinery/types"
+)
+
+// DefaultPermissionCacheTTL is the duration SelfSubjectAccessReview
+// answers are cached before they are requested again.
+const DefaultPermissionCacheTTL = time.Minute
+
+// DefaultPermissionCacheSize is the maximum number of
+// SelfSubjectAccessReview answers cached.
+const DefaultPermissionCacheSize = 1024
+
+// permissionVerbs are the verbs the engines need on every object:
+// get, list and watch for the cache and create, patch and delete for writes.
+var permissionVerbs = []string{"get", "list", "watch", "create", "patch", "delete"}
+
+// cacheVerbs are the verbs used by informers at the scope of the cache.
+var cacheVerbs = map[string]bool{"list": true, "watch": true}
+
+// PermissionValidator checks via SelfSubjectAccessReviews that the identity
+// of the given writer is permitted to manage objects, before any write is attempted.
+// Use the same writer the engines use, so impersonation
+// e.g. via ObjectBoundAccessManager is respected.
+//
+// list and watch are checked cluster-wide, as needed by a cache without
+// DefaultNamespaces, unless WithNamespacedCache is given.
+//
+// Answers are cached per verb, resource and namespace for DefaultPermissionCacheTTL,
+// keeping at most DefaultPermissionCacheSize answers. Use one PermissionValidator per identity.
+// PermissionValidator is safe for concurrent use.
+type PermissionValidator struct {
+	restMapper restMapper
+	writer     client.Writer
+	clock      clock.PassiveClock
+	ttl        time.Duration
+
+	// namespacedCache checks list and watch in the namespace of objects.
+	namespacedCache bool
+
+	cache *lru.Cache
+}
+
+// PermissionValidatorOption configures a PermissionValidator.
+type PermissionValidatorOption interface {
+	ApplyToPermissionValidator(v *PermissionValidator)
+}
+
+type permissionValidatorOptionFn func(v *PermissionValidator)
+
+// ApplyToPermissionValidator implements PermissionValidatorOption.
+func (fn permissionValidatorOptionFn) ApplyToPermissionValidator(v *PermissionValidator) {
+	fn(v)
+}
+
+// WithNamespacedCache checks list and watch permissions in the namespace of objects,
+// instead of cluster-wide. Use when the cache of the engines is restricted
+// to the namespaces of the objects, e.g. via cache.Options.DefaultNamespaces.
+func WithNamespacedCache() PermissionValidatorOption {
+	return permissionValidatorOptionFn(func(v *PermissionValidator) {
+		v.namespacedCache = true
+	})
+}
+
+type permissionCacheKey struct {
+	verb, group, resource, namespace string
+}
+
+type permissionCacheEntry struct {
+	allowed bool
+	checked time.Time
+}
+
+// NewPermissionValidator returns a new PermissionValidator instance.
+func NewPermissionValidator(
+	restMapper restMapper,
+	writer client.Writer,
+	opts ...PermissionValidatorOption,
+) *PermissionValidator {
+	v := &PermissionValidator{
+		restMapper: restMapper,
+		writer:     writer,
+		clock:      clock.RealClock{},
+		ttl:        DefaultPermissionCacheTTL,
+		cache:      lru.New(DefaultPermissionCacheSize),
+	}
+
+	for _, opt := range opts {
+		opt.ApplyToPermissionValidator(v)
+	}
+
+	return v
+}
+
+// Validate checks that all verbs needed by the engines are allowed for the given object.
+// The function returns nil, if no permissions are missing.
+// It returns an ObjectValidationError when it was successfully able to validate the Object.
+// It returns a different error when unable to validate the object.
+func (v *PermissionValidator) Validate(ctx context.Context, obj client.Object) error {
+	errs, err := v.validate(ctx, obj)
+	if err != nil {
+		return err
+	}
+
+	return NewObjectValidationError(bctypes.ToObjectRef(obj), errs...)
+}
+
+func (v *PermissionValidator) validate(ctx context.Context, obj client.Object) ([]error, error) {
+	gvk := obj.GetObjectKind().GroupVersionKind()
+	if len(gvk.Kind) == 0 || len(gvk.Version) == 0 {
+		// Reported by validateObjectMetadata.
+		return nil, nil
+	}
+
+	mapping, err := v.restMapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+	if meta.IsNoMatchError(err) {
+		// API does not exist in the cluster, reported by dry run.
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	var namespace string
+	if mapping.Scope.Name() == meta.RESTScopeNameNamespace {
+		namespace = obj.GetNamespace()
+	}
+
+	// Missing verbs by namespace they are checked in.
+	var rules []MissingPermissionRule
+
+	for _, verb := range permissionVerbs {
+		key := permissionCacheKey{
+			verb:      verb,
+			group:     mapping.Resource.Group,
+			resource:  mapping.Resource.Resource,
+			namespace: namespace,
+		}
+		if cacheVerbs[verb] && !v.namespacedCache {
+			key.namespace = ""
+		}
+
+		allowed, err := v.allowed(ctx, key)
+		if err != nil {
+			return nil, err
+		}
+
+		if !allowed {
+			rules = addMissingPermission(rules, key)
+		}
+	}
+
+	if len(rules) == 0 {
+		return nil, nil
+	}
+
+	return []error{MissingPermissionsError{Rules: rules}}, nil
+}
+
+// addMissingPermission adds the verb of key to the rule of its namespace.
+func addMissingPermission(rules []MissingPermissionRule, key permissionCacheKey) []MissingPermissionRule {
+	for i := range rules {
+		if rules[i].Namespace == key.namespace {
+			rules[i].Verbs = append(rules[i].Verbs, key.verb)
+
+			return rules
+		}
+	}
+
+	return append(rules, MissingPermissionRule{
+		Verbs:     []string{key.verb},
+		APIGroup:  key.group,
+		Resource:  key.resource,
+		Namespace: key.namespace,
+	})
+}
+
+func (v *PermissionValidator) allowed(ctx context.Context, key permissionCacheKey) (bool, error) {
+	if e, ok := v.cache.Get(key); ok {
+		entry := e.(permissionCacheEntry)
+		if v.clock.Since(entry.checked) <= v.ttl {
+			return entry.allowed, nil
+		}
+	}
+
+	ssar := &authorizationv1.SelfSubjectAccessReview{
+		Spec: authorizationv1.SelfSubjectAccessReviewSpec{
+			ResourceAttributes: &authorizationv1.ResourceAttributes{
+				Verb:      key.verb,
+				Group:     key.group,
+				Resource:  key.resource,
+				Namespace: key.namespace,
+			},
+		},
+	}
+	if err := v.writer.Create(ctx, ssar); err != nil {
+		return false, fmt.Errorf("creating SelfSubjectAccessReview: %w", err)
+	}
+
+	v.cache.Add(key, permissionCacheEntry{
+		allowed: ssar.Status.Allowed,
+		checked: v.clock.Now(),
+	})
+
+	return ssar.Status.Allowed, nil
+}
+
+// MissingPermissionsError is returned when the identity
+// managing an object lacks RBAC permissions for it.
+type MissingPermissionsError struct {
+	// Rules that would need to be granted.
+	Rules []MissingPermissionRule
+}
+
+// Error implements the error interface.
+func (e MissingPermissionsError) Error() string {
+	rules := make([]string, len(e.Rules))
+	for i, r := range e.Rules {
+		rules[i] = r.String()
+	}
+
+	return "missing permissions: " + strings.Join(rules, ", ")
+}
+
+// MissingPermissionRule describes verbs missing on a resource.
+type MissingPermissionRule struct {
+	// Verbs not permitted.
+	Verbs []string
+	// APIGroup of the resource, empty for the core group.
+	APIGroup string
+	// Resource name, e.g. "deployments".
+	Resource string
+	// Namespace the verbs are missing in, empty for cluster-scoped resources.
+	Namespace string
+}
+
+// String returns a human readable description of the rule.
+func (r MissingPermissionRule) String() string {
+	resource := r.Resource
+	if len(r.APIGroup) > 0 {
+		resource += "." + r.APIGroup
+	}
+
+	s := fmt.Sprintf("[%s] on %s", strings.Join(r.Verbs, " "), resource)
+	if len(r.Namespace) > 0 {
+		s += fmt.Sprintf(" in namespace %q", r.Namespace)
+	}
+
+	return s
+}
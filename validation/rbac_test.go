@@ -0,0 +1,219 @@
+package validation
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	clocktesting "k8s.io/utils/clock/testing"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"pkg.package-operator.run/boxcutter/machinery/types"
+)
+
+func TestPermissionValidator(t *testing.T) {
+	t.Parallel()
+
+	restMapper := &mockRestMapper{}
+	restMapper.
+		On("RESTMapping", schema.GroupKind{Group: "apps", Kind: "Deployment"}, []string{"v1"}).
+		Return(&meta.RESTMapping{
+			Resource: schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "deployments"},
+			Scope:    meta.RESTScopeNamespace,
+		}, nil)
+	restMapper.
+		On("RESTMapping", schema.GroupKind{Group: "example.com", Kind: "Widget"}, []string{"v1"}).
+		Return(nil, &meta.NoKindMatchError{})
+
+	writer := &mockWriter{}
+	writer.
+		On("Create", mock.Anything, mock.AnythingOfType("*v1.SelfSubjectAccessReview"), mock.Anything).
+		Run(func(args mock.Arguments) {
+			ssar := args.Get(1).(*authorizationv1.SelfSubjectAccessReview)
+			attrs := ssar.Spec.ResourceAttributes
+			ssar.Status.Allowed = attrs.Namespace == "allowed" ||
+				attrs.Verb == "get" || attrs.Verb == "list" || attrs.Verb == "watch"
+		}).
+		Return(nil)
+
+	clock := clocktesting.NewFakePassiveClock(time.Now())
+	v := NewPermissionValidator(restMapper, writer)
+	v.clock = clock
+
+	deploy := func(namespace string) client.Object {
+		obj := &unstructured.Unstructured{}
+		obj.SetAPIVersion("apps/v1")
+		obj.SetKind("Deployment")
+		obj.SetName("test")
+		obj.SetNamespace(namespace)
+
+		return obj
+	}
+
+	require.NoError(t, v.Validate(t.Context(), deploy("allowed")))
+	writer.AssertNumberOfCalls(t, "Create", 6)
+
+	denied := deploy("denied")
+	err := v.Validate(t.Context(), denied)
+
+	var oerr *ObjectValidationError
+	require.ErrorAs(t, err, &oerr)
+	assert.Equal(t, types.ToObjectRef(denied), oerr.ObjectRef)
+	assert.Equal(t, []error{MissingPermissionsError{Rules: []MissingPermissionRule{{
+		Verbs:     []string{"create", "patch", "delete"},
+		APIGroup:  "apps",
+		Resource:  "deployments",
+		Namespace: "denied",
+	}}}}, oerr.Errors)
+	assert.Equal(t,
+		`missing permissions: [create patch delete] on deployments.apps in namespace "denied"`,
+		oerr.Errors[0].Error())
+	// list and watch are checked cluster-wide once.
+	writer.AssertNumberOfCalls(t, "Create", 10)
+
+	// Answers are cached.
+	require.Error(t, v.Validate(t.Context(), denied))
+	writer.AssertNumberOfCalls(t, "Create", 10)
+
+	// Until they expire.
+	clock.SetTime(clock.Now().Add(DefaultPermissionCacheTTL + time.Second))
+	require.Error(t, v.Validate(t.Context(), denied))
+	writer.AssertNumberOfCalls(t, "Create", 16)
+
+	// APIs not served are skipped.
+	widget := &unstructured.Unstructured{}
+	widget.SetAPIVersion("example.com/v1")
+	widget.SetKind("Widget")
+	require.NoError(t, v.Validate(t.Context(), widget))
+}
+
+func TestPermissionValidator_CacheScope(t *testing.T) {
+	t.Parallel()
+
+	restMapper := &mockRestMapper{}
+	restMapper.
+		On("RESTMapping", mock.Anything, mock.Anything).
+		Return(&meta.RESTMapping{
+			Resource: schema.GroupVersionResource{Version: "v1", Resource: "configmaps"},
+			Scope:    meta.RESTScopeNamespace,
+		}, nil)
+
+	// Only namespaced permissions are granted.
+	writer := &mockWriter{}
+	writer.
+		On("Create", mock.Anything, mock.AnythingOfType("*v1.SelfSubjectAccessReview"), mock.Anything).
+		Run(func(args mock.Arguments) {
+			ssar := args.Get(1).(*authorizationv1.SelfSubjectAccessReview)
+			ssar.Status.Allowed = ssar.Spec.ResourceAttributes.Namespace == "test"
+		}).
+		Return(nil)
+
+	obj := newTestObject("v1", "ConfigMap")
+
+	tests := []struct {
+		name string
+		opts []PermissionValidatorOption
+		err  error
+	}{
+		{
+			name: "cluster-wide cache",
+			err: MissingPermissionsError{Rules: []MissingPermissionRule{{
+				Verbs:    []string{"list", "watch"},
+				Resource: "configmaps",
+			}}},
+		},
+		{
+			name: "namespaced cache",
+			opts: []PermissionValidatorOption{WithNamespacedCache()},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := NewPermissionValidator(restMapper, writer, test.opts...).Validate(t.Context(), obj)
+			if test.err == nil {
+				require.NoError(t, err)
+
+				return
+			}
+
+			var oerr *ObjectValidationError
+			require.ErrorAs(t, err, &oerr)
+			assert.Equal(t, []error{test.err}, oerr.Errors)
+		})
+	}
+}
+
+func TestPermissionValidator_CacheSize(t *testing.T) {
+	t.Parallel()
+
+	restMapper := &mockRestMapper{}
+	restMapper.
+		On("RESTMapping", mock.Anything, mock.Anything).
+		Return(&meta.RESTMapping{
+			Resource: schema.GroupVersionResource{Version: "v1", Resource: "configmaps"},
+			Scope:    meta.RESTScopeNamespace,
+		}, nil)
+
+	writer := &mockWriter{}
+	writer.
+		On("Create", mock.Anything, mock.AnythingOfType("*v1.SelfSubjectAccessReview"), mock.Anything).
+		Return(nil)
+
+	v := NewPermissionValidator(restMapper, writer)
+
+	for i := range DefaultPermissionCacheSize {
+		obj := newTestObject("v1", "ConfigMap")
+		obj.SetNamespace(fmt.Sprintf("test-%d", i))
+		require.Error(t, v.Validate(t.Context(), obj))
+	}
+
+	assert.Equal(t, DefaultPermissionCacheSize, v.cache.Len())
+}
+
+func TestPhaseValidator_PermissionValidation(t *testing.T) {
+	t.Parallel()
+
+	restMapper := &mockRestMapper{}
+	restMapper.
+		On("RESTMapping", mock.Anything, mock.Anything).
+		Return(&meta.RESTMapping{
+			Resource: schema.GroupVersionResource{Version: "v1", Resource: "configmaps"},
+			Scope:    meta.RESTScopeNamespace,
+		}, nil)
+
+	writer := &mockWriter{}
+	writer.
+		On("Create", mock.Anything, mock.AnythingOfType("*v1.SelfSubjectAccessReview"), mock.Anything).
+		Return(nil)
+
+	ov := &mockObjectValidator{}
+	ov.On("Validate", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+	v := newPhaseValidator(ov, WithPermissionValidation(NewPermissionValidator(restMapper, writer)))
+
+	obj := &unstructured.Unstructured{}
+	obj.SetAPIVersion("v1")
+	obj.SetKind("ConfigMap")
+	obj.SetName("test")
+	obj.SetNamespace("test")
+
+	err := v.Validate(t.Context(), types.NewPhase("phase", []client.Object{obj}))
+
+	var perr *PhaseValidationError
+	require.ErrorAs(t, err, &perr)
+	require.Len(t, perr.Objects, 1)
+	assert.ErrorAs(t, perr.Objects[0].Errors[0], &MissingPermissionsError{})
+
+	// No dry run with missing permissions.
+	ov.AssertNotCalled(t, "Validate", mock.Anything, mock.Anything, mock.Anything)
+}
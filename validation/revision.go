@@ -3,8 +3,11 @@ package validation
 import (
 	"context"
 	"errors"
+	"fmt"
 	"slices"
 
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
 	"pkg.package-operator.run/boxcutter/machinery/types"
 )
 
@@ -14,16 +17,85 @@ import (
 // It performes less detailed checks than ObjectValidator or PhaseValidator
 // as detailed checks (using e.g. dry run) should only be run right before
 // a phase is installed to prevent false positives.
-type RevisionValidator struct{}
+type RevisionValidator struct {
+	schemaValidator    *SchemaValidator    // may be nil
+	collisionValidator *CollisionValidator // may be nil
+	policies           []Policy
+	sizeLimits         SizeLimits
+}
+
+// RevisionValidatorOption configures a RevisionValidator.
+type RevisionValidatorOption interface {
+	ApplyToRevisionValidator(v *RevisionValidator)
+}
+
+type revisionValidatorOptionFn func(v *RevisionValidator)
+
+// ApplyToRevisionValidator implements RevisionValidatorOption.
+func (fn revisionValidatorOptionFn) ApplyToRevisionValidator(v *RevisionValidator) {
+	fn(v)
+}
+
+// WithSchemaValidation validates all objects offline against their OpenAPI schema.
+func WithSchemaValidation(schemaValidator *SchemaValidator) RevisionValidatorOption {
+	return revisionValidatorOptionFn(func(v *RevisionValidator) {
+		v.schemaValidator = schemaValidator
+	})
+}
 
 // NewRevisionValidator returns a new RevisionValidator instance.
-func NewRevisionValidator() *RevisionValidator {
-	return &RevisionValidator{}
+func NewRevisionValidator(opts ...RevisionValidatorOption) *RevisionValidator {
+	v := &RevisionValidator{}
+	for _, opt := range opts {
+		opt.ApplyToRevisionValidator(v)
+	}
+
+	return v
 }
 
 // Validate a revision compromising of multiple phases.
-func (v *RevisionValidator) Validate(_ context.Context, rev types.Revision) error {
-	pvs := staticValidateMultiplePhases(rev.GetPhases()...)
+// It returns a RevisionValidationError when it was successfully able to validate the Revision.
+// It returns a different error when unable to validate the Revision.
+func (v *RevisionValidator) Validate(ctx context.Context, rev types.Revision) error {
+	pvs, err := v.staticValidateMultiplePhases(ctx, rev.GetPhases()...)
+	if err != nil {
+		return err
+	}
+
+	var total int
+
+	for _, phase := range rev.GetPhases() {
+		oErrs, size, err := v.sizeLimits.validatePhaseSize(phase)
+		if err != nil {
+			return err
+		}
+
+		total += size
+
+		if len(oErrs) > 0 {
+			pvs = mergePhaseValidationErrors(pvs, phase.GetName(), oErrs)
+		}
+	}
+
+	if v.collisionValidator != nil {
+		cpvs, err := v.collisionValidator.validate(ctx, rev)
+		if err != nil {
+			return err
+		}
+
+		for _, cpv := range cpvs {
+			pvs = mergePhaseValidationError(pvs, cpv)
+		}
+	}
+
+	if revErr := v.sizeLimits.validateTotalSize(total); revErr != nil {
+		return &RevisionValidationError{
+			RevisionName:   rev.GetName(),
+			RevisionNumber: rev.GetRevisionNumber(),
+			RevisionError:  revErr,
+			Phases:         pvs,
+		}
+	}
 
 	return NewRevisionValidationError(
 		rev.GetName(), rev.GetRevisionNumber(),
@@ -31,6 +103,70 @@ func (v *RevisionValidator) Validate(_ context.Context, rev types.Revision) erro
 	)
 }
 
+func (v *RevisionValidator) staticValidateMultiplePhases(
+	ctx context.Context, phases ...types.Phase,
+) ([]PhaseValidationError, error) {
+	pvs := staticValidateMultiplePhases(phases...)
+	if v.schemaValidator == nil && len(v.policies) == 0 {
+		return pvs, nil
+	}
+
+	for _, phase := range phases {
+		var objectErrors []ObjectValidationError
+
+		for _, obj := range phase.GetObjects() {
+			errs, err := v.validateObject(ctx, obj)
+			if err != nil {
+				return nil, fmt.Errorf("validating %s: %w", types.ToObjectRef(obj), err)
+			}
+
+			if len(errs) > 0 {
+				objectErrors = append(objectErrors, ObjectValidationError{
+					ObjectRef: types.ToObjectRef(obj),
+					Errors:    errs,
+				})
+			}
+		}
+
+		if len(objectErrors) > 0 {
+			pvs = mergePhaseValidationErrors(pvs, phase.GetName(), objectErrors)
+		}
+	}
+
+	return pvs, nil
+}
+
+func (v *RevisionValidator) validateObject(ctx context.Context, obj client.Object) ([]error, error) {
+	var errs []error
+
+	if v.schemaValidator != nil {
+		schemaErrs, err := v.schemaValidator.validate(ctx, obj)
+		if err != nil {
+			return nil, err
+		}
+
+		errs = append(errs, schemaErrs...)
+	}
+
+	policyErrs, err := validatePolicies(ctx, v.policies, obj)
+	if err != nil {
+		return nil, err
+	}
+
+	return append(errs, policyErrs...), nil
+}
+
+// mergePhaseValidationErrors adds the given object errors to the
+// PhaseValidationError of the given phase, merging errors of the same object.
+func mergePhaseValidationErrors(
+	pvs []PhaseValidationError, phaseName string, oErrs []ObjectValidationError,
+) []PhaseValidationError {
+	return mergePhaseValidationError(pvs, PhaseValidationError{
+		PhaseName: phaseName,
+		Objects:   oErrs,
+	})
+}
+
 func staticValidateMultiplePhases(phases ...types.Phase) []PhaseValidationError {
 	dups := checkForObjectDuplicates(phases...)
 
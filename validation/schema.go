@@ -0,0 +1,457 @@
+package validation
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/sync/singleflight"
+	"k8s.io/apiextensions-apiserver/pkg/apis/apiextensions"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	structuralschema "k8s.io/apiextensions-apiserver/pkg/apiserver/schema"
+	"k8s.io/apiextensions-apiserver/pkg/apiserver/schema/cel"
+	"k8s.io/apiextensions-apiserver/pkg/apiserver/schema/pruning"
+	apiextensionsvalidation "k8s.io/apiextensions-apiserver/pkg/apiserver/validation"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	celconfig "k8s.io/apiserver/pkg/apis/cel"
+	"k8s.io/client-go/openapi"
+	"k8s.io/client-go/openapi3"
+	"k8s.io/utils/clock"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	bctypes "pkg.package-operator.run/boxcutter/machinery/types"
+)
+
+// DefaultSchemaCacheTTL is the duration compiled OpenAPI schemas
+// are cached before they are fetched from the discovery client again.
+const DefaultSchemaCacheTTL = 5 * time.Minute
+
+type discoveryClient interface {
+	OpenAPIV3() openapi.Client
+}
+
+type openAPIAccessor interface {
+	GetAsMap(gv schema.GroupVersion) (map[string]any, error)
+}
+
+type defaultOpenAPIAccessor struct {
+	c openapi.Client
+}
+
+func (a *defaultOpenAPIAccessor) GetAsMap(gv schema.GroupVersion) (map[string]any, error) {
+	r := openapi3.NewRoot(a.c)
+
+	return r.GVSpecAsMap(gv)
+}
+
+// SchemaValidator validates objects offline against the OpenAPI v3 schemas
+// published by the kube-apiserver, without issuing dry-run requests.
+// It checks for unknown fields, types, required fields, enums
+// and CEL x-kubernetes-validations rules.
+//
+// Objects of APIs not served by the cluster are skipped, as their schema
+// may be part of the same revision, e.g. a CustomResourceDefinition
+// installed in an earlier phase.
+// SchemaValidator is safe for concurrent use.
+type SchemaValidator struct {
+	openAPIAccessor openAPIAccessor
+	clock           clock.PassiveClock
+	ttl             time.Duration
+
+	// flight deduplicates concurrent fetches and compiles.
+	flight singleflight.Group
+	mux    sync.Mutex
+	cache  map[schema.GroupVersion]*groupVersionSchemas
+}
+
+// NewSchemaValidator returns a new SchemaValidator instance.
+// Pass the same (cached) discovery client used for the Comparator,
+// to validate against the already fetched OpenAPI schemas.
+func NewSchemaValidator(discoveryClient discoveryClient) *SchemaValidator {
+	return &SchemaValidator{
+		openAPIAccessor: &defaultOpenAPIAccessor{
+			c: discoveryClient.OpenAPIV3(),
+		},
+		clock: clock.RealClock{},
+		ttl:   DefaultSchemaCacheTTL,
+		cache: map[schema.GroupVersion]*groupVersionSchemas{},
+	}
+}
+
+// Validate validates the given object against its OpenAPI schema.
+// The function returns nil, if no validation errors where found.
+// It returns an ObjectValidationError when it was successfully able to validate the Object.
+// It returns a different error when unable to validate the object.
+func (v *SchemaValidator) Validate(ctx context.Context, obj client.Object) error {
+	errs, err := v.validate(ctx, obj)
+	if err != nil {
+		return err
+	}
+
+	return NewObjectValidationError(bctypes.ToObjectRef(obj), errs...)
+}
+
+func (v *SchemaValidator) validate(ctx context.Context, obj client.Object) ([]error, error) {
+	gvk := obj.GetObjectKind().GroupVersionKind()
+	if len(gvk.Kind) == 0 || len(gvk.Version) == 0 {
+		// Reported by validateObjectMetadata.
+		return nil, nil
+	}
+
+	ks, err := v.kindSchema(gvk)
+	if err != nil {
+		return nil, fmt.Errorf("compiling OpenAPI schema for %s: %w", gvk, err)
+	}
+
+	if ks == nil {
+		// API not served by the cluster.
+		return nil, nil
+	}
+
+	u, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+	if err != nil {
+		return nil, err
+	}
+
+	// Just like the kube-apiserver, ignore null values.
+	pruneNulls(u)
+
+	var errs []error
+
+	// Pruning removes unknown fields, so all following checks
+	// only report issues with known fields.
+	pruneOpts := structuralschema.UnknownFieldPathOptions{TrackUnknownFieldPaths: true}
+	for _, p := range pruning.PruneWithOptions(u, ks.structural, true, pruneOpts) {
+		errs = append(errs, SchemaValidationError{FieldError: &field.Error{
+			Type:   field.ErrorTypeForbidden,
+			Field:  p,
+			Detail: "unknown field",
+		}})
+	}
+
+	for _, ferr := range apiextensionsvalidation.ValidateCustomResource(nil, u, ks.validator) {
+		errs = append(errs, SchemaValidationError{FieldError: ferr})
+	}
+
+	if ks.celValidator != nil {
+		celErrs, _ := ks.celValidator.Validate(
+			ctx, nil, ks.structural, u, nil, celconfig.RuntimeCELCostBudget)
+		for _, ferr := range celErrs {
+			errs = append(errs, SchemaValidationError{FieldError: ferr})
+		}
+	}
+
+	return errs, nil
+}
+
+// SchemaValidationError is returned when an object does not match its OpenAPI schema.
+type SchemaValidationError struct {
+	FieldError *field.Error
+}
+
+// Error implements the error interface.
+func (e SchemaValidationError) Error() string {
+	return e.FieldError.Error()
+}
+
+// Unwrap implements the Unwrap interface for errors.As and errors.Is.
+func (e SchemaValidationError) Unwrap() error {
+	return e.FieldError
+}
+
+type groupVersionSchemas struct {
+	fetched    time.Time
+	components map[string]any // nil when not served.
+	kinds      map[string]*kindSchema
+}
+
+type kindSchema struct {
+	validator    apiextensionsvalidation.SchemaValidator
+	structural   *structuralschema.Structural
+	celValidator *cel.Validator
+}
+
+// kindSchema returns the compiled schema for the given GVK
+// or nil, if the API is not served by the cluster.
+// Fetching and compiling happens outside of the lock,
+// concurrent calls for the same GroupVersion or Kind share a single fetch and compile.
+func (v *SchemaValidator) kindSchema(gvk schema.GroupVersionKind) (*kindSchema, error) {
+	gvs, err := v.groupVersionSchemas(gvk.GroupVersion())
+	if err != nil {
+		return nil, err
+	}
+
+	if gvs.components == nil {
+		return nil, nil
+	}
+
+	v.mux.Lock()
+	ks, ok := gvs.kinds[gvk.Kind]
+	v.mux.Unlock()
+
+	if ok {
+		return ks, nil
+	}
+
+	r, err, _ := v.flight.Do("kind:"+gvk.String(), func() (any, error) {
+		// components are never modified after fetch.
+		ks, err := gvs.compile(gvk)
+		if err != nil {
+			return nil, err
+		}
+
+		v.mux.Lock()
+		defer v.mux.Unlock()
+
+		gvs.kinds[gvk.Kind] = ks
+
+		return ks, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return r.(*kindSchema), nil
+}
+
+// groupVersionSchemas returns the cached schemas of the given GroupVersion,
+// fetching them again when missing or expired.
+func (v *SchemaValidator) groupVersionSchemas(gv schema.GroupVersion) (*groupVersionSchemas, error) {
+	v.mux.Lock()
+	gvs, ok := v.cache[gv]
+	v.mux.Unlock()
+
+	if ok && v.clock.Since(gvs.fetched) <= v.ttl {
+		return gvs, nil
+	}
+
+	r, err, _ := v.flight.Do("gv:"+gv.String(), func() (any, error) {
+		gvs, err := v.fetch(gv)
+		if err != nil {
+			return nil, err
+		}
+
+		v.mux.Lock()
+		defer v.mux.Unlock()
+
+		v.cache[gv] = gvs
+
+		return gvs, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return r.(*groupVersionSchemas), nil
+}
+
+func (v *SchemaValidator) fetch(gv schema.GroupVersion) (*groupVersionSchemas, error) {
+	gvs := &groupVersionSchemas{
+		fetched: v.clock.Now(),
+		kinds:   map[string]*kindSchema{},
+	}
+
+	doc, err := v.openAPIAccessor.GetAsMap(gv)
+
+	var nfErr *openapi3.GroupVersionNotFoundError
+
+	switch {
+	case errors.As(err, &nfErr):
+		return gvs, nil
+	case err != nil:
+		return nil, fmt.Errorf("API accessor: %w", err)
+	}
+
+	components, _ := doc["components"].(map[string]any)
+	gvs.components, _ = components["schemas"].(map[string]any)
+
+	return gvs, nil
+}
+
+// compile returns the compiled schema for the given GVK,
+// or nil if the GroupVersion does not contain the Kind.
+func (gvs *groupVersionSchemas) compile(gvk schema.GroupVersionKind) (*kindSchema, error) {
+	name, ok := findSchemaName(gvs.components, gvk)
+	if !ok {
+		return nil, nil
+	}
+
+	inlined := inlineSchemaRefs(gvs.components, gvs.components[name], map[string]bool{name: true})
+
+	b, err := json.Marshal(inlined)
+	if err != nil {
+		return nil, err
+	}
+
+	var v1Props apiextensionsv1.JSONSchemaProps
+	if err := json.Unmarshal(b, &v1Props); err != nil {
+		return nil, err
+	}
+
+	var props apiextensions.JSONSchemaProps
+	if err := apiextensionsv1.Convert_v1_JSONSchemaProps_To_apiextensions_JSONSchemaProps(
+		&v1Props, &props, nil); err != nil {
+		return nil, err
+	}
+
+	ks := &kindSchema{}
+
+	ks.validator, _, err = apiextensionsvalidation.NewSchemaValidator(&props)
+	if err != nil {
+		return nil, err
+	}
+
+	ks.structural, err = structuralschema.NewStructural(&props)
+	if err != nil {
+		return nil, fmt.Errorf("structural schema: %w", err)
+	}
+
+	ks.celValidator = cel.NewValidator(ks.structural, true, celconfig.PerCallLimit)
+
+	return ks, nil
+}
+
+// findSchemaName returns the name of the schema component for the given GVK.
+func findSchemaName(components map[string]any, gvk schema.GroupVersionKind) (string, bool) {
+	for name, s := range components {
+		sm, _ := s.(map[string]any)
+
+		gvks, ok := sm["x-kubernetes-group-version-kind"].([]any)
+		if !ok {
+			continue
+		}
+
+		for _, e := range gvks {
+			m, ok := e.(map[string]any)
+			if !ok {
+				continue
+			}
+
+			if m["group"] == gvk.Group && m["version"] == gvk.Version && m["kind"] == gvk.Kind {
+				return name, true
+			}
+		}
+	}
+
+	return "", false
+}
+
+const componentsSchemasPrefix = "#/components/schemas/"
+
+// inlineSchemaRefs replaces all $ref's within the given schema node with the
+// referenced component schema, so it can be used as a CRD validation schema.
+// Recursive references are replaced with a schema preserving unknown fields.
+func inlineSchemaRefs(components map[string]any, node any, visiting map[string]bool) any {
+	n, ok := node.(map[string]any)
+	if !ok {
+		return node
+	}
+
+	out := make(map[string]any, len(n))
+
+	for k, v := range n {
+		switch k {
+		case "default", "enum", "example", "x-kubernetes-validations":
+			// Values, not schemas.
+			out[k] = v
+
+		case "properties", "patternProperties":
+			props, _ := v.(map[string]any)
+			inlinedProps := make(map[string]any, len(props))
+
+			for pk, pv := range props {
+				inlinedProps[pk] = inlineSchemaRefs(components, pv, visiting)
+			}
+
+			out[k] = inlinedProps
+
+		case "allOf", "anyOf", "oneOf":
+			items, _ := v.([]any)
+			inlinedItems := make([]any, len(items))
+
+			for i, item := range items {
+				inlinedItems[i] = inlineSchemaRefs(components, item, visiting)
+			}
+
+			out[k] = inlinedItems
+
+		default:
+			out[k] = inlineSchemaRefs(components, v, visiting)
+		}
+	}
+
+	// allOf with a single element is used to attach
+	// sibling fields like default or description to a $ref.
+	if allOf, ok := out["allOf"].([]any); ok && len(allOf) == 1 {
+		delete(out, "allOf")
+
+		if m, ok := allOf[0].(map[string]any); ok {
+			for k, v := range m {
+				if _, exists := out[k]; !exists {
+					out[k] = v
+				}
+			}
+		}
+	}
+
+	if ref, ok := out["$ref"].(string); ok {
+		delete(out, "$ref")
+
+		name := strings.TrimPrefix(ref, componentsSchemasPrefix)
+
+		target, ok := components[name]
+		if visiting[name] || !ok {
+			// Recursive or unresolvable type, stop validating here.
+			out["x-kubernetes-preserve-unknown-fields"] = true
+		} else {
+			visiting[name] = true
+			resolved := inlineSchemaRefs(components, target, visiting).(map[string]any)
+			delete(visiting, name)
+
+			for k, v := range resolved {
+				if _, exists := out[k]; !exists {
+					out[k] = v
+				}
+			}
+		}
+	}
+
+	if out["format"] == "int-or-string" {
+		delete(out, "type")
+		delete(out, "format")
+		out["x-kubernetes-int-or-string"] = true
+	}
+
+	// Objects without declared fields, like runtime.RawExtension, accept any field.
+	_, hasProperties := out["properties"]
+	_, hasAdditionalProperties := out["additionalProperties"]
+
+	if out["type"] == "object" && !hasProperties && !hasAdditionalProperties {
+		out["x-kubernetes-preserve-unknown-fields"] = true
+	}
+
+	return out
+}
+
+func pruneNulls(m map[string]any) {
+	for k, v := range m {
+		switch tv := v.(type) {
+		case nil:
+			delete(m, k)
+		case map[string]any:
+			pruneNulls(tv)
+		case []any:
+			for _, item := range tv {
+				if im, ok := item.(map[string]any); ok {
+					pruneNulls(im)
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,270 @@
+package validation
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/client-go/openapi"
+	"k8s.io/client-go/openapi/openapitest"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"pkg.package-operator.run/boxcutter/machinery/types"
+)
+
+type openAPIDiscoveryClient struct {
+	c openapi.Client
+}
+
+func (c openAPIDiscoveryClient) OpenAPIV3() openapi.Client {
+	return c.c
+}
+
+func TestSchemaValidator(t *testing.T) {
+	t.Parallel()
+
+	builtin := NewSchemaValidator(openAPIDiscoveryClient{c: openapitest.NewEmbeddedFileClient()})
+	crd := NewSchemaValidator(openAPIDiscoveryClient{c: openapitest.NewFileClient("testdata/openapi")})
+
+	tests := []struct {
+		name      string
+		validator *SchemaValidator
+		obj       map[string]any
+		fields    []string
+	}{
+		{
+			name:      "valid builtin",
+			validator: builtin,
+			obj: map[string]any{
+				"apiVersion": "v1",
+				"kind":       "ConfigMap",
+				"metadata": map[string]any{
+					"name":              "test",
+					"creationTimestamp": nil,
+				},
+				"data": map[string]any{"a": "b"},
+			},
+		},
+		{
+			name:      "invalid builtin",
+			validator: builtin,
+			obj: map[string]any{
+				"apiVersion": "v1",
+				"kind":       "ConfigMap",
+				"metadata":   map[string]any{"name": "test"},
+				"data":       "banana",
+				"unknown":    true,
+			},
+			fields: []string{"unknown", "data"},
+		},
+		{
+			name:      "missing required",
+			validator: builtin,
+			obj: map[string]any{
+				"apiVersion": "apps/v1",
+				"kind":       "Deployment",
+				"metadata":   map[string]any{"name": "test"},
+				"spec": map[string]any{
+					"replicas": int64(1),
+				},
+			},
+			fields: []string{"spec.selector", "spec.template"},
+		},
+		{
+			name:      "valid custom resource",
+			validator: crd,
+			obj: map[string]any{
+				"apiVersion": "example.com/v1",
+				"kind":       "Widget",
+				"metadata":   map[string]any{"name": "test", "labels": map[string]any{"a": "b"}},
+				"spec": map[string]any{
+					"size": int64(3),
+					"mode": "Fast",
+					"port": "http",
+				},
+			},
+		},
+		{
+			name:      "invalid custom resource",
+			validator: crd,
+			obj: map[string]any{
+				"apiVersion": "example.com/v1",
+				"kind":       "Widget",
+				"metadata":   map[string]any{"name": "test"},
+				"spec": map[string]any{
+					"size":  int64(11),
+					"mode":  "Medium",
+					"port":  int64(80),
+					"color": "blue",
+				},
+			},
+			fields: []string{"spec.color", "spec.mode", "spec"},
+		},
+		{
+			name:      "not served",
+			validator: crd,
+			obj: map[string]any{
+				"apiVersion": "example.com/v2",
+				"kind":       "Widget",
+				"metadata":   map[string]any{"name": "test"},
+			},
+		},
+		{
+			name:      "unknown kind",
+			validator: crd,
+			obj: map[string]any{
+				"apiVersion": "example.com/v1",
+				"kind":       "Gadget",
+				"metadata":   map[string]any{"name": "test"},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			obj := &unstructured.Unstructured{Object: test.obj}
+
+			err := test.validator.Validate(t.Context(), obj)
+			if len(test.fields) == 0 {
+				require.NoError(t, err)
+
+				return
+			}
+
+			var oerr *ObjectValidationError
+			require.ErrorAs(t, err, &oerr)
+			assert.Equal(t, types.ToObjectRef(obj), oerr.ObjectRef)
+			assert.Equal(t, test.fields, fieldsFromErrs(t, oerr.Errors))
+		})
+	}
+}
+
+// blockingOpenAPIAccessor blocks fetches of the given GroupVersion until released.
+type blockingOpenAPIAccessor struct {
+	openAPIAccessor
+
+	block   schema.GroupVersion
+	started chan struct{}
+	release chan struct{}
+}
+
+func (a *blockingOpenAPIAccessor) GetAsMap(gv schema.GroupVersion) (map[string]any, error) {
+	if gv == a.block {
+		close(a.started)
+		<-a.release
+	}
+
+	return a.openAPIAccessor.GetAsMap(gv)
+}
+
+func TestSchemaValidator_FetchOutsideLock(t *testing.T) {
+	t.Parallel()
+
+	v := NewSchemaValidator(openAPIDiscoveryClient{c: openapitest.NewEmbeddedFileClient()})
+	accessor := &blockingOpenAPIAccessor{
+		openAPIAccessor: v.openAPIAccessor,
+		block:           schema.GroupVersion{Group: "apps", Version: "v1"},
+		started:         make(chan struct{}),
+		release:         make(chan struct{}),
+	}
+	v.openAPIAccessor = accessor
+
+	deploy := &unstructured.Unstructured{Object: map[string]any{
+		"apiVersion": "apps/v1",
+		"kind":       "Deployment",
+		"metadata":   map[string]any{"name": "test"},
+		"unknown":    true,
+	}}
+
+	errCh := make(chan error)
+
+	go func() {
+		errCh <- v.Validate(t.Context(), deploy)
+	}()
+
+	<-accessor.started
+
+	// Other APIs are validated while the fetch is in progress.
+	require.NoError(t, v.Validate(t.Context(), &unstructured.Unstructured{Object: map[string]any{
+		"apiVersion": "v1",
+		"kind":       "ConfigMap",
+		"metadata":   map[string]any{"name": "test"},
+	}}))
+
+	close(accessor.release)
+
+	var oerr *ObjectValidationError
+	require.ErrorAs(t, <-errCh, &oerr)
+	assert.Equal(t, []string{"unknown"}, fieldsFromErrs(t, oerr.Errors))
+}
+
+func TestInlineSchemaRefs(t *testing.T) {
+	t.Parallel()
+
+	components := map[string]any{
+		"Props": map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"items": map[string]any{"$ref": "#/components/schemas/Props"},
+			},
+		},
+	}
+
+	inlined := inlineSchemaRefs(components, map[string]any{
+		"description": "root",
+		"allOf":       []any{map[string]any{"$ref": "#/components/schemas/Props"}},
+	}, map[string]bool{})
+
+	assert.Equal(t, map[string]any{
+		"description": "root",
+		"type":        "object",
+		"properties": map[string]any{
+			"items": map[string]any{"x-kubernetes-preserve-unknown-fields": true},
+		},
+	}, inlined)
+}
+
+func TestRevisionValidator_SchemaValidation(t *testing.T) {
+	t.Parallel()
+
+	v := NewRevisionValidator(WithSchemaValidation(
+		NewSchemaValidator(openAPIDiscoveryClient{c: openapitest.NewEmbeddedFileClient()})))
+
+	invalid := &unstructured.Unstructured{Object: map[string]any{
+		"apiVersion": "v1",
+		"kind":       "ConfigMap",
+		"metadata":   map[string]any{"name": "test", "namespace": "test"},
+		"unknown":    true,
+	}}
+
+	err := v.Validate(t.Context(), types.NewRevision("test", 1, []types.Phase{
+		types.NewPhase("phase1", []client.Object{invalid}),
+	}))
+
+	var rerr *RevisionValidationError
+	require.ErrorAs(t, err, &rerr)
+	require.Len(t, rerr.Phases, 1)
+	assert.Equal(t, "phase1", rerr.Phases[0].PhaseName)
+	require.Len(t, rerr.Phases[0].Objects, 1)
+	assert.Equal(t, []string{"unknown"}, fieldsFromErrs(t, rerr.Phases[0].Objects[0].Errors))
+}
+
+func fieldsFromErrs(t *testing.T, errs []error) []string {
+	t.Helper()
+
+	fields := make([]string, 0, len(errs))
+
+	for _, err := range errs {
+		var ferr *field.Error
+		require.ErrorAs(t, err, &ferr)
+
+		fields = append(fields, ferr.Field)
+	}
+
+	return fields
+}
@@ -0,0 +1,96 @@
+package validation
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	apimachineryerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// ScopePolicy allows objects of a namespaced owner to leave the owner's namespace.
+// The owner's namespace is always allowed.
+type ScopePolicy struct {
+	// Namespaces objects may be placed in.
+	Namespaces []string
+	// NamespaceSelector allows all namespaces with matching labels.
+	// An empty selector does not match any namespace.
+	NamespaceSelector labels.Selector
+	// ClusterScoped lists cluster-scoped resources objects may be.
+	ClusterScoped []ClusterScopedRule
+}
+
+// ClusterScopedRule allows cluster-scoped objects of the given kind.
+type ClusterScopedRule struct {
+	GroupKind schema.GroupKind
+	// NamePrefix restricts allowed objects to names with this prefix.
+	NamePrefix string
+}
+
+// ScopePolicyFunc returns the ScopePolicy for objects of the given owner.
+// Returning nil restricts objects to the owner's namespace.
+type ScopePolicyFunc func(ctx context.Context, owner client.Object) (*ScopePolicy, error)
+
+// StaticScopePolicy returns a ScopePolicyFunc returning the same policy for all owners.
+func StaticScopePolicy(policy ScopePolicy) ScopePolicyFunc {
+	return func(context.Context, client.Object) (*ScopePolicy, error) {
+		return &policy, nil
+	}
+}
+
+func (p *ScopePolicy) allowsClusterScoped(obj client.Object) bool {
+	if p == nil {
+		return false
+	}
+
+	gk := obj.GetObjectKind().GroupVersionKind().GroupKind()
+
+	return slices.ContainsFunc(p.ClusterScoped, func(r ClusterScopedRule) bool {
+		return r.GroupKind == gk && strings.HasPrefix(obj.GetName(), r.NamePrefix)
+	})
+}
+
+func (p *ScopePolicy) allowsNamespace(
+	ctx context.Context, reader client.Reader, namespace string,
+) (bool, error) {
+	if p == nil {
+		return false, nil
+	}
+
+	if slices.Contains(p.Namespaces, namespace) {
+		return true, nil
+	}
+
+	if p.NamespaceSelector == nil || p.NamespaceSelector.Empty() {
+		return false, nil
+	}
+
+	ns := &corev1.Namespace{}
+
+	err := reader.Get(ctx, client.ObjectKey{Name: namespace}, ns)
+	if apimachineryerrors.IsNotFound(err) {
+		return false, nil
+	}
+
+	if err != nil {
+		return false, fmt.Errorf("getting namespace %q: %w", namespace, err)
+	}
+
+	return p.NamespaceSelector.Matches(labels.Set(ns.Labels)), nil
+}
+
+// NamespaceNotAllowedError is returned when an object
+// is placed in a namespace not allowed by the ScopePolicy.
+type NamespaceNotAllowedError struct {
+	Namespace string
+}
+
+// Error implements the error interface.
+func (e NamespaceNotAllowedError) Error() string {
+	return fmt.Sprintf("namespace %q not allowed by scope policy", e.Namespace)
+}
@@ -0,0 +1,182 @@
+package validation
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	apimachineryerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"pkg.package-operator.run/boxcutter/internal/testutil"
+	"pkg.package-operator.run/boxcutter/machinery/types"
+)
+
+func TestValidateNamespace_ScopePolicy(t *testing.T) {
+	t.Parallel()
+
+	policy := &ScopePolicy{
+		Namespaces:        []string{"shared"},
+		NamespaceSelector: labels.SelectorFromSet(labels.Set{"team": "a"}),
+		ClusterScoped: []ClusterScopedRule{
+			{GroupKind: schema.GroupKind{Group: "apiextensions.k8s.io", Kind: "CustomResourceDefinition"}},
+			{GroupKind: schema.GroupKind{Group: "rbac.authorization.k8s.io", Kind: "ClusterRole"}, NamePrefix: "team-a-"},
+		},
+	}
+
+	obj := func(apiVersion, kind, name, namespace string) client.Object {
+		u := &unstructured.Unstructured{}
+		u.SetAPIVersion(apiVersion)
+		u.SetKind(kind)
+		u.SetName(name)
+		u.SetNamespace(namespace)
+
+		return u
+	}
+
+	tests := []struct {
+		name          string
+		obj           client.Object
+		expectedError error
+	}{
+		{
+			name: "owner namespace",
+			obj:  obj("v1", "ConfigMap", "test", "owner"),
+		},
+		{
+			name: "listed namespace",
+			obj:  obj("v1", "ConfigMap", "test", "shared"),
+		},
+		{
+			name: "selected namespace",
+			obj:  obj("v1", "ConfigMap", "test", "team-a"),
+		},
+		{
+			name:          "unselected namespace",
+			obj:           obj("v1", "ConfigMap", "test", "team-b"),
+			expectedError: NamespaceNotAllowedError{Namespace: "team-b"},
+		},
+		{
+			name:          "missing namespace",
+			obj:           obj("v1", "ConfigMap", "test", "missing"),
+			expectedError: NamespaceNotAllowedError{Namespace: "missing"},
+		},
+		{
+			name: "allowed cluster-scoped kind",
+			obj:  obj("apiextensions.k8s.io/v1", "CustomResourceDefinition", "widgets.example.com", ""),
+		},
+		{
+			name: "allowed cluster-scoped name prefix",
+			obj:  obj("rbac.authorization.k8s.io/v1", "ClusterRole", "team-a-view", ""),
+		},
+		{
+			name:          "disallowed cluster-scoped name prefix",
+			obj:           obj("rbac.authorization.k8s.io/v1", "ClusterRole", "cluster-admin", ""),
+			expectedError: MustBeNamespaceScopedResourceError{},
+		},
+		{
+			name:          "disallowed cluster-scoped kind",
+			obj:           obj("v1", "Namespace", "test", ""),
+			expectedError: MustBeNamespaceScopedResourceError{},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			restMapper := &mockRestMapper{}
+			restMapper.
+				On("RESTMapping", schema.GroupKind{Kind: "ConfigMap"}, mock.Anything).
+				Return(&meta.RESTMapping{Scope: meta.RESTScopeNamespace}, nil)
+			restMapper.
+				On("RESTMapping", mock.Anything, mock.Anything).
+				Return(&meta.RESTMapping{Scope: meta.RESTScopeRoot}, nil)
+
+			reader := testutil.NewClient()
+			reader.
+				On("Get", mock.Anything, client.ObjectKey{Name: "missing"}, mock.Anything, mock.Anything).
+				Return(apimachineryerrors.NewNotFound(schema.GroupResource{Resource: "namespaces"}, "missing"))
+			reader.
+				On("Get", mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+				Run(func(args mock.Arguments) {
+					ns := args.Get(2).(*corev1.Namespace)
+					ns.Labels = map[string]string{"team": args.Get(1).(client.ObjectKey).Name[len("team-"):]}
+				}).
+				Return(nil)
+
+			err := validateNamespace(t.Context(), restMapper, reader, policy, "owner", test.obj)
+			if test.expectedError == nil {
+				require.NoError(t, err)
+
+				return
+			}
+
+			assert.Equal(t, test.expectedError, err)
+		})
+	}
+}
+
+func TestScopedObjectValidator(t *testing.T) {
+	t.Parallel()
+
+	restMapper := &mockRestMapper{}
+	restMapper.
+		On("RESTMapping", mock.Anything, mock.Anything).
+		Return(&meta.RESTMapping{Scope: meta.RESTScopeNamespace}, nil)
+
+	writer := &mockWriter{}
+	writer.
+		On("Patch", mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+		Return(nil)
+
+	owner := &unstructured.Unstructured{}
+	owner.SetNamespace("owner")
+	owner.SetName("privileged")
+	owner.SetUID("1")
+
+	// Only the privileged owner may deploy into "shared".
+	v := NewScopedObjectValidator(restMapper, writer, nil,
+		func(_ context.Context, owner client.Object) (*ScopePolicy, error) {
+			if owner.GetName() != "privileged" {
+				return nil, nil
+			}
+
+			return &ScopePolicy{Namespaces: []string{"shared"}}, nil
+		})
+
+	obj := &unstructured.Unstructured{}
+	obj.SetAPIVersion("v1")
+	obj.SetKind("ConfigMap")
+	obj.SetName("test")
+	obj.SetNamespace("shared")
+
+	require.NoError(t, v.Validate(t.Context(), obj, types.WithOwner(owner, nil)))
+
+	owner.SetName("other")
+
+	err := v.Validate(t.Context(), obj, types.WithOwner(owner, nil))
+
+	var oerr *ObjectValidationError
+	require.ErrorAs(t, err, &oerr)
+	assert.Equal(t, []error{MustBeInNamespaceError{
+		ExpectedNamespace: "owner",
+		ActualNamespace:   "shared",
+	}}, oerr.Errors)
+
+	failing := NewScopedObjectValidator(restMapper, writer, nil,
+		func(context.Context, client.Object) (*ScopePolicy, error) {
+			return nil, errors.New("boom")
+		})
+	err = failing.Validate(t.Context(), obj, types.WithOwner(owner, nil))
+	require.ErrorContains(t, err, "boom")
+	assert.NotErrorAs(t, err, &oerr)
+}
@@ -0,0 +1,133 @@
+package validation
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"pkg.package-operator.run/boxcutter/machinery/types"
+)
+
+// SizeLimits configures size checks of RevisionValidator.
+// Size checks are disabled unless enabled via WithSizeLimits.
+// Zero values disable the respective check.
+type SizeLimits struct {
+	// MaxObjectBytes limits the JSON serialized size of each object.
+	MaxObjectBytes int
+	// MaxAnnotationBytes limits the total size of all annotation keys and values of an object.
+	MaxAnnotationBytes int
+	// MaxLabelBytes limits the total size of all label keys and values of an object.
+	MaxLabelBytes int
+	// MaxRevisionBytes limits the JSON serialized size of all objects in a revision.
+	MaxRevisionBytes int
+}
+
+// DefaultSizeLimits match the limits of a default kube-apiserver and etcd setup.
+var DefaultSizeLimits = SizeLimits{
+	// etcd --max-request-bytes default.
+	MaxObjectBytes: 1572864,
+	// kube-apiserver total annotation size limit.
+	MaxAnnotationBytes: 256 * 1024,
+}
+
+// WithSizeLimits enables size checks of a RevisionValidator,
+// e.g. WithSizeLimits(DefaultSizeLimits).
+type WithSizeLimits SizeLimits
+
+// ApplyToRevisionValidator implements RevisionValidatorOption.
+func (w WithSizeLimits) ApplyToRevisionValidator(v *RevisionValidator) {
+	v.sizeLimits = SizeLimits(w)
+}
+
+// SizeLimitExceededError is returned when an object or
+// revision exceeds a configured size limit.
+type SizeLimitExceededError struct {
+	// Subject of the limit, e.g. "object", "annotations", "labels" or "revision".
+	Subject string
+	// Size in bytes.
+	Size int
+	// Limit in bytes.
+	Limit int
+}
+
+// Error implements the error interface.
+func (e SizeLimitExceededError) Error() string {
+	return fmt.Sprintf("%s size of %d bytes exceeds limit of %d bytes", e.Subject, e.Size, e.Limit)
+}
+
+// validateObjectSize checks the object against the configured limits
+// and returns its serialized size.
+func (l SizeLimits) validateObjectSize(obj client.Object) (int, []error, error) {
+	b, err := json.Marshal(obj)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	var errs []error
+	if l.MaxObjectBytes > 0 && len(b) > l.MaxObjectBytes {
+		errs = append(errs, SizeLimitExceededError{
+			Subject: "object", Size: len(b), Limit: l.MaxObjectBytes,
+		})
+	}
+
+	if size := mapSize(obj.GetAnnotations()); l.MaxAnnotationBytes > 0 && size > l.MaxAnnotationBytes {
+		errs = append(errs, SizeLimitExceededError{
+			Subject: "annotations", Size: size, Limit: l.MaxAnnotationBytes,
+		})
+	}
+
+	if size := mapSize(obj.GetLabels()); l.MaxLabelBytes > 0 && size > l.MaxLabelBytes {
+		errs = append(errs, SizeLimitExceededError{
+			Subject: "labels", Size: size, Limit: l.MaxLabelBytes,
+		})
+	}
+
+	return len(b), errs, nil
+}
+
+// validatePhaseSize checks the size of all objects in the given phase.
+// Returns object errors and the total size of the phase.
+func (l SizeLimits) validatePhaseSize(phase types.Phase) ([]ObjectValidationError, int, error) {
+	var (
+		total        int
+		objectErrors []ObjectValidationError
+	)
+
+	for _, obj := range phase.GetObjects() {
+		size, errs, err := l.validateObjectSize(obj)
+		if err != nil {
+			return nil, 0, fmt.Errorf("validating size of %s: %w", types.ToObjectRef(obj), err)
+		}
+
+		total += size
+
+		if len(errs) > 0 {
+			objectErrors = append(objectErrors, ObjectValidationError{
+				ObjectRef: types.ToObjectRef(obj),
+				Errors:    errs,
+			})
+		}
+	}
+
+	return objectErrors, total, nil
+}
+
+func (l SizeLimits) validateTotalSize(total int) error {
+	if l.MaxRevisionBytes > 0 && total > l.MaxRevisionBytes {
+		return SizeLimitExceededError{
+			Subject: "revision", Size: total, Limit: l.MaxRevisionBytes,
+		}
+	}
+
+	return nil
+}
+
+func mapSize(m map[string]string) int {
+	var size int
+	for k, v := range m {
+		size += len(k) + len(v)
+	}
+
+	return size
+}
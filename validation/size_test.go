@@ -0,0 +1,99 @@
+package validation
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"pkg.package-operator.run/boxcutter/machinery/types"
+)
+
+func TestRevisionValidator_SizeLimits(t *testing.T) {
+	t.Parallel()
+
+	obj := func(name string, annotations, labels map[string]string) client.Object {
+		u := &unstructured.Unstructured{}
+		u.SetAPIVersion("v1")
+		u.SetKind("ConfigMap")
+		u.SetName(name)
+		u.SetNamespace("test")
+		u.SetAnnotations(annotations)
+		u.SetLabels(labels)
+
+		return u
+	}
+
+	small := obj("small", nil, nil)
+	annotated := obj("annotated", map[string]string{"a": strings.Repeat("x", 99)}, nil)
+	labeled := obj("labeled", nil, map[string]string{"a": strings.Repeat("x", 99)})
+
+	v := NewRevisionValidator(WithSizeLimits{
+		MaxObjectBytes:     150,
+		MaxAnnotationBytes: 50,
+		MaxLabelBytes:      50,
+		MaxRevisionBytes:   300,
+	})
+
+	err := v.Validate(t.Context(), types.NewRevision("test", 1, []types.Phase{
+		types.NewPhase("phase1", []client.Object{small}),
+		types.NewPhase("phase2", []client.Object{annotated, labeled}),
+	}))
+
+	var rerr *RevisionValidationError
+	require.ErrorAs(t, err, &rerr)
+
+	var revErr SizeLimitExceededError
+	require.ErrorAs(t, rerr.RevisionError, &revErr)
+	assert.Equal(t, "revision", revErr.Subject)
+	assert.Equal(t, 300, revErr.Limit)
+
+	require.Len(t, rerr.Phases, 1)
+	assert.Equal(t, "phase2", rerr.Phases[0].PhaseName)
+	require.Len(t, rerr.Phases[0].Objects, 2)
+
+	subjects := func(errs []error) []string {
+		var out []string
+
+		for _, err := range errs {
+			var serr SizeLimitExceededError
+			require.ErrorAs(t, err, &serr)
+
+			out = append(out, serr.Subject)
+		}
+
+		return out
+	}
+	assert.Equal(t, []string{"object", "annotations"}, subjects(rerr.Phases[0].Objects[0].Errors))
+	assert.Equal(t, []string{"object", "labels"}, subjects(rerr.Phases[0].Objects[1].Errors))
+}
+
+func TestRevisionValidator_DefaultSizeLimits(t *testing.T) {
+	t.Parallel()
+
+	huge := &unstructured.Unstructured{}
+	huge.SetAPIVersion("v1")
+	huge.SetKind("ConfigMap")
+	huge.SetName("huge")
+	huge.SetAnnotations(map[string]string{"a": strings.Repeat("x", DefaultSizeLimits.MaxAnnotationBytes)})
+
+	rev := types.NewRevision("test", 1, []types.Phase{
+		types.NewPhase("phase1", []client.Object{huge}),
+	})
+
+	// Size checks are opt-in.
+	require.NoError(t, NewRevisionValidator().Validate(t.Context(), rev))
+
+	err := NewRevisionValidator(WithSizeLimits(DefaultSizeLimits)).Validate(t.Context(), rev)
+
+	var serr SizeLimitExceededError
+	require.ErrorAs(t, err, &serr)
+	assert.Equal(t, "annotations", serr.Subject)
+	assert.Equal(t, []ErrorDetail{{
+		Code:    ErrorCodeSizeLimitExceeded,
+		Message: serr.Error(),
+	}}, ErrorDetails(serr))
+}
@@ -0,0 +1,74 @@
+package validation
+
+import (
+	"context"
+
+	"k8s.io/client-go/rest"
+
+	"pkg.package-operator.run/boxcutter/machinery/types"
+)
+
+// Warning is a non-fatal finding of a preflight check,
+// e.g. the use of a deprecated API version.
+type Warning = types.Warning
+
+// WarningRecorder collects warnings encountered during validation.
+// It is safe for concurrent use.
+type WarningRecorder = types.WarningRecorder
+
+// ContextWithWarningRecorder returns a new context, collecting
+// warnings of validators called with it into the given WarningRecorder.
+func ContextWithWarningRecorder(ctx context.Context, r *WarningRecorder) context.Context {
+	return types.ContextWithWarningRecorder(ctx, r)
+}
+
+// RecordWarning records the given warnings into the WarningRecorder of the context.
+// Warnings are dropped if the context has no WarningRecorder.
+func RecordWarning(ctx context.Context, warnings ...Warning) {
+	types.RecordWarning(ctx, warnings...)
+}
+
+// objectWarningSink attributes warnings of API requests to an object.
+type objectWarningSink struct {
+	ref      types.ObjectRef
+	recorder *WarningRecorder
+}
+
+type objectWarningSinkKey struct{}
+
+func contextWithObjectWarningSink(ctx context.Context, ref types.ObjectRef) context.Context {
+	r := types.WarningRecorderFromContext(ctx)
+	if r == nil {
+		return ctx
+	}
+
+	return context.WithValue(ctx, objectWarningSinkKey{}, objectWarningSink{ref: ref, recorder: r})
+}
+
+// DryRunWarningHandler captures Warning headers returned by the kube-apiserver
+// for dry run requests of the ObjectValidator and reports them as Warnings.
+// Install it as WarningHandlerWithContext into the rest.Config of the client used for dry runs.
+// Warnings of other requests are passed on to Next, if set.
+//
+// Dry runs skipped due to a DryRunCache hit replay the warnings of the cached dry run.
+type DryRunWarningHandler struct {
+	Next rest.WarningHandlerWithContext
+}
+
+var _ rest.WarningHandlerWithContext = DryRunWarningHandler{}
+
+// HandleWarningHeaderWithContext implements rest.WarningHandlerWithContext.
+func (h DryRunWarningHandler) HandleWarningHeaderWithContext(
+	ctx context.Context, code int, agent string, text string,
+) {
+	// Only 299 is defined as a warning code by the kube-apiserver.
+	if sink, ok := ctx.Value(objectWarningSinkKey{}).(objectWarningSink); ok && code == 299 {
+		sink.recorder.Record(Warning{ObjectRef: sink.ref, Message: text})
+
+		return
+	}
+
+	if h.Next != nil {
+		h.Next.HandleWarningHeaderWithContext(ctx, code, agent, text)
+	}
+}